@@ -20,6 +20,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	resourcev1 "k8s.io/api/resource/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // BuildArg represents a build argument used when building a container image.
@@ -186,6 +187,106 @@ type ModprobeSpec struct {
 	// In order to load all 3 modules, moduleA shoud be defined in the ModuleName parameter of this struct
 	// +optional
 	ModulesLoadingOrder []string `json:"modulesLoadingOrder,omitempty"`
+
+	// VerifySignature, if set, makes the worker verify the signature appended to the kernel module and to the
+	// dependencies it loads, before they reach the kernel.
+	// Unsigned modules and modules signed with another key are not loaded.
+	// This field can only be set if moduleName is set.
+	// +optional
+	VerifySignature *ModuleSignatureVerification `json:"verifySignature,omitempty"`
+
+	// Hooks are commands shipped in the module image that the worker runs before and after loading or unloading the
+	// kernel module.
+	// A failing hook fails the worker Pod.
+	// +optional
+	Hooks *ModprobeHooks `json:"hooks,omitempty"`
+
+	// UnloadPolicy defines what the worker does when the kernel module is still in use when it must be unloaded.
+	// This field can only be set if moduleName is set.
+	// +optional
+	UnloadPolicy *UnloadPolicy `json:"unloadPolicy,omitempty"`
+
+	// Livepatch, if set, makes the worker handle moduleName as a kernel livepatch module.
+	// After loading it, the worker waits for the patch transition to complete.
+	// Before unloading it, the worker disables the patch through /sys/kernel/livepatch and waits for the transition
+	// to complete.
+	// This field can only be set if moduleName is set.
+	// +optional
+	Livepatch *Livepatch `json:"livepatch,omitempty"`
+}
+
+type Livepatch struct {
+	// TransitionTimeoutSeconds is how long the worker waits for a patch transition to complete.
+	// Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TransitionTimeoutSeconds int32 `json:"transitionTimeoutSeconds,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Fail;Wait;Force
+type UnloadPolicyType string
+
+const (
+	// UnloadPolicyFail makes the unload fail immediately if the module is in use.
+	UnloadPolicyFail UnloadPolicyType = "Fail"
+	// UnloadPolicyWait makes the worker wait for the module to be released, up to a timeout.
+	UnloadPolicyWait UnloadPolicyType = "Wait"
+	// UnloadPolicyForce makes the worker unload the module even if it is in use, like rmmod -f.
+	// The kernel must have been built with CONFIG_MODULE_FORCE_UNLOAD.
+	UnloadPolicyForce UnloadPolicyType = "Force"
+)
+
+type UnloadPolicy struct {
+	// Type is the behavior when the module is in use.
+	// Modules that other loaded modules depend on cannot be unloaded, even with Force.
+	// +kubebuilder:default=Fail
+	// +optional
+	Type UnloadPolicyType `json:"type,omitempty"`
+
+	// TimeoutSeconds is how long the worker waits for the module to be released.
+	// Only used with the Wait type; defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+type ModprobeHooks struct {
+	// PreLoad runs before the kernel module is loaded, after in-tree modules were removed and firmware was copied.
+	// +optional
+	PreLoad *ModprobeHook `json:"preLoad,omitempty"`
+
+	// PostLoad runs after the kernel module was loaded.
+	// +optional
+	PostLoad *ModprobeHook `json:"postLoad,omitempty"`
+
+	// PreUnload runs before the kernel module is unloaded.
+	// +optional
+	PreUnload *ModprobeHook `json:"preUnload,omitempty"`
+
+	// PostUnload runs after the kernel module was unloaded.
+	// +optional
+	PostUnload *ModprobeHook `json:"postUnload,omitempty"`
+}
+
+type ModprobeHook struct {
+	// Command is the command to run.
+	// Its first element must be the absolute path of an executable in the module image; it is run in the worker
+	// container.
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// TimeoutSeconds is the maximum duration of the command.
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+type ModuleSignatureVerification struct {
+	// CertSecret is a reference to a Secret in the Module's namespace that holds the X.509 certificate the kernel
+	// modules were signed with, under the "cert" key.
+	// The certificate may be DER or PEM encoded.
+	CertSecret v1.LocalObjectReference `json:"certSecret"`
 }
 
 type ModuleLoaderContainerSpec struct {
@@ -237,6 +338,12 @@ type ModuleLoaderContainerSpec struct {
 	// InTreeModulesToRemove specifies any number of  in-tree kernel modules that should be removed (if present)
 	// before loading the kernel module from the ContainerImage
 	InTreeModulesToRemove []string `json:"inTreeModulesToRemove"`
+
+	// +optional
+	// BlacklistInTreeModules makes the worker blacklist the in-tree modules to remove on the node, in
+	// /etc/modprobe.d/kmm-<namespace>-<module>.conf, so that they are not loaded automatically when the node boots.
+	// The file is removed when the kernel module is unloaded.
+	BlacklistInTreeModules bool `json:"blacklistInTreeModules,omitempty"`
 }
 
 type ModuleLoaderSpec struct {
@@ -247,6 +354,74 @@ type ModuleLoaderSpec struct {
 	// ServiceAccountName is the name of the ServiceAccount to use to run this pod.
 	// More info: https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// RetryPolicy limits how often KMM retries loading or unloading the module on a node where it failed to.
+	// It overrides the retry policy of the operator configuration.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// DrainPolicy makes KMM cordon the node and evict the Pods that use the kernel module before unloading it,
+	// including before reloading it with a new config.
+	// The node is uncordoned once the module was unloaded or loaded again.
+	// +optional
+	DrainPolicy *DrainPolicy `json:"drainPolicy,omitempty"`
+
+	// MaintenanceWindows restrict when KMM may change the kernel module on nodes where it is already loaded: worker
+	// Pods that reload or unload the module, or set its parameters, are only created while one of the windows is
+	// open.
+	// The windows only apply to upgrades and unloads: the first load of the module on a node, for a new Module or a
+	// node that the Module newly targets, and loads after a node reboot or a kernel upgrade are not delayed.
+	// They override the maintenance windows of the operator configuration.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time during which KMM may disturb nodes.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with five fields (minute, hour, day of month, month and day of week), or one of
+	// @yearly, @monthly, @weekly, @daily and @hourly, at which the window opens.
+	// It is evaluated in UTC.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// DrainPolicy describes the Pods that must be evicted from a node before the kernel module is unloaded from it.
+// Pods that are managed by a DaemonSet and static Pods are never evicted.
+type DrainPolicy struct {
+	// PodSelector selects the Pods to evict, in all namespaces.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Resources are the names of extended resources, such as the one advertised by the Module's device plugin.
+	// Pods that request or are limited to any of them are evicted.
+	// +optional
+	Resources []v1.ResourceName `json:"resources,omitempty"`
+}
+
+// RetryPolicy limits how often KMM retries loading or unloading a module on a node where it failed to.
+type RetryPolicy struct {
+	// MaxAttempts is the number of consecutive failed load, or unload, attempts on a node after which KMM stops
+	// retrying, until the Module changes or its kmm.node.kubernetes.io/retry annotation is set to a new value.
+	// Unloads of a module that the Module no longer targets are only retried after the node reboots; unloads that
+	// failed because the module was in use are retried without limit.
+	// 0 means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// InitialBackoff is how long KMM waits after the first failure before recreating the worker Pod.
+	// The delay doubles after each consecutive failure.
+	// Defaults to 10s.
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+
+	// MaxBackoff is the maximum delay between two attempts.
+	// Defaults to 5m.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
 }
 
 type CommonContainerSpec struct {
@@ -406,6 +581,153 @@ type ModuleSpec struct {
 	// all module images.
 	// +optional
 	ImageRebuildTriggerGeneration *int `json:"imageRebuildTriggerGeneration,omitempty"`
+
+	// UpgradeStrategy limits how many nodes get a new module config at the same time, when the config of the module
+	// changes on nodes where it is already loaded.
+	// If not set, all nodes are updated at once.
+	// +optional
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// DependsOn lists the Modules that must be loaded on a node before this Module.
+	// On each node, KMM loads this Module once all its dependencies are loaded, and unloads them only after this
+	// Module was unloaded.
+	// +optional
+	DependsOn []ModuleReference `json:"dependsOn,omitempty"`
+}
+
+// ModuleReference identifies a Module.
+type ModuleReference struct {
+	// Name of the Module.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Module.
+	// Defaults to the namespace of the Module holding the reference.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// UpgradeStrategy describes how a new module config is rolled out to nodes where the module is already loaded.
+type UpgradeStrategy struct {
+	// MaxUnavailable is the maximum number of nodes that can be unavailable during the upgrade.
+	// A node is unavailable while the config in its NodeModulesConfig spec has not been loaded yet, and for
+	// MinReadySeconds after it was loaded.
+	// Value can be an absolute number (ex: 5) or a percentage of the nodes on which the module should be loaded
+	// (ex: 10%), rounded down.
+	// At least one node is upgraded at a time.
+	// Defaults to 1.
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MinReadySeconds is the number of seconds for which a node must have the new config loaded before it is
+	// considered available.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// Canary makes KMM roll out a new module config to a few nodes first, and only roll it out to the other nodes
+	// once it was healthy there for some time.
+	// If the canary fails, KMM rolls back to the last module config that was rolled out to all nodes.
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+}
+
+// CanaryStrategy describes the canary phase of a rollout.
+type CanaryStrategy struct {
+	// NodeSelector selects the canary nodes among the nodes on which the module config changes.
+	// If it matches none of them, the canary nodes are chosen as if it was not set.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Nodes is the number of canary nodes, if NodeSelector is not set or matches no node.
+	// Value can be an absolute number (ex: 2) or a percentage of the nodes on which the module should be loaded
+	// (ex: 10%), rounded up.
+	// Defaults to 1.
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	Nodes *intstr.IntOrString `json:"nodes,omitempty"`
+
+	// SoakSeconds is the number of seconds for which the canary nodes must stay healthy after the new config was
+	// loaded on all of them.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SoakSeconds int32 `json:"soakSeconds,omitempty"`
+
+	// Probe selects Pods, in the Module's namespace, that must be running and ready on every canary node for the
+	// canary to be healthy.
+	// +optional
+	Probe *metav1.LabelSelector `json:"probe,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Progressing;Soaking;Succeeded;Failed
+type CanaryPhase string
+
+const (
+	// CanaryProgressing means that the new config is being loaded on the canary nodes.
+	CanaryProgressing CanaryPhase = "Progressing"
+	// CanarySoaking means that the new config was loaded on all canary nodes, which must stay healthy for
+	// soakSeconds.
+	CanarySoaking CanaryPhase = "Soaking"
+	// CanarySucceeded means that the new config is being rolled out to the other nodes.
+	CanarySucceeded CanaryPhase = "Succeeded"
+	// CanaryFailed means that the new config was rolled back.
+	CanaryFailed CanaryPhase = "Failed"
+)
+
+// CanaryStatus reports the canary phase of the rollout of a Module generation.
+type CanaryStatus struct {
+	// Generation is the generation of the Module that is being rolled out.
+	Generation int64 `json:"generation"`
+	// Phase is the phase of the canary.
+	Phase CanaryPhase `json:"phase"`
+	// Nodes are the canary nodes.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+	// SoakStartTime is when the new config was loaded on all canary nodes.
+	// +optional
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+	// Message tells why the canary failed, or what it is waiting for.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RevisionConfig is the module config of a revision for the nodes that run the same kernel version and that the same
+// ModuleNodeOverrides apply to.
+type RevisionConfig struct {
+	ModuleConfig `json:",inline"`
+	// NodeOverrides are the names of the ModuleNodeOverrides that were merged into the config, in order.
+	// +optional
+	NodeOverrides []string `json:"nodeOverrides,omitempty"`
+}
+
+// ModuleRevision is a module config that was rolled out to all nodes.
+// It is stored in a ControllerRevision owned by the Module.
+type ModuleRevision struct {
+	// Generation is the generation of the Module that the revision was rolled out for.
+	Generation int64 `json:"generation"`
+	// Configs are the module configs of the revision, one per kernel version and set of ModuleNodeOverrides.
+	// +optional
+	Configs []RevisionConfig `json:"configs,omitempty"`
+}
+
+// UpgradeStatus reports the progress of an upgrade.
+type UpgradeStatus struct {
+	// number of nodes on which the NodeModulesConfig has the latest module config
+	UpdatedNumber int32 `json:"updatedNumber"`
+	// number of nodes that are still waiting for the latest module config
+	PendingNumber int32 `json:"pendingNumber"`
+	// number of nodes that are counted as unavailable towards maxUnavailable
+	UnavailableNumber int32 `json:"unavailableNumber"`
+	// Canary reports the canary phase of the rollout, if .spec.upgradeStrategy.canary is set.
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+	// Revision is the name of the ControllerRevision that holds the last module config rolled out to all nodes; KMM
+	// rolls back to it if a canary fails, and keeps its images in the ModuleImagesConfig until it is superseded.
+	// +optional
+	Revision string `json:"revision,omitempty"`
 }
 
 // DaemonSetStatus contains the status for a daemonset deployed during
@@ -417,6 +739,26 @@ type DaemonSetStatus struct {
 	DesiredNumber int32 `json:"desiredNumber,omitempty"`
 	// number of the actually deployed and running pods
 	AvailableNumber int32 `json:"availableNumber,omitempty"`
+	// number of nodes on which the kernel module tainted the kernel
+	TaintedNumber int32 `json:"taintedNumber,omitempty"`
+}
+
+// KernelStatus reports the status of a Module on the targeted nodes that run the same kernel version.
+type KernelStatus struct {
+	// KernelVersion is the kernel version of the nodes.
+	KernelVersion string `json:"kernelVersion"`
+	// NodesNumber is the number of targeted nodes that run the kernel version.
+	NodesNumber int32 `json:"nodesNumber"`
+	// Image is the module image for this kernel version; it is empty if no kernel mapping matches it.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// ImageState is the state of Image in the ModuleImagesConfig.
+	// +optional
+	ImageState ImageState `json:"imageState,omitempty"`
+	// LoadedNumber is the number of nodes on which the kernel module is loaded with the latest module config.
+	LoadedNumber int32 `json:"loadedNumber"`
+	// FailedNumber is the number of nodes on which the kernel module failed to load or unload.
+	FailedNumber int32 `json:"failedNumber"`
 }
 
 // ModuleStatus defines the observed state of Module.
@@ -433,8 +775,41 @@ type ModuleStatus struct {
 	// When this differs from spec.imageRebuildTriggerGeneration, all module images will be re-verified and potentially rebuilt.
 	// +optional
 	ImageRebuildTriggerGeneration *int `json:"imageRebuildTriggerGeneration,omitempty"`
+	// Upgrade reports the progress of the rollout of the module config to nodes, if .spec.upgradeStrategy is set.
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// Kernels reports the status of the Module for each kernel version that the targeted nodes run.
+	// +optional
+	// +listType=map
+	// +listMapKey=kernelVersion
+	Kernels []KernelStatus `json:"kernels,omitempty"`
+	// Conditions represent the latest available observations of the Module's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ModuleConditionConflict is True if another Module uses the same kernel module on some of the nodes that the
+	// Module targets.
+	ModuleConditionConflict = "Conflict"
+	// ModuleConditionImagesReady is True once the images of the Module exist for the kernels of all targeted nodes.
+	ModuleConditionImagesReady = "ImagesReady"
+	// ModuleConditionBuildFailed is True if the in-cluster build of some images of the Module failed.
+	ModuleConditionBuildFailed = "BuildFailed"
+	// ModuleConditionSignFailed is True if the in-cluster signing of some images of the Module failed.
+	ModuleConditionSignFailed = "SignFailed"
+	// ModuleConditionProgressing is True while the kernel module is being built, signed, or loaded on nodes.
+	ModuleConditionProgressing = "Progressing"
+	// ModuleConditionAvailable is True once the kernel module is loaded with its current config on all the nodes that
+	// it should run on.
+	ModuleConditionAvailable = "Available"
+	// ModuleConditionDegraded is True if the kernel module failed to load or unload on some nodes, if its images could
+	// not be built or signed, or if its canary failed.
+	ModuleConditionDegraded = "Degraded"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:scope=Namespaced
 //+kubebuilder:subresource:status
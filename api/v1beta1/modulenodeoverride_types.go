@@ -0,0 +1,84 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ModuleNodeOverrideSpec describes the modprobe settings that replace or extend those of a Module on some nodes.
+type ModuleNodeOverrideSpec struct {
+	// ModuleName is the name of the Module, in the same namespace, that the override applies to.
+	// +kubebuilder:validation:MinLength=1
+	ModuleName string `json:"moduleName"`
+
+	// Selector selects the nodes that the override applies to, among the nodes targeted by the Module.
+	// The override applies to all nodes targeted by the Module if it is empty.
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// Priority orders the overrides that apply to the same node: they are merged by increasing priority, then by
+	// name, so that the settings of the last override win.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Parameters replace the kernel module parameters of the Module.
+	// +optional
+	Parameters []string `json:"parameters,omitempty"`
+
+	// ExtraParameters are appended to the kernel module parameters of the Module.
+	// +optional
+	ExtraParameters []string `json:"extraParameters,omitempty"`
+
+	// FirmwarePath replaces the firmware path of the Module.
+	// +optional
+	FirmwarePath string `json:"firmwarePath,omitempty"`
+
+	// Args replace the modprobe arguments of the Module.
+	// +optional
+	Args *ModprobeArgs `json:"args,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ModuleNodeOverride replaces or extends the modprobe settings of a Module on the nodes matching a selector.
+// +kubebuilder:resource:path=modulenodeoverrides,scope=Namespaced,shortName=mno
+// +kubebuilder:printcolumn:name="Module",type=string,JSONPath=`.spec.moduleName`
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +operator-sdk:csv:customresourcedefinitions:displayName="Module Node Override"
+type ModuleNodeOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ModuleNodeOverrideSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ModuleNodeOverrideList is a list of ModuleNodeOverride objects.
+type ModuleNodeOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of ModuleNodeOverride. More info:
+	// https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md
+	Items []ModuleNodeOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ModuleNodeOverride{}, &ModuleNodeOverrideList{})
+}
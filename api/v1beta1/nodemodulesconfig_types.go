@@ -32,8 +32,11 @@ type ModuleConfig struct {
 	//+optional
 	InTreeModulesToRemove []string `json:"inTreeModulesToRemove,omitempty"`
 	//+optional
-	InTreeModuleToRemove string       `json:"inTreeModuleToRemove,omitempty"`
-	Modprobe             ModprobeSpec `json:"modprobe"`
+	InTreeModuleToRemove string `json:"inTreeModuleToRemove,omitempty"`
+	// BlacklistInTreeModules makes the worker blacklist InTreeModulesToRemove on the node.
+	//+optional
+	BlacklistInTreeModules bool         `json:"blacklistInTreeModules,omitempty"`
+	Modprobe               ModprobeSpec `json:"modprobe"`
 }
 
 type ModuleItem struct {
@@ -47,12 +50,28 @@ type ModuleItem struct {
 	//+optional
 	// Version is the version of the kernel module that should be loaded
 	Version string `json:"version,omitempty"`
+	//+optional
+	// DrainPolicy describes the Pods to evict from the node before the kernel module is unloaded
+	DrainPolicy *DrainPolicy `json:"drainPolicy,omitempty"`
+	//+optional
+	// MaintenanceWindows restrict when the kernel module may be reloaded or unloaded, or its parameters changed
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	//+optional
+	// DependsOn lists the modules that must be loaded on the node before this module, and unloaded after it
+	DependsOn []ModuleReference `json:"dependsOn,omitempty"`
+	// RetryPolicy is the retry policy of the Module, if it overrides the one of the operator configuration.
+	//+optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 }
 
 type NodeModuleSpec struct {
 	ModuleItem `json:",inline"`
 
 	Config ModuleConfig `json:"config"`
+	// RetryToken is the value of the kmm.node.kubernetes.io/retry annotation of the Module.
+	// Changing it resets the number of failed load and unload attempts.
+	//+optional
+	RetryToken string `json:"retryToken,omitempty"`
 }
 
 // NodeModulesConfigSpec describes the desired state of modules on the node
@@ -71,6 +90,178 @@ type NodeModuleStatus struct {
 	Config ModuleConfig `json:"config,omitempty"`
 	//+optional
 	BootId string `json:"bootId,omitempty"`
+	// LastFailure describes the last failure of a worker Pod for this module.
+	// It is cleared when the module is successfully loaded.
+	//+optional
+	LastFailure *WorkerFailure `json:"lastFailure,omitempty"`
+	// LoadedModules are the kernel modules that were loaded on the node after the worker loaded this module, including
+	// its dependencies.
+	//+optional
+	LoadedModules []LoadedKernelModule `json:"loadedModules,omitempty"`
+	// Parameters are the parameters of the kernel module, as read from sysfs after it was loaded or after its
+	// parameters were last changed at runtime.
+	// Parameters that are not readable are not listed.
+	//+optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// FirmwareFiles are the firmware files that the worker copied to the node, relative to the firmware directory.
+	//+optional
+	FirmwareFiles []string `json:"firmwareFiles,omitempty"`
+	// BlacklistedModules are the in-tree kernel modules that the worker blacklisted on the node when it loaded this
+	// module.
+	//+optional
+	BlacklistedModules []string `json:"blacklistedModules,omitempty"`
+	// Livepatch is the state of the kernel livepatch after the worker loaded this module, if it is a livepatch module.
+	//+optional
+	Livepatch *LivepatchStatus `json:"livepatch,omitempty"`
+	// Taint are the taint flags of the kernel after the worker loaded this module.
+	//+optional
+	Taint *KernelTaint `json:"taint,omitempty"`
+	// LoadDuration is how long the worker took to load the kernel module.
+	//+optional
+	LoadDuration *metav1.Duration `json:"loadDuration,omitempty"`
+	// Conditions describe the state of the module on the node, as observed from the worker Pods.
+	//+optional
+	//+listType=map
+	//+listMapKey=type
+	Conditions []NodeModuleCondition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Progressing;Loaded;LoadFailed;UnloadFailed;RebootRequired;MaintenanceWindowPending;DependenciesPending
+type NodeModuleConditionType string
+
+const (
+	// NodeModuleConditionProgressing is True while a worker Pod is loading or unloading the module, or setting its
+	// parameters.
+	NodeModuleConditionProgressing NodeModuleConditionType = "Progressing"
+	// NodeModuleConditionLoaded is True once the module was loaded with the config in the status.
+	NodeModuleConditionLoaded NodeModuleConditionType = "Loaded"
+	// NodeModuleConditionLoadFailed is True if the last attempt to load the module failed.
+	NodeModuleConditionLoadFailed NodeModuleConditionType = "LoadFailed"
+	// NodeModuleConditionUnloadFailed is True if the last attempt to unload the module failed.
+	NodeModuleConditionUnloadFailed NodeModuleConditionType = "UnloadFailed"
+	// NodeModuleConditionRebootRequired is True if a new config cannot be applied because the loaded module is in
+	// use; it is applied once the module is released or the node is rebooted.
+	NodeModuleConditionRebootRequired NodeModuleConditionType = "RebootRequired"
+	// NodeModuleConditionMaintenanceWindowPending is True if a change to the module is held back until the next
+	// maintenance window opens.
+	NodeModuleConditionMaintenanceWindowPending NodeModuleConditionType = "MaintenanceWindowPending"
+	// NodeModuleConditionDependenciesPending is True if the module is not loaded until the modules it depends on are
+	// loaded, or not unloaded until the modules that depend on it are unloaded.
+	NodeModuleConditionDependenciesPending NodeModuleConditionType = "DependenciesPending"
+)
+
+type NodeModuleCondition struct {
+	// Type of the condition.
+	Type NodeModuleConditionType `json:"type"`
+	// Status of the condition, one of True, False or Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status metav1.ConditionStatus `json:"status"`
+	// Reason is a CamelCase reason for the condition's last transition.
+	//+optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message about the last transition.
+	//+optional
+	Message string `json:"message,omitempty"`
+	// FailureCount is the number of consecutive worker failures for LoadFailed and UnloadFailed.
+	//+optional
+	FailureCount int32 `json:"failureCount,omitempty"`
+	// LastTransitionTime is the last time the condition changed from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+type LivepatchStatus struct {
+	// Enabled is true if the patch is enabled, as reported in /sys/kernel/livepatch/<name>/enabled.
+	Enabled bool `json:"enabled"`
+	// Transition is true if the patch is still being applied to or removed from the running tasks, as reported in
+	// /sys/kernel/livepatch/<name>/transition.
+	Transition bool `json:"transition"`
+}
+
+// TaintFlag is a kernel taint flag, such as OutOfTreeModule or UnsignedModule.
+type TaintFlag string
+
+type KernelTaint struct {
+	// Kernel are the taint flags of the running kernel, as decoded from /proc/sys/kernel/tainted.
+	// They may have been set by other modules.
+	//+optional
+	Kernel []TaintFlag `json:"kernel,omitempty"`
+	// Module are the taint flags that this kernel module set, as decoded from /sys/module/<name>/taint.
+	//+optional
+	Module []TaintFlag `json:"module,omitempty"`
+}
+
+type LoadedKernelModule struct {
+	// Name is the name of the kernel module.
+	Name string `json:"name"`
+	// SrcVersion is the checksum of the module's source code, as reported in /sys/module/<name>/srcversion.
+	//+optional
+	SrcVersion string `json:"srcVersion,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Load;SetParameters;Unload
+type WorkerAction string
+
+const (
+	WorkerActionLoad          WorkerAction = "Load"
+	WorkerActionSetParameters WorkerAction = "SetParameters"
+	WorkerActionUnload        WorkerAction = "Unload"
+)
+
+// +kubebuilder:validation:Enum=Error;ArchMismatch;FirmwareConflict;HookFailed;InvalidModule;InvalidSignature;KernelMismatch;LivepatchTransition;MissingDependency;ModuleInUse;ModuleNotFound;ParameterNotWritable;UnknownSymbol
+type WorkerFailureReason string
+
+const (
+	// WorkerFailureReasonError is used for errors that could not be classified.
+	WorkerFailureReasonError WorkerFailureReason = "Error"
+	// WorkerFailureReasonArchMismatch means that the module was built for another architecture.
+	WorkerFailureReasonArchMismatch WorkerFailureReason = "ArchMismatch"
+	// WorkerFailureReasonFirmwareConflict means that another Module installed a firmware file with the same path and
+	// different contents.
+	WorkerFailureReasonFirmwareConflict WorkerFailureReason = "FirmwareConflict"
+	// WorkerFailureReasonHookFailed means that a lifecycle hook failed or timed out.
+	WorkerFailureReasonHookFailed WorkerFailureReason = "HookFailed"
+	// WorkerFailureReasonInvalidModule means that the module file is malformed.
+	WorkerFailureReasonInvalidModule WorkerFailureReason = "InvalidModule"
+	// WorkerFailureReasonInvalidSignature means that the module signature is missing, malformed or was rejected.
+	WorkerFailureReasonInvalidSignature WorkerFailureReason = "InvalidSignature"
+	// WorkerFailureReasonKernelMismatch means that the module was built for another kernel.
+	WorkerFailureReasonKernelMismatch WorkerFailureReason = "KernelMismatch"
+	// WorkerFailureReasonLivepatchTransition means that a livepatch transition did not complete in time.
+	WorkerFailureReasonLivepatchTransition WorkerFailureReason = "LivepatchTransition"
+	// WorkerFailureReasonMissingDependency means that a module dependency is neither loaded nor in the image.
+	WorkerFailureReasonMissingDependency WorkerFailureReason = "MissingDependency"
+	// WorkerFailureReasonModuleInUse means that the module could not be unloaded because it is in use.
+	// KMM does not restart the worker immediately; it retries the unload periodically.
+	WorkerFailureReasonModuleInUse WorkerFailureReason = "ModuleInUse"
+	// WorkerFailureReasonModuleNotFound means that the module was not found in the image.
+	WorkerFailureReasonModuleNotFound WorkerFailureReason = "ModuleNotFound"
+	// WorkerFailureReasonParameterNotWritable means that a module parameter cannot be changed without reloading the
+	// module.
+	WorkerFailureReasonParameterNotWritable WorkerFailureReason = "ParameterNotWritable"
+	// WorkerFailureReasonUnknownSymbol means that the module references a symbol that the kernel does not export.
+	WorkerFailureReasonUnknownSymbol WorkerFailureReason = "UnknownSymbol"
+)
+
+// WorkerFailure describes a worker container that exited with an error.
+type WorkerFailure struct {
+	// Action is what the worker was doing when it failed.
+	Action WorkerAction `json:"action"`
+	// Reason classifies the failure.
+	Reason WorkerFailureReason `json:"reason"`
+	// ExitCode is the exit code of the worker container.
+	ExitCode int32 `json:"exitCode"`
+	// Message is the error reported by the worker.
+	//+optional
+	Message string `json:"message,omitempty"`
+	// RestartCount is the number of times the worker container has been restarted.
+	//+optional
+	RestartCount int32 `json:"restartCount,omitempty"`
+	// Time is when the worker container terminated.
+	Time metav1.Time `json:"time"`
+	// SpecHash identifies the config and the retry token that a failed loader worker was applying.
+	// The number of failed load attempts is reset when they change.
+	//+optional
+	SpecHash string `json:"specHash,omitempty"`
 }
 
 // NodeModuleConfigStatus is the most recently observed status of the KMM modules on node.
@@ -82,6 +273,47 @@ type NodeModulesConfigStatus struct {
 	// +patchStrategy=merge
 	// +optional
 	Modules []NodeModuleStatus `json:"modules,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// Drain describes the last drain of the node that KMM performed before unloading modules.
+	// +optional
+	Drain *NodeDrainStatus `json:"drain,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Evicting;Drained;Completed
+type NodeDrainPhase string
+
+const (
+	// NodeDrainEvicting means that the node is cordoned and that KMM is evicting Pods from it.
+	NodeDrainEvicting NodeDrainPhase = "Evicting"
+	// NodeDrainDrained means that all Pods were evicted and that the worker Pods can unload the modules.
+	NodeDrainDrained NodeDrainPhase = "Drained"
+	// NodeDrainCompleted means that the modules were unloaded or loaded again, and that the node was uncordoned.
+	NodeDrainCompleted NodeDrainPhase = "Completed"
+)
+
+// NodeDrainModule identifies a module for which the node was drained.
+type NodeDrainModule struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// NodeDrainStatus describes the drain of a node before modules are unloaded from it.
+type NodeDrainStatus struct {
+	Phase NodeDrainPhase `json:"phase"`
+	// Modules are the modules for which the node was drained.
+	//+optional
+	Modules []NodeDrainModule `json:"modules,omitempty"`
+	// Cordoned is true if KMM cordoned the node, and will uncordon it once the drain is completed.
+	// It is false if the node was already unschedulable.
+	//+optional
+	Cordoned bool `json:"cordoned,omitempty"`
+	// RemainingPods is the number of Pods that still need to be evicted.
+	//+optional
+	RemainingPods int32 `json:"remainingPods,omitempty"`
+	//+optional
+	Message   string      `json:"message,omitempty"`
+	StartTime metav1.Time `json:"startTime"`
+	//+optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
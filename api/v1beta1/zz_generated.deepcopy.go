@@ -23,7 +23,9 @@ package v1beta1
 import (
 	"k8s.io/api/core/v1"
 	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -99,6 +101,62 @@ func (in *BuildSignImageState) DeepCopy() *BuildSignImageState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakStartTime != nil {
+		in, out := &in.SoakStartTime, &out.SoakStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
+func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonContainerSpec) DeepCopyInto(out *CommonContainerSpec) {
 	*out = *in
@@ -256,6 +314,31 @@ func (in *DevicePluginSpec) DeepCopy() *DevicePluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainPolicy) DeepCopyInto(out *DrainPolicy) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainPolicy.
+func (in *DrainPolicy) DeepCopy() *DrainPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KanikoParams) DeepCopyInto(out *KanikoParams) {
 	*out = *in
@@ -306,6 +389,107 @@ func (in *KernelMapping) DeepCopy() *KernelMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelStatus) DeepCopyInto(out *KernelStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelStatus.
+func (in *KernelStatus) DeepCopy() *KernelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelTaint) DeepCopyInto(out *KernelTaint) {
+	*out = *in
+	if in.Kernel != nil {
+		in, out := &in.Kernel, &out.Kernel
+		*out = make([]TaintFlag, len(*in))
+		copy(*out, *in)
+	}
+	if in.Module != nil {
+		in, out := &in.Module, &out.Module
+		*out = make([]TaintFlag, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelTaint.
+func (in *KernelTaint) DeepCopy() *KernelTaint {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelTaint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Livepatch) DeepCopyInto(out *Livepatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Livepatch.
+func (in *Livepatch) DeepCopy() *Livepatch {
+	if in == nil {
+		return nil
+	}
+	out := new(Livepatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LivepatchStatus) DeepCopyInto(out *LivepatchStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LivepatchStatus.
+func (in *LivepatchStatus) DeepCopy() *LivepatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LivepatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadedKernelModule) DeepCopyInto(out *LoadedKernelModule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadedKernelModule.
+func (in *LoadedKernelModule) DeepCopy() *LoadedKernelModule {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadedKernelModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModprobeArgs) DeepCopyInto(out *ModprobeArgs) {
 	*out = *in
@@ -331,6 +515,61 @@ func (in *ModprobeArgs) DeepCopy() *ModprobeArgs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModprobeHook) DeepCopyInto(out *ModprobeHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeHook.
+func (in *ModprobeHook) DeepCopy() *ModprobeHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ModprobeHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModprobeHooks) DeepCopyInto(out *ModprobeHooks) {
+	*out = *in
+	if in.PreLoad != nil {
+		in, out := &in.PreLoad, &out.PreLoad
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostLoad != nil {
+		in, out := &in.PostLoad, &out.PostLoad
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreUnload != nil {
+		in, out := &in.PreUnload, &out.PreUnload
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostUnload != nil {
+		in, out := &in.PostUnload, &out.PostUnload
+		*out = new(ModprobeHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeHooks.
+func (in *ModprobeHooks) DeepCopy() *ModprobeHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ModprobeHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModprobeSpec) DeepCopyInto(out *ModprobeSpec) {
 	*out = *in
@@ -354,6 +593,26 @@ func (in *ModprobeSpec) DeepCopyInto(out *ModprobeSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.VerifySignature != nil {
+		in, out := &in.VerifySignature, &out.VerifySignature
+		*out = new(ModuleSignatureVerification)
+		**out = **in
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ModprobeHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnloadPolicy != nil {
+		in, out := &in.UnloadPolicy, &out.UnloadPolicy
+		*out = new(UnloadPolicy)
+		**out = **in
+	}
+	if in.Livepatch != nil {
+		in, out := &in.Livepatch, &out.Livepatch
+		*out = new(Livepatch)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeSpec.
@@ -726,6 +985,26 @@ func (in *ModuleItem) DeepCopyInto(out *ModuleItem) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DrainPolicy != nil {
+		in, out := &in.DrainPolicy, &out.DrainPolicy
+		*out = new(DrainPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]ModuleReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleItem.
@@ -813,6 +1092,21 @@ func (in *ModuleLoaderContainerSpec) DeepCopy() *ModuleLoaderContainerSpec {
 func (in *ModuleLoaderSpec) DeepCopyInto(out *ModuleLoaderSpec) {
 	*out = *in
 	in.Container.DeepCopyInto(&out.Container)
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainPolicy != nil {
+		in, out := &in.DrainPolicy, &out.DrainPolicy
+		*out = new(DrainPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleLoaderSpec.
@@ -825,6 +1119,154 @@ func (in *ModuleLoaderSpec) DeepCopy() *ModuleLoaderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleNodeOverride) DeepCopyInto(out *ModuleNodeOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleNodeOverride.
+func (in *ModuleNodeOverride) DeepCopy() *ModuleNodeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleNodeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModuleNodeOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleNodeOverrideList) DeepCopyInto(out *ModuleNodeOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModuleNodeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleNodeOverrideList.
+func (in *ModuleNodeOverrideList) DeepCopy() *ModuleNodeOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleNodeOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModuleNodeOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleNodeOverrideSpec) DeepCopyInto(out *ModuleNodeOverrideSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraParameters != nil {
+		in, out := &in.ExtraParameters, &out.ExtraParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = new(ModprobeArgs)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleNodeOverrideSpec.
+func (in *ModuleNodeOverrideSpec) DeepCopy() *ModuleNodeOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleNodeOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleReference) DeepCopyInto(out *ModuleReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleReference.
+func (in *ModuleReference) DeepCopy() *ModuleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleRevision) DeepCopyInto(out *ModuleRevision) {
+	*out = *in
+	if in.Configs != nil {
+		in, out := &in.Configs, &out.Configs
+		*out = make([]RevisionConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleRevision.
+func (in *ModuleRevision) DeepCopy() *ModuleRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleSignatureVerification) DeepCopyInto(out *ModuleSignatureVerification) {
+	*out = *in
+	out.CertSecret = in.CertSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleSignatureVerification.
+func (in *ModuleSignatureVerification) DeepCopy() *ModuleSignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleSignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 	*out = *in
@@ -867,6 +1309,16 @@ func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]ModuleReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleSpec.
@@ -890,6 +1342,23 @@ func (in *ModuleStatus) DeepCopyInto(out *ModuleStatus) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kernels != nil {
+		in, out := &in.Kernels, &out.Kernels
+		*out = make([]KernelStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleStatus.
@@ -902,6 +1371,62 @@ func (in *ModuleStatus) DeepCopy() *ModuleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainModule) DeepCopyInto(out *NodeDrainModule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainModule.
+func (in *NodeDrainModule) DeepCopy() *NodeDrainModule {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainStatus) DeepCopyInto(out *NodeDrainStatus) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]NodeDrainModule, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainStatus.
+func (in *NodeDrainStatus) DeepCopy() *NodeDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeModuleCondition) DeepCopyInto(out *NodeModuleCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModuleCondition.
+func (in *NodeModuleCondition) DeepCopy() *NodeModuleCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeModuleCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeModuleSpec) DeepCopyInto(out *NodeModuleSpec) {
 	*out = *in
@@ -924,6 +1449,55 @@ func (in *NodeModuleStatus) DeepCopyInto(out *NodeModuleStatus) {
 	*out = *in
 	in.ModuleItem.DeepCopyInto(&out.ModuleItem)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.LastFailure != nil {
+		in, out := &in.LastFailure, &out.LastFailure
+		*out = new(WorkerFailure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadedModules != nil {
+		in, out := &in.LoadedModules, &out.LoadedModules
+		*out = make([]LoadedKernelModule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FirmwareFiles != nil {
+		in, out := &in.FirmwareFiles, &out.FirmwareFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlacklistedModules != nil {
+		in, out := &in.BlacklistedModules, &out.BlacklistedModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Livepatch != nil {
+		in, out := &in.Livepatch, &out.Livepatch
+		*out = new(LivepatchStatus)
+		**out = **in
+	}
+	if in.Taint != nil {
+		in, out := &in.Taint, &out.Taint
+		*out = new(KernelTaint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadDuration != nil {
+		in, out := &in.LoadDuration, &out.LoadDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]NodeModuleCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModuleStatus.
@@ -1027,6 +1601,11 @@ func (in *NodeModulesConfigStatus) DeepCopyInto(out *NodeModulesConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(NodeDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModulesConfigStatus.
@@ -1129,6 +1708,52 @@ func (in *PreflightValidationStatus) DeepCopy() *PreflightValidationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionConfig) DeepCopyInto(out *RevisionConfig) {
+	*out = *in
+	in.ModuleConfig.DeepCopyInto(&out.ModuleConfig)
+	if in.NodeOverrides != nil {
+		in, out := &in.NodeOverrides, &out.NodeOverrides
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionConfig.
+func (in *RevisionConfig) DeepCopy() *RevisionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sign) DeepCopyInto(out *Sign) {
 	*out = *in
@@ -1174,3 +1799,79 @@ func (in *TLSOptions) DeepCopy() *TLSOptions {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnloadPolicy) DeepCopyInto(out *UnloadPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnloadPolicy.
+func (in *UnloadPolicy) DeepCopy() *UnloadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UnloadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStrategy) DeepCopyInto(out *UpgradeStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategy.
+func (in *UpgradeStrategy) DeepCopy() *UpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerFailure) DeepCopyInto(out *WorkerFailure) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerFailure.
+func (in *WorkerFailure) DeepCopy() *WorkerFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerFailure)
+	in.DeepCopyInto(out)
+	return out
+}
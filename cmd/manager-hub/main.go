@@ -111,7 +111,7 @@ func main() {
 
 	client := mgr.GetClient()
 
-	nmcHelper := nmc.NewHelper(client)
+	nmcHelper := nmc.NewHelper(client, cfg.ModuleConflictPolicy)
 	filterAPI := filter.New(client, nmcHelper)
 
 	metricsAPI := metrics.New()
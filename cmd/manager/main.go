@@ -120,7 +120,7 @@ func main() {
 
 	client := mgr.GetClient()
 
-	nmcHelper := nmc.NewHelper(client)
+	nmcHelper := nmc.NewHelper(client, cfg.ModuleConflictPolicy)
 	filterAPI := filter.New(client, nmcHelper)
 
 	metricsAPI := metrics.New()
@@ -144,6 +144,8 @@ func main() {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.DevicePluginReconcilerName)
 	}
 
+	eventRecorder := mgr.GetEventRecorderFor("kmm")
+
 	mnc := controllers.NewModuleReconciler(
 		client,
 		kernelAPI,
@@ -151,14 +153,15 @@ func main() {
 		filterAPI,
 		nodeAPI,
 		micAPI,
+		mbscAPI,
+		eventRecorder,
 		scheme,
+		cfg.ModuleConflictPolicy,
 	)
 	if err = mnc.SetupWithManager(mgr); err != nil {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.ModuleReconcilerName)
 	}
 
-	eventRecorder := mgr.GetEventRecorderFor("kmm")
-
 	workerPodManagerAPI := pod.NewWorkerPodManager(client, workerImage, scheme, &cfg.Worker)
 	if err = controllers.NewNMCReconciler(client, scheme, workerImage, &cfg.Worker, eventRecorder, nodeAPI,
 		workerPodManagerAPI).SetupWithManager(ctx, mgr); err != nil {
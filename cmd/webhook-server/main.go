@@ -42,7 +42,7 @@ func main() {
 	)
 
 	flag.StringVar(&userConfigMapName, "config", "", "Name of the ConfigMap containing user config.")
-	flag.BoolVar(&enableModule, "enable-module", false, "Enable the webhook for Module resources")
+	flag.BoolVar(&enableModule, "enable-module", false, "Enable the webhooks for Module and ModuleNodeOverride resources")
 	flag.BoolVar(&enableManagedClusterModule, "enable-managedclustermodule", false, "Enable the webhook for ManagedClusterModule resources")
 	flag.BoolVar(&enableNamespaceDeletion, "enable-namespace", false, "Enable the webhook for Namespace deletion")
 	flag.BoolVar(&enablePreflightValidation, "enable-preflightvalidation", false, "Enable the webhook for PreflightValidation resources")
@@ -92,9 +92,13 @@ func main() {
 
 		setupLogger.Info("Detected Kubernetes version", "major", kubeVersion.Major, "minor", kubeVersion.Minor)
 
-		if err = webhook.NewModuleValidator(logger, &kubeVersion).SetupWebhookWithManager(mgr); err != nil {
+		if err = webhook.NewModuleValidator(logger, &kubeVersion, mgr.GetAPIReader(), cfg.ModuleConflictPolicy).SetupWebhookWithManager(mgr); err != nil {
 			cmd.FatalError(setupLogger, err, "unable to create webhook", "webhook", "ModuleValidator")
 		}
+
+		if err = webhook.NewModuleNodeOverrideValidator(logger).SetupWebhookWithManager(mgr); err != nil {
+			cmd.FatalError(setupLogger, err, "unable to create webhook", "webhook", "ModuleNodeOverrideValidator")
+		}
 	}
 
 	if enableManagedClusterModule {
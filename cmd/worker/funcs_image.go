@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
+	cp "github.com/otiai10/copy"
+	"github.com/spf13/cobra"
+)
+
+func imagePullFunc(cmd *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	var keychain authn.Keychain = authn.DefaultKeychain
+
+	if _, err = os.Stat(worker.PullSecretsDir); err == nil {
+		if keychain, err = worker.ReadKubernetesSecrets(cmd.Context(), worker.PullSecretsDir, logger); err != nil {
+			return fmt.Errorf("could not read the pull secrets: %v", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not check if %s exists: %v", worker.PullSecretsDir, err)
+	}
+
+	ip := worker.NewImagePuller(worker.ImagesDir, worker.ImagesCacheMaxSize, keychain, logger)
+
+	dir, err := ip.PullImage(cmd.Context(), cfg.ContainerImage, cfg.InsecurePull, worker.ImagePaths(cfg))
+	if err != nil {
+		return fmt.Errorf("could not pull image %s: %v", cfg.ContainerImage, err)
+	}
+
+	outputDir := cmd.Flags().Lookup(worker.FlagOutputDir).Value.String()
+
+	// The cache lives on the host and may be pruned by other workers once this one has exited, so the files are copied
+	// to the directory shared with the worker container, which is removed with the Pod.
+	logger.Info("Copying image files", "source", dir, "destination", outputDir)
+
+	if err = cp.Copy(dir, outputDir); err != nil {
+		return fmt.Errorf("could not copy %s to %s: %v", dir, outputDir, err)
+	}
+
+	return nil
+}
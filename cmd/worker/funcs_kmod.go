@@ -1,12 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	kmmcmd "github.com/kubernetes-sigs/kernel-module-management/internal/cmd"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func rootFuncPreRunE(cmd *cobra.Command, args []string) error {
@@ -17,9 +21,28 @@ func rootFuncPreRunE(cmd *cobra.Command, args []string) error {
 	}
 	logger.Info("Starting worker", "version", Version, "git commit", commit)
 
-	mr := worker.NewModprobeRunner(logger)
+	var mr worker.ModprobeRunner
+
+	sc := worker.NewKmodSyscalls()
+
+	if f := cmd.Flags().Lookup(worker.FlagNativeLoader); f != nil && f.Value.String() == "true" {
+		logger.Info("Using the native kernel module loader")
+		mr = worker.NewNativeModprobeRunner(sc, logger)
+	} else {
+		mr = worker.NewModprobeRunner(logger)
+	}
+
+	mr = worker.NewKernelLogModprobeRunner(mr, worker.NewKernelLog(), logger)
+
 	fsh := utils.NewFSHelper(logger)
-	w = worker.NewWorker(mr, fsh, logger)
+	w = worker.NewWorker(
+		mr,
+		worker.NewModuleChecker(sc, logger),
+		worker.NewHookRunner(logger),
+		fsh,
+		worker.NewFirmwareManager(logger),
+		logger,
+	)
 
 	return nil
 }
@@ -27,6 +50,11 @@ func rootFuncPreRunE(cmd *cobra.Command, args []string) error {
 func kmodLoadFunc(cmd *cobra.Command, args []string) error {
 	cfgPath := args[0]
 
+	f := cmd.Flags().Lookup(worker.FlagDryRun)
+	dryRun := f != nil && f.Value.String() == "true"
+
+	reportResult = !dryRun
+
 	logger.Info("Reading config", "path", cfgPath)
 
 	cfg, err := configHelper.ReadConfigFile(cfgPath)
@@ -35,6 +63,11 @@ func kmodLoadFunc(cmd *cobra.Command, args []string) error {
 	}
 
 	mountPathFlag := cmd.Flags().Lookup(worker.FlagFirmwarePath)
+
+	if dryRun {
+		return printLoadPlan(cmd.OutOrStdout(), cfg, mountPathFlag)
+	}
+
 	if mountPathFlag.Changed {
 		logger.V(1).Info(worker.FlagFirmwarePath + " set, setting firmware_class.path")
 
@@ -43,12 +76,74 @@ func kmodLoadFunc(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return w.LoadKmod(cmd.Context(), cfg, mountPathFlag.Value.String())
+	res, err := w.LoadKmod(cmd.Context(), cfg, kmodOptions(cmd))
+
+	result = res
+
+	return err
+}
+
+// printLoadPlan writes what kmod load would do to out, without doing it.
+func printLoadPlan(out io.Writer, cfg *kmmv1beta1.ModuleConfig, mountPathFlag *pflag.Flag) error {
+	if mountPathFlag.Changed {
+		if _, err := fmt.Fprintf(out, "write %s to %s\n", mountPathFlag.Value.String(), worker.FirmwareClassPathLocation); err != nil {
+			return err
+		}
+	}
+
+	plan, err := w.PlanLoadKmod(cfg, mountPathFlag.Value.String())
+	if err != nil {
+		return err
+	}
+
+	return plan.Print(out)
+}
+
+func kmodSetParamsFunc(cmd *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	reportResult = true
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	res, err := w.SetKmodParameters(cmd.Context(), cfg)
+
+	result = res
+
+	return err
+}
+
+func kmodStatusFunc(cmd *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	hs, err := w.KmodStatus(cfg, cmd.Flags().Lookup(worker.FlagFirmwarePath).Value.String())
+	if err != nil {
+		return fmt.Errorf("could not get the status of the kernel modules: %v", err)
+	}
+
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(hs)
 }
 
 func kmodUnloadFunc(cmd *cobra.Command, args []string) error {
 	cfgPath := args[0]
 
+	reportResult = true
+
 	logger.Info("Reading config", "path", cfgPath)
 
 	cfg, err := configHelper.ReadConfigFile(cfgPath)
@@ -56,17 +151,78 @@ func kmodUnloadFunc(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
 	}
 
-	return w.UnloadKmod(cmd.Context(), cfg, cmd.Flags().Lookup(worker.FlagFirmwarePath).Value.String())
+	res, err := w.UnloadKmod(cmd.Context(), cfg, kmodOptions(cmd))
+
+	result = res
+
+	return err
+}
+
+// kmodOptions returns the options of the load and unload commands.
+func kmodOptions(cmd *cobra.Command) worker.KmodOptions {
+	value := func(name string) string {
+		if f := cmd.Flags().Lookup(name); f != nil {
+			return f.Value.String()
+		}
+
+		return ""
+	}
+
+	return worker.KmodOptions{
+		FirmwareMountPath: value(worker.FlagFirmwarePath),
+		FirmwareOwner:     value(worker.FlagFirmwareOwner),
+		BlacklistPath:     value(worker.FlagBlacklistPath),
+	}
 }
 
 func setCommandsFlags() {
+	imagePullCmd.Flags().String(
+		worker.FlagOutputDir,
+		"/tmp",
+		"directory into which the files extracted from the image are copied")
+
+	kmodCmd.PersistentFlags().Bool(
+		worker.FlagNativeLoader,
+		false,
+		"if set, load and unload kernel modules with finit_module(2) and delete_module(2) instead of running modprobe")
+
 	kmodLoadCmd.Flags().String(
 		worker.FlagFirmwarePath,
 		"",
 		"if set, this value will be written to "+worker.FirmwareClassPathLocation+" and it is also the value that firmware host path is mounted to")
 
+	kmodLoadCmd.Flags().String(
+		worker.FlagFirmwareOwner,
+		"",
+		"identifies the Module on behalf of which firmware files are installed; defaults to the module name")
+
+	kmodLoadCmd.Flags().String(
+		worker.FlagBlacklistPath,
+		"",
+		"if set, blacklist the in-tree modules to remove in this modprobe configuration file")
+
+	kmodLoadCmd.Flags().Bool(
+		worker.FlagDryRun,
+		false,
+		"if set, run the compatibility checks and print the operations that would load the module, without running them")
+
+	kmodStatusCmd.Flags().String(
+		worker.FlagFirmwarePath,
+		"",
+		"if set, report which firmware files of the image are present in this directory")
+
 	kmodUnloadCmd.Flags().String(
 		worker.FlagFirmwarePath,
 		"",
 		"if set, this the value that firmware host path is mounted to")
+
+	kmodUnloadCmd.Flags().String(
+		worker.FlagFirmwareOwner,
+		"",
+		"identifies the Module on behalf of which firmware files were installed; defaults to the module name")
+
+	kmodUnloadCmd.Flags().String(
+		worker.FlagBlacklistPath,
+		"",
+		"if set, remove this modprobe configuration file after the module is unloaded")
 }
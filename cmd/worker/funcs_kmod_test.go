@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"strings"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
@@ -31,6 +32,8 @@ var _ = Describe("kmodLoadFunc", func() {
 
 	AfterEach(func() {
 		configHelper = worker.NewConfigHelper()
+		reportResult = false
+		result = nil
 		w = nil
 	})
 
@@ -54,6 +57,7 @@ var _ = Describe("kmodLoadFunc", func() {
 			cmd := &cobra.Command{}
 			cmd.SetContext(ctx)
 			cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+			cmd.Flags().String(worker.FlagFirmwareOwner, "", "")
 
 			if flagFirmwarePath != nil {
 				Expect(
@@ -61,15 +65,20 @@ var _ = Describe("kmodLoadFunc", func() {
 				).NotTo(
 					HaveOccurred(),
 				)
+				Expect(
+					cmd.Flags().Set(worker.FlagFirmwareOwner, "ns/name"),
+				).NotTo(
+					HaveOccurred(),
+				)
 				gomock.InOrder(
 					ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
 					wo.EXPECT().SetFirmwareClassPath(*flagFirmwarePath),
-					wo.EXPECT().LoadKmod(ctx, cfg, *flagFirmwarePath),
+					wo.EXPECT().LoadKmod(ctx, cfg, worker.KmodOptions{FirmwareMountPath: *flagFirmwarePath, FirmwareOwner: "ns/name"}),
 				)
 			} else {
 				gomock.InOrder(
 					ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
-					wo.EXPECT().LoadKmod(ctx, cfg, ""),
+					wo.EXPECT().LoadKmod(ctx, cfg, worker.KmodOptions{}),
 				)
 			}
 
@@ -83,4 +92,187 @@ var _ = Describe("kmodLoadFunc", func() {
 		Entry("fimrwarePath path defined and empty", ptr.To("")),
 		Entry("firmwarePath defined", ptr.To("/some/path")),
 	)
+	It("should keep the worker result", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+		ctx := context.TODO()
+		res := &worker.Result{Error: &worker.ResultError{Message: "some error"}}
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+		cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+		cmd.Flags().String(worker.FlagFirmwareOwner, "", "")
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().LoadKmod(ctx, cfg, worker.KmodOptions{}).Return(res, errors.New("some error")),
+		)
+
+		Expect(
+			kmodLoadFunc(cmd, []string{configPath}),
+		).To(
+			HaveOccurred(),
+		)
+
+		Expect(result).To(Equal(res))
+		Expect(reportResult).To(BeTrue())
+	})
+})
+
+var _ = Describe("kmodSetParamsFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch *worker.MockConfigHelper
+		wo *worker.MockWorker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		reportResult = false
+		result = nil
+		w = nil
+	})
+
+	It("should set the parameters and keep the worker result", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+		ctx := context.TODO()
+		res := &worker.Result{Parameters: map[string]string{"debug": "1"}}
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().SetKmodParameters(ctx, cfg).Return(res, nil),
+		)
+
+		Expect(
+			kmodSetParamsFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(result).To(Equal(res))
+	})
+})
+
+var _ = Describe("kmodLoadFunc_dryRun", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch *worker.MockConfigHelper
+		wo *worker.MockWorker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		reportResult = false
+		w = nil
+	})
+
+	It("should print the plan without loading the module", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+		plan := &worker.LoadPlan{ModprobeArgs: []string{"-vd", "/tmp/dir", "mod"}}
+
+		var out strings.Builder
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+		cmd.Flags().Bool(worker.FlagDryRun, false, "")
+
+		Expect(
+			cmd.Flags().Set(worker.FlagFirmwarePath, "/var/lib/firmware"),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			cmd.Flags().Set(worker.FlagDryRun, "true"),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().PlanLoadKmod(cfg, "/var/lib/firmware").Return(plan, nil),
+		)
+
+		Expect(
+			kmodLoadFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(out.String()).To(Equal(
+			"write /var/lib/firmware to " + worker.FirmwareClassPathLocation + "\nmodprobe -vd /tmp/dir mod\n",
+		))
+		Expect(reportResult).To(BeFalse())
+	})
+})
+
+var _ = Describe("kmodStatusFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch *worker.MockConfigHelper
+		wo *worker.MockWorker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		reportResult = false
+		w = nil
+	})
+
+	It("should print the host status as JSON", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+		hs := &worker.HostStatus{
+			Modules: []worker.ModuleState{{Name: "mod", Loaded: true, RefCount: 1}},
+		}
+
+		var out strings.Builder
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().KmodStatus(cfg, "").Return(hs, nil),
+		)
+
+		Expect(
+			kmodStatusFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(out.String()).To(MatchJSON(`{"modules":[{"name":"mod","loaded":true,"refCount":1}]}`))
+		Expect(reportResult).To(BeFalse())
+	})
 })
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"os"
 	"os/signal"
@@ -20,6 +21,12 @@ var (
 	configHelper = worker.NewConfigHelper()
 	logger       logr.Logger
 	w            worker.Worker
+
+	// result is set by the kmod commands and written to the termination message.
+	result *worker.Result
+	// reportResult is set by the commands that the operator runs in worker Pods, whose result it reads from the
+	// termination message; debug commands leave it unset so that they do not overwrite it.
+	reportResult bool
 )
 
 var rootCmd = &cobra.Command{
@@ -31,6 +38,18 @@ var rootCmd = &cobra.Command{
 	PersistentPreRunE: rootFuncPreRunE,
 }
 
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage kmod images",
+}
+
+var imagePullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull the kmod image of a config and copy the files that the worker needs",
+	Args:  cobra.ExactArgs(1),
+	RunE:  imagePullFunc,
+}
+
 var kmodCmd = &cobra.Command{
 	Use:   "kmod",
 	Short: "Manage kernel modules",
@@ -43,6 +62,20 @@ var kmodLoadCmd = &cobra.Command{
 	RunE:  kmodLoadFunc,
 }
 
+var kmodSetParamsCmd = &cobra.Command{
+	Use:   "set-params",
+	Short: "Change the parameters of a loaded kernel module without reloading it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodSetParamsFunc,
+}
+
+var kmodStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the state of the kernel modules and firmware files of a config on this host as JSON",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodStatusFunc,
+}
+
 var kmodUnloadCmd = &cobra.Command{
 	Use:   "unload",
 	Short: "Unload a kernel module",
@@ -54,20 +87,56 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM)
 	defer cancel()
 
-	rootCmd.AddCommand(kmodCmd)
+	rootCmd.AddCommand(imageCmd, kmodCmd)
 
-	kmodCmd.AddCommand(kmodLoadCmd, kmodUnloadCmd)
+	imageCmd.AddCommand(imagePullCmd)
+
+	kmodCmd.AddCommand(kmodLoadCmd, kmodSetParamsCmd, kmodStatusCmd, kmodUnloadCmd)
 
 	setCommandsFlags()
 
 	configureLogging()
 
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
+	err := rootCmd.ExecuteContext(ctx)
+
+	if reportResult {
+		// The command may have failed before running the worker, for example if the configuration could not be read.
+		if result == nil && err != nil {
+			result = &worker.Result{Error: worker.NewResultError(err)}
+		}
+
+		if result != nil {
+			writeTerminationMessage(result)
+		}
+	}
+
+	if err != nil {
+		// Let the operator tell a busy module apart from other failures.
+		if errors.Is(err, worker.ErrModuleBusy) {
+			logger.Error(err, "Fatal error")
+			os.Exit(worker.ExitCodeModuleBusy)
+		}
+
 		kmmcmd.FatalError(logger, err, "Fatal error")
 		os.Exit(1)
 	}
 }
 
+var terminationMessagePath = "/dev/termination-log"
+
+// writeTerminationMessage makes res available in the worker container's status, so that the operator can report it.
+func writeTerminationMessage(res *worker.Result) {
+	b, err := res.TerminationMessage()
+	if err != nil {
+		logger.Error(err, "Could not encode the termination message")
+		return
+	}
+
+	if err = os.WriteFile(terminationMessagePath, b, 0644); err != nil {
+		logger.Error(err, "Could not write the termination message", "path", terminationMessagePath)
+	}
+}
+
 func configureLogging() {
 	klogFlagSet := flag.NewFlagSet("klog", flag.ContinueOnError)
 	logConfig := textlogger.NewConfig()
@@ -60,6 +60,24 @@ type ModuleLoaderData struct {
 	// InTreeModulesToRemove - in case array not empty, remove the modules prior to loading the module specified in moduleName
 	InTreeModulesToRemove []string
 
+	// BlacklistInTreeModules - if true, InTreeModulesToRemove are blacklisted on the node
+	BlacklistInTreeModules bool
+
+	// RetryPolicy overrides the retry policy of the operator configuration
+	RetryPolicy *kmmv1beta1.RetryPolicy
+
+	// RetryToken is the value of the Module's retry annotation
+	RetryToken string
+
+	// DrainPolicy describes the Pods to evict from nodes before unloading the module
+	DrainPolicy *kmmv1beta1.DrainPolicy
+
+	// MaintenanceWindows restrict when the module may be reloaded or unloaded on nodes where it is loaded
+	MaintenanceWindows []kmmv1beta1.MaintenanceWindow
+
+	// DependsOn lists the modules that must be loaded on nodes before this module; namespaces are always set
+	DependsOn []kmmv1beta1.ModuleReference
+
 	// used for setting the owner field of pods/buildconfigs
 	Owner metav1.Object
 
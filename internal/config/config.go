@@ -27,10 +27,44 @@ type Job struct {
 }
 
 type Worker struct {
-	RunAsUser        *int64  `yaml:"runAsUser"`
-	SELinuxType      string  `yaml:"seLinuxType"`
-	FirmwareHostPath *string `yaml:"firmwareHostPath,omitempty"`
-}
+	RunAsUser        *int64       `yaml:"runAsUser"`
+	SELinuxType      string       `yaml:"seLinuxType"`
+	FirmwareHostPath *string      `yaml:"firmwareHostPath,omitempty"`
+	NativeLoader     bool         `yaml:"nativeLoader,omitempty"`
+	PullImages       bool         `yaml:"pullImages,omitempty"`
+	RetryPolicy      *RetryPolicy `yaml:"retryPolicy,omitempty"`
+	// MaintenanceWindows are the default maintenance windows of Modules that do not define any.
+	// They only apply to upgrades and unloads, not to the first load of a module on a node.
+	MaintenanceWindows []MaintenanceWindow `yaml:"maintenanceWindows,omitempty"`
+}
+
+// RetryPolicy limits how often loader and unloader Pods are recreated on a node where a module failed to load or unload.
+type RetryPolicy struct {
+	MaxAttempts    int32         `yaml:"maxAttempts,omitempty"`
+	InitialBackoff time.Duration `yaml:"initialBackoff,omitempty"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time during which KMM may reload or unload modules on nodes.
+type MaintenanceWindow struct {
+	Schedule string        `yaml:"schedule"`
+	Duration time.Duration `yaml:"duration"`
+}
+
+// ConflictPolicy tells what KMM does when several Modules use the same kernel module on a node.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyReject makes the webhook reject Modules that conflict with existing ones, and prevents conflicting
+	// Modules from being configured on nodes where another Module already uses the kernel module; the Conflict
+	// condition of all conflicting Modules reports the rejection.
+	ConflictPolicyReject ConflictPolicy = "Reject"
+	// ConflictPolicyWarn only reports conflicts; all Modules are configured on nodes.
+	ConflictPolicyWarn ConflictPolicy = "Warn"
+	// ConflictPolicyFirstWins configures only the oldest of conflicting Modules on each node, by creation time, even if
+	// a newer Module was configured on the node first; the webhook only warns about conflicts.
+	ConflictPolicyFirstWins ConflictPolicy = "FirstWins"
+)
 
 type LeaderElection struct {
 	Enabled    bool   `yaml:"enabled"`
@@ -50,6 +84,9 @@ type Config struct {
 	Metrics                Metrics        `yaml:"metrics"`
 	WebhookPort            int            `yaml:"webhookPort"`
 	Worker                 Worker         `yaml:"worker"`
+	// ModuleConflictPolicy is the policy applied to Modules that use the same kernel module on a node.
+	// Defaults to Warn.
+	ModuleConflictPolicy ConflictPolicy `yaml:"moduleConflictPolicy,omitempty"`
 }
 
 var ErrCannotUseCustomConfig = errors.New("cannot use custom config on top of the default config; using default configs")
@@ -165,6 +202,12 @@ func (ch *configHelper) decodeStrictYAMLIntoConfig(yamlData []byte, config *Conf
 		return fmt.Errorf("error unmarshaling YAML: %v", err)
 	}
 
+	switch config.ModuleConflictPolicy {
+	case "", ConflictPolicyReject, ConflictPolicyWarn, ConflictPolicyFirstWins:
+	default:
+		return fmt.Errorf("invalid moduleConflictPolicy %q", config.ModuleConflictPolicy)
+	}
+
 	return nil
 }
 
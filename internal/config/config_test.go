@@ -86,6 +86,7 @@ worker:
  runAsUser: 1000
  seLinuxType: "custom_t"
  firmwareHostPath: "/firmware"
+ nativeLoader: true
 `,
 			},
 		}
@@ -98,6 +99,7 @@ worker:
 		Expect(cfg.LeaderElection.ResourceID).To(Equal("some-id"))
 		Expect(cfg.Worker.SELinuxType).To(Equal("custom_t"))
 		Expect(*cfg.Worker.FirmwareHostPath).To(Equal("/firmware"))
+		Expect(cfg.Worker.NativeLoader).To(BeTrue())
 		Expect(cfg.Job.GCDelay).To(Equal(2 * time.Minute))
 		Expect(*cfg.Worker.RunAsUser).To(Equal(int64(1000)))
 	})
@@ -249,6 +251,25 @@ job:
 		Expect(cfg.Job.GCDelay).To(Equal(45 * time.Second))
 	})
 
+	It("should decode the module conflict policy", func() {
+		yamlData := []byte(`
+moduleConflictPolicy: FirstWins
+`)
+		cfg := &Config{}
+		err := ch.decodeStrictYAMLIntoConfig(yamlData, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.ModuleConflictPolicy).To(Equal(ConflictPolicyFirstWins))
+	})
+
+	It("should return error on an invalid module conflict policy", func() {
+		yamlData := []byte(`
+moduleConflictPolicy: LastWins
+`)
+		cfg := &Config{}
+		err := ch.decodeStrictYAMLIntoConfig(yamlData, cfg)
+		Expect(err).To(MatchError(ContainSubstring("invalid moduleConflictPolicy")))
+	})
+
 	It("should return error on unknown field", func() {
 		yamlData := []byte(`
 someUnknownField: true
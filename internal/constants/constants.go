@@ -7,6 +7,8 @@ const (
 	TargetKernelTarget     = "kmm.node.kubernetes.io/target-kernel"
 	ResourceType           = "kmm.node.kubernetes.io/resource-type"
 	ResourceHashAnnotation = "kmm.node.kubernetes.io/last-hash"
+	RetryAnnotation        = "kmm.node.kubernetes.io/retry"
+	NodeCordonedAnnotation = "kmm.node.kubernetes.io/cordoned"
 	KernelLabel            = "kmm.node.kubernetes.io/kernel-version.full"
 	DaemonSetRole          = "kmm.node.kubernetes.io/role"
 	NamespaceLabelKey      = "kmm.node.k8s.io/contains-modules"
@@ -11,6 +11,7 @@ package controllers
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	api "github.com/kubernetes-sigs/kernel-module-management/internal/api"
@@ -42,6 +43,22 @@ func (m *MockmoduleReconcilerHelperAPI) EXPECT() *MockmoduleReconcilerHelperAPIM
 	return m.recorder
 }
 
+// applyUpgradeStrategy mocks base method.
+func (m *MockmoduleReconcilerHelperAPI) applyUpgradeStrategy(ctx context.Context, mod *v1beta1.Module, sdMap map[string]schedulingData) (*v1beta1.UpgradeStatus, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "applyUpgradeStrategy", ctx, mod, sdMap)
+	ret0, _ := ret[0].(*v1beta1.UpgradeStatus)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// applyUpgradeStrategy indicates an expected call of applyUpgradeStrategy.
+func (mr *MockmoduleReconcilerHelperAPIMockRecorder) applyUpgradeStrategy(ctx, mod, sdMap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "applyUpgradeStrategy", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).applyUpgradeStrategy), ctx, mod, sdMap)
+}
+
 // disableModuleOnNode mocks base method.
 func (m *MockmoduleReconcilerHelperAPI) disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error {
 	m.ctrl.T.Helper()
@@ -128,6 +145,20 @@ func (mr *MockmoduleReconcilerHelperAPIMockRecorder) prepareSchedulingData(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "prepareSchedulingData", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).prepareSchedulingData), ctx, mod, targetedNodes, currentNMCs)
 }
 
+// rollbackModuleOnNode mocks base method.
+func (m *MockmoduleReconcilerHelperAPI) rollbackModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, config *v1beta1.ModuleConfig, node *v1.Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "rollbackModuleOnNode", ctx, mld, config, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// rollbackModuleOnNode indicates an expected call of rollbackModuleOnNode.
+func (mr *MockmoduleReconcilerHelperAPIMockRecorder) rollbackModuleOnNode(ctx, mld, config, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "rollbackModuleOnNode", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).rollbackModuleOnNode), ctx, mld, config, node)
+}
+
 // setFinalizerAndStatus mocks base method.
 func (m *MockmoduleReconcilerHelperAPI) setFinalizerAndStatus(ctx context.Context, mod *v1beta1.Module) error {
 	m.ctrl.T.Helper()
@@ -143,17 +174,17 @@ func (mr *MockmoduleReconcilerHelperAPIMockRecorder) setFinalizerAndStatus(ctx,
 }
 
 // updateModuleStatus mocks base method.
-func (m *MockmoduleReconcilerHelperAPI) updateModuleStatus(ctx context.Context, mod *v1beta1.Module, targetedNodes []v1.Node) error {
+func (m *MockmoduleReconcilerHelperAPI) updateModuleStatus(ctx context.Context, mod *v1beta1.Module, targetedNodes []v1.Node, upgradeStatus *v1beta1.UpgradeStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "updateModuleStatus", ctx, mod, targetedNodes)
+	ret := m.ctrl.Call(m, "updateModuleStatus", ctx, mod, targetedNodes, upgradeStatus)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // updateModuleStatus indicates an expected call of updateModuleStatus.
-func (mr *MockmoduleReconcilerHelperAPIMockRecorder) updateModuleStatus(ctx, mod, targetedNodes any) *gomock.Call {
+func (mr *MockmoduleReconcilerHelperAPIMockRecorder) updateModuleStatus(ctx, mod, targetedNodes, upgradeStatus any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateModuleStatus", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).updateModuleStatus), ctx, mod, targetedNodes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateModuleStatus", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).updateModuleStatus), ctx, mod, targetedNodes, upgradeStatus)
 }
 
 // MocknamespaceLabeler is a mock of namespaceLabeler interface.
@@ -42,6 +42,20 @@ func (m *MocknmcReconcilerHelper) EXPECT() *MocknmcReconcilerHelperMockRecorder
 	return m.recorder
 }
 
+// CompleteDrain mocks base method.
+func (m *MocknmcReconcilerHelper) CompleteDrain(ctx context.Context, nmc *v1beta1.NodeModulesConfig, node *v1.Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteDrain", ctx, nmc, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteDrain indicates an expected call of CompleteDrain.
+func (mr *MocknmcReconcilerHelperMockRecorder) CompleteDrain(ctx, nmc, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteDrain", reflect.TypeOf((*MocknmcReconcilerHelper)(nil).CompleteDrain), ctx, nmc, node)
+}
+
 // GarbageCollectInUseLabels mocks base method.
 func (m *MocknmcReconcilerHelper) GarbageCollectInUseLabels(ctx context.Context, nmc *v1beta1.NodeModulesConfig) error {
 	m.ctrl.T.Helper()
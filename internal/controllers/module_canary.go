@@ -0,0 +1,512 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// canaryCheckInterval is how often the health of the canary nodes is checked while the canary is progressing.
+const canaryCheckInterval = 30 * time.Second
+
+// applyCanary restricts upgrades, the nodes on which the module config would change, to the canary nodes until the
+// canary succeeded, and rolls the module config back on all nodes if it failed.
+// The canary of a Module generation only starts if an older generation was rolled out to all nodes, so that there is
+// a revision to roll back to.
+// It updates the canary status in upgradeStatus, and returns the nodes that may be upgraded and the delay after which
+// the canary should be checked again.
+func (mrh *moduleReconcilerHelper) applyCanary(
+	ctx context.Context,
+	mod *kmmv1beta1.Module,
+	sdMap map[string]schedulingData,
+	nmcByName map[string]*kmmv1beta1.NodeModulesConfig,
+	upgradeStatus *kmmv1beta1.UpgradeStatus,
+	revision *kmmv1beta1.ModuleRevision,
+	upgrades []string,
+	desired int) ([]string, time.Duration, error) {
+
+	logger := log.FromContext(ctx)
+
+	strategy := mod.Spec.UpgradeStrategy.Canary
+
+	var canary *kmmv1beta1.CanaryStatus
+
+	if prev := mod.Status.Upgrade; prev != nil && prev.Canary != nil && prev.Canary.Generation == mod.Generation {
+		canary = prev.Canary.DeepCopy()
+	}
+
+	if canary == nil {
+		if len(upgrades) == 0 || revision == nil || revision.Generation == mod.Generation {
+			return upgrades, 0, nil
+		}
+
+		nodes, err := canaryNodes(strategy, sdMap, upgrades, desired)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		logger.Info("Starting the canary", "generation", mod.Generation, "nodes", nodes)
+
+		canary = &kmmv1beta1.CanaryStatus{
+			Generation: mod.Generation,
+			Phase:      kmmv1beta1.CanaryProgressing,
+			Nodes:      nodes,
+		}
+	}
+
+	upgradeStatus.Canary = canary
+
+	switch canary.Phase {
+	case kmmv1beta1.CanarySucceeded:
+		return upgrades, 0, nil
+	case kmmv1beta1.CanaryFailed:
+		mrh.rollback(ctx, mod, sdMap, nmcByName, upgradeStatus, revision, desired)
+		return nil, 0, nil
+	}
+
+	failure, waiting, err := mrh.checkCanary(ctx, mod, sdMap, nmcByName, canary.Nodes)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not check the health of the canary nodes: %v", err)
+	}
+
+	// a canary node that becomes unhealthy while soaking fails the canary
+	if failure == "" && waiting != "" && canary.Phase == kmmv1beta1.CanarySoaking {
+		failure = waiting
+	}
+
+	if failure != "" {
+		logger.Info("The canary failed; rolling back", "generation", mod.Generation, "reason", failure)
+
+		canary.Phase = kmmv1beta1.CanaryFailed
+		canary.Message = failure
+
+		mrh.rollback(ctx, mod, sdMap, nmcByName, upgradeStatus, revision, desired)
+
+		return nil, 0, nil
+	}
+
+	requeueAfter := canaryCheckInterval
+
+	switch {
+	case waiting != "":
+		canary.Message = waiting
+	case canary.Phase == kmmv1beta1.CanaryProgressing:
+		canary.Phase = kmmv1beta1.CanarySoaking
+		canary.SoakStartTime = &metav1.Time{Time: time.Now()}
+		canary.Message = ""
+		fallthrough
+	default:
+		soak := time.Duration(strategy.SoakSeconds) * time.Second
+
+		remaining := time.Until(canary.SoakStartTime.Add(soak))
+		if remaining <= 0 {
+			logger.Info("The canary succeeded", "generation", mod.Generation)
+			canary.Phase = kmmv1beta1.CanarySucceeded
+			return upgrades, 0, nil
+		}
+
+		requeueAfter = min(requeueAfter, remaining)
+	}
+
+	allowed := make([]string, 0, len(canary.Nodes))
+
+	for _, nodeName := range upgrades {
+		if slices.Contains(canary.Nodes, nodeName) {
+			allowed = append(allowed, nodeName)
+			continue
+		}
+
+		sdMap[nodeName] = schedulingData{}
+		upgradeStatus.PendingNumber++
+	}
+
+	return allowed, requeueAfter, nil
+}
+
+// canaryNodes returns the canary nodes among upgrades: those that match the node selector of strategy, or the first
+// ones in name order if there is no selector or if it matches none of them.
+func canaryNodes(
+	strategy *kmmv1beta1.CanaryStrategy,
+	sdMap map[string]schedulingData,
+	upgrades []string,
+	desired int) ([]string, error) {
+
+	sorted := slices.Clone(upgrades)
+	sort.Strings(sorted)
+
+	if len(strategy.NodeSelector) > 0 {
+		selector := labels.SelectorFromSet(strategy.NodeSelector)
+
+		nodes := make([]string, 0, len(sorted))
+
+		for _, nodeName := range sorted {
+			if n := sdMap[nodeName].node; n != nil && selector.Matches(labels.Set(n.Labels)) {
+				nodes = append(nodes, nodeName)
+			}
+		}
+
+		if len(nodes) > 0 {
+			return nodes, nil
+		}
+	}
+
+	count := 1
+
+	if strategy.Nodes != nil {
+		var err error
+
+		if count, err = intstr.GetScaledValueFromIntOrPercent(strategy.Nodes, desired, true); err != nil {
+			return nil, fmt.Errorf("invalid number of canary nodes: %v", err)
+		}
+
+		count = max(count, 1)
+	}
+
+	return sorted[:min(count, len(sorted))], nil
+}
+
+// checkCanary checks the health of the canary nodes.
+// It returns why the canary failed, if the module failed to load on one of them, or what the canary is still waiting
+// for before all of them are healthy.
+func (mrh *moduleReconcilerHelper) checkCanary(
+	ctx context.Context,
+	mod *kmmv1beta1.Module,
+	sdMap map[string]schedulingData,
+	nmcByName map[string]*kmmv1beta1.NodeModulesConfig,
+	nodes []string) (string, string, error) {
+
+	var (
+		waiting string
+		// canary nodes that are still targeted by the Module
+		targeted = make([]string, 0, len(nodes))
+	)
+
+	for _, nodeName := range nodes {
+		sd, ok := sdMap[nodeName]
+		if !ok || sd.action != actionAdd {
+			continue
+		}
+
+		targeted = append(targeted, nodeName)
+
+		var (
+			spec   *kmmv1beta1.NodeModuleSpec
+			status *kmmv1beta1.NodeModuleStatus
+		)
+
+		if nmcObj := nmcByName[nodeName]; nmcObj != nil {
+			spec, _ = mrh.nmcHelper.GetModuleSpecEntry(nmcObj, mod.Namespace, mod.Name)
+			status = mrh.nmcHelper.GetModuleStatusEntry(nmcObj, mod.Namespace, mod.Name)
+		}
+
+		if spec == nil || !reflect.DeepEqual(spec.Config, moduleConfigFromMLD(sd.mld)) {
+			if waiting == "" {
+				waiting = fmt.Sprintf("waiting for the new config to be set on node %s", nodeName)
+			}
+			continue
+		}
+
+		if status == nil {
+			if waiting == "" {
+				waiting = fmt.Sprintf("waiting for the module to be loaded on node %s", nodeName)
+			}
+			continue
+		}
+
+		if f := status.LastFailure; f != nil && f.Action == kmmv1beta1.WorkerActionLoad && f.SpecHash == specHash(spec) {
+			return fmt.Sprintf("the module failed to load on node %s: %s", nodeName, f.Reason), "", nil
+		}
+
+		if !reflect.DeepEqual(status.Config, spec.Config) && waiting == "" {
+			waiting = fmt.Sprintf("waiting for the module to be loaded on node %s", nodeName)
+		}
+	}
+
+	if waiting != "" {
+		return "", waiting, nil
+	}
+
+	if mod.Spec.DevicePlugin != nil {
+		podLabels, _ := generateDevicePluginLabelsAndSelector(mod)
+
+		nodeName, err := mrh.firstNodeWithoutReadyPod(ctx, mod.Namespace, labels.SelectorFromSet(podLabels), targeted)
+		if err != nil {
+			return "", "", fmt.Errorf("could not list device plugin Pods: %v", err)
+		}
+
+		if nodeName != "" {
+			return "", fmt.Sprintf("waiting for the device plugin to be ready on node %s", nodeName), nil
+		}
+	}
+
+	if probe := mod.Spec.UpgradeStrategy.Canary.Probe; probe != nil {
+		selector, err := metav1.LabelSelectorAsSelector(probe)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid probe selector: %v", err)
+		}
+
+		nodeName, err := mrh.firstNodeWithoutReadyPod(ctx, mod.Namespace, selector, targeted)
+		if err != nil {
+			return "", "", fmt.Errorf("could not list probe Pods: %v", err)
+		}
+
+		if nodeName != "" {
+			return "", fmt.Sprintf("waiting for the probe Pods to be ready on node %s", nodeName), nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// firstNodeWithoutReadyPod returns the first of nodes on which no Pod in namespace matches selector, or on which one
+// of those Pods is not ready.
+// It returns an empty string if all nodes have matching Pods that are all ready.
+func (mrh *moduleReconcilerHelper) firstNodeWithoutReadyPod(
+	ctx context.Context,
+	namespace string,
+	selector labels.Selector,
+	nodes []string) (string, error) {
+
+	podList := v1.PodList{}
+
+	if err := mrh.client.List(ctx, &podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return "", err
+	}
+
+	for _, nodeName := range nodes {
+		found := false
+		ready := true
+
+		for i := range podList.Items {
+			p := &podList.Items[i]
+
+			if p.Spec.NodeName != nodeName {
+				continue
+			}
+
+			found = true
+
+			if !podutils.IsPodReady(p) {
+				ready = false
+			}
+		}
+
+		if !found || !ready {
+			return nodeName, nil
+		}
+	}
+
+	return "", nil
+}
+
+// rollback restores the module config of revision on the nodes of sdMap that would get another config, and holds
+// back the other ones.
+// Nodes running a kernel for which the revision has no config get the new config.
+func (mrh *moduleReconcilerHelper) rollback(
+	ctx context.Context,
+	mod *kmmv1beta1.Module,
+	sdMap map[string]schedulingData,
+	nmcByName map[string]*kmmv1beta1.NodeModulesConfig,
+	upgradeStatus *kmmv1beta1.UpgradeStatus,
+	revision *kmmv1beta1.ModuleRevision,
+	desired int) {
+
+	logger := log.FromContext(ctx)
+
+	var pending int32
+
+	for nodeName, sd := range sdMap {
+		if sd.action != actionAdd {
+			continue
+		}
+
+		cfg := revisionConfig(revision, sd.mld.KernelVersion, sd.overrides)
+		if cfg == nil || reflect.DeepEqual(*cfg, moduleConfigFromMLD(sd.mld)) {
+			continue
+		}
+
+		pending++
+
+		if nmcObj := nmcByName[nodeName]; nmcObj != nil {
+			spec, _ := mrh.nmcHelper.GetModuleSpecEntry(nmcObj, mod.Namespace, mod.Name)
+			if spec != nil && reflect.DeepEqual(spec.Config, *cfg) {
+				sdMap[nodeName] = schedulingData{}
+				continue
+			}
+		}
+
+		logger.Info("Rolling back the module config", "node", nodeName)
+
+		sdMap[nodeName] = schedulingData{action: actionRollback, mld: sd.mld, node: sd.node, config: cfg}
+	}
+
+	upgradeStatus.PendingNumber = pending
+	upgradeStatus.UpdatedNumber = int32(desired) - pending
+	upgradeStatus.UnavailableNumber = 0
+}
+
+// revisionConfig returns the module config of revision for the nodes running kernelVersion that the overrides
+// apply to, or nil if it has none.
+func revisionConfig(revision *kmmv1beta1.ModuleRevision, kernelVersion string, overrides []string) *kmmv1beta1.ModuleConfig {
+	if revision == nil {
+		return nil
+	}
+
+	for i := range revision.Configs {
+		if revision.Configs[i].KernelVersion == kernelVersion && slices.Equal(revision.Configs[i].NodeOverrides, overrides) {
+			return &revision.Configs[i].ModuleConfig
+		}
+	}
+
+	return nil
+}
+
+// newRevision returns the revision of the module configs of the nodes in sdMap, one per kernel version and set of
+// ModuleNodeOverrides.
+func newRevision(generation int64, sdMap map[string]schedulingData) *kmmv1beta1.ModuleRevision {
+	configs := make(map[string]kmmv1beta1.RevisionConfig)
+
+	for _, sd := range sdMap {
+		if sd.action == actionAdd {
+			key := sd.mld.KernelVersion + "/" + strings.Join(sd.overrides, ",")
+			configs[key] = kmmv1beta1.RevisionConfig{
+				ModuleConfig:  moduleConfigFromMLD(sd.mld),
+				NodeOverrides: slices.Clone(sd.overrides),
+			}
+		}
+	}
+
+	revision := kmmv1beta1.ModuleRevision{
+		Generation: generation,
+		Configs:    make([]kmmv1beta1.RevisionConfig, 0, len(configs)),
+	}
+
+	for _, cfg := range configs {
+		revision.Configs = append(revision.Configs, cfg)
+	}
+
+	sort.Slice(revision.Configs, func(i, j int) bool {
+		a, b := revision.Configs[i], revision.Configs[j]
+		if a.KernelVersion != b.KernelVersion {
+			return a.KernelVersion < b.KernelVersion
+		}
+
+		return slices.Compare(a.NodeOverrides, b.NodeOverrides) < 0
+	})
+
+	return &revision
+}
+
+// getRevision returns the revision stored in the ControllerRevision that the upgrade status of mod refers to, or nil if
+// there is none.
+func (mrh *moduleReconcilerHelper) getRevision(ctx context.Context, mod *kmmv1beta1.Module) (*kmmv1beta1.ModuleRevision, error) {
+	if mod.Status.Upgrade == nil || mod.Status.Upgrade.Revision == "" {
+		return nil, nil
+	}
+
+	name := mod.Status.Upgrade.Revision
+	cr := appsv1.ControllerRevision{}
+
+	if err := mrh.client.Get(ctx, types.NamespacedName{Namespace: mod.Namespace, Name: name}, &cr); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Info(utils.WarnString("The ControllerRevision of the Module was not found"), "name", name)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not get ControllerRevision %s/%s: %v", mod.Namespace, name, err)
+	}
+
+	revision := kmmv1beta1.ModuleRevision{}
+
+	if err := json.Unmarshal(cr.Data.Raw, &revision); err != nil {
+		return nil, fmt.Errorf("could not decode ControllerRevision %s/%s: %v", mod.Namespace, name, err)
+	}
+
+	return &revision, nil
+}
+
+// saveRevision stores revision in a ControllerRevision owned by mod, and returns its name.
+// The name is derived from the content of revision, so that saving the same revision again does nothing.
+func (mrh *moduleReconcilerHelper) saveRevision(ctx context.Context, mod *kmmv1beta1.Module, revision *kmmv1beta1.ModuleRevision) (string, error) {
+	data, err := json.Marshal(revision)
+	if err != nil {
+		return "", fmt.Errorf("could not encode the revision: %v", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	cr := appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", mod.Name, hex.EncodeToString(sum[:5])),
+			Namespace: mod.Namespace,
+			Labels:    map[string]string{constants.ModuleNameLabel: mod.Name},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: revision.Generation,
+	}
+
+	if err = controllerutil.SetControllerReference(mod, &cr, mrh.scheme); err != nil {
+		return "", fmt.Errorf("could not set the owner of ControllerRevision %s/%s: %v", cr.Namespace, cr.Name, err)
+	}
+
+	if err = mrh.client.Create(ctx, &cr); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("could not create ControllerRevision %s/%s: %v", cr.Namespace, cr.Name, err)
+	}
+
+	log.FromContext(ctx).Info("Recorded the revision", "name", cr.Name, "generation", revision.Generation)
+
+	return cr.Name, nil
+}
+
+// pruneRevisions deletes the ControllerRevisions of mod other than keep.
+func (mrh *moduleReconcilerHelper) pruneRevisions(ctx context.Context, mod *kmmv1beta1.Module, keep ...string) error {
+	crList := appsv1.ControllerRevisionList{}
+
+	opts := []client.ListOption{
+		client.InNamespace(mod.Namespace),
+		client.MatchingLabels{constants.ModuleNameLabel: mod.Name},
+	}
+
+	if err := mrh.client.List(ctx, &crList, opts...); err != nil {
+		return fmt.Errorf("could not list ControllerRevisions: %v", err)
+	}
+
+	for i := range crList.Items {
+		cr := &crList.Items[i]
+
+		if slices.Contains(keep, cr.Name) || !metav1.IsControlledBy(cr, mod) {
+			continue
+		}
+
+		log.FromContext(ctx).Info("Deleting a superseded revision", "name", cr.Name)
+
+		if err := mrh.client.Delete(ctx, cr); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete ControllerRevision %s/%s: %v", cr.Namespace, cr.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,582 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("applyUpgradeStrategy_canary", func() {
+	const (
+		modName      = "modName"
+		modNamespace = "modNamespace"
+		oldImage     = "old-image"
+		newImage     = "new-image"
+		revisionName = "modName-old"
+	)
+
+	var (
+		ctx      context.Context
+		clnt     *client.MockClient
+		mod      *kmmv1beta1.Module
+		mrh      moduleReconcilerHelperAPI
+		revision *kmmv1beta1.ModuleRevision
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		revision = &kmmv1beta1.ModuleRevision{
+			Generation: 1,
+			Configs:    []kmmv1beta1.RevisionConfig{{ModuleConfig: kmmv1beta1.ModuleConfig{ContainerImage: oldImage}}},
+		}
+		mod = &kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: modName, Namespace: modNamespace, Generation: 2},
+			Spec: kmmv1beta1.ModuleSpec{
+				UpgradeStrategy: &kmmv1beta1.UpgradeStrategy{
+					Canary: &kmmv1beta1.CanaryStrategy{SoakSeconds: 60},
+				},
+			},
+			Status: kmmv1beta1.ModuleStatus{
+				Upgrade: &kmmv1beta1.UpgradeStatus{Revision: revisionName},
+			},
+		}
+		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, nmc.NewHelper(clnt, ""), nil, scheme, "")
+
+		clnt.EXPECT().
+			Get(ctx, types.NamespacedName{Namespace: modNamespace, Name: revisionName}, gomock.AssignableToTypeOf(&appsv1.ControllerRevision{})).
+			DoAndReturn(func(_ interface{}, _ interface{}, cr *appsv1.ControllerRevision, _ ...ctrlclient.GetOption) error {
+				data, err := json.Marshal(revision)
+				cr.Data.Raw = data
+				return err
+			}).
+			AnyTimes()
+	})
+
+	// expectSavedRevision expects a ControllerRevision to be created and the other ControllerRevisions of the Module to
+	// be listed; it returns the created ControllerRevision.
+	expectSavedRevision := func() *appsv1.ControllerRevision {
+		cr := &appsv1.ControllerRevision{}
+
+		gomock.InOrder(
+			clnt.EXPECT().Create(ctx, gomock.AssignableToTypeOf(cr)).DoAndReturn(
+				func(_ interface{}, obj *appsv1.ControllerRevision, _ ...ctrlclient.CreateOption) error {
+					obj.DeepCopyInto(cr)
+					return nil
+				},
+			),
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&appsv1.ControllerRevisionList{}), gomock.Any(), gomock.Any()),
+		)
+
+		return cr
+	}
+
+	decodeRevision := func(cr *appsv1.ControllerRevision) *kmmv1beta1.ModuleRevision {
+		rev := kmmv1beta1.ModuleRevision{}
+		Expect(json.Unmarshal(cr.Data.Raw, &rev)).To(Succeed())
+		return &rev
+	}
+
+	// nmcWithImage returns the NMC of nodeName, with a module config using image in its spec and loadedImage in its
+	// status.
+	nmcWithImage := func(nodeName, image, loadedImage string) kmmv1beta1.NodeModulesConfig {
+		item := kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace}
+
+		return kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{ModuleItem: item, Config: kmmv1beta1.ModuleConfig{ContainerImage: image}},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{ModuleItem: item, Config: kmmv1beta1.ModuleConfig{ContainerImage: loadedImage}},
+				},
+			},
+		}
+	}
+
+	addSchedulingData := func(nodeNames ...string) map[string]schedulingData {
+		sdMap := make(map[string]schedulingData)
+
+		for _, n := range nodeNames {
+			sdMap[n] = schedulingData{
+				action: actionAdd,
+				mld:    &api.ModuleLoaderData{Name: modName, Namespace: modNamespace, ContainerImage: newImage},
+				node: &v1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: n, Labels: map[string]string{"name": n}},
+				},
+			}
+		}
+
+		return sdMap
+	}
+
+	expectNMCs := func(nmcs ...kmmv1beta1.NodeModulesConfig) {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+				list.Items = nmcs
+				return nil
+			},
+		)
+	}
+
+	expectPods := func(pods ...v1.Pod) {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *v1.PodList, _ ...interface{}) error {
+				list.Items = pods
+				return nil
+			},
+		)
+	}
+
+	podOnNode := func(nodeName string, ready bool) v1.Pod {
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+
+		return v1.Pod{
+			Spec: v1.PodSpec{NodeName: nodeName},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	It("should record the revision once all nodes run the new config", func() {
+		mod.Status.Upgrade = nil
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+			nmcWithImage("node2", newImage, newImage),
+		)
+		cr := expectSavedRevision()
+
+		status, requeueAfter, err := mrh.applyUpgradeStrategy(ctx, mod, addSchedulingData("node1", "node2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+		Expect(status.Canary).To(BeNil())
+		Expect(status.Revision).To(Equal(cr.Name))
+		Expect(cr.Name).To(HavePrefix(modName + "-"))
+		Expect(cr.Namespace).To(Equal(modNamespace))
+		Expect(cr.Revision).To(Equal(int64(2)))
+		Expect(metav1.IsControlledBy(cr, mod)).To(BeTrue())
+		Expect(decodeRevision(cr)).To(Equal(&kmmv1beta1.ModuleRevision{
+			Generation: 2,
+			Configs:    []kmmv1beta1.RevisionConfig{{ModuleConfig: kmmv1beta1.ModuleConfig{ContainerImage: newImage}}},
+		}))
+	})
+
+	It("should delete the superseded revisions but the one in the status", func() {
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+		)
+
+		cr := &appsv1.ControllerRevision{}
+
+		owned := func(name string) appsv1.ControllerRevision {
+			r := appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: modNamespace}}
+			Expect(controllerutil.SetControllerReference(mod, &r, scheme)).To(Succeed())
+			return r
+		}
+
+		gomock.InOrder(
+			clnt.EXPECT().Create(ctx, gomock.AssignableToTypeOf(cr)).DoAndReturn(
+				func(_ interface{}, obj *appsv1.ControllerRevision, _ ...ctrlclient.CreateOption) error {
+					obj.DeepCopyInto(cr)
+					return nil
+				},
+			),
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&appsv1.ControllerRevisionList{}), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *appsv1.ControllerRevisionList, _ ...interface{}) error {
+					list.Items = []appsv1.ControllerRevision{
+						owned(revisionName),
+						owned(cr.Name),
+						owned("modName-superseded"),
+						{ObjectMeta: metav1.ObjectMeta{Name: "not-owned", Namespace: modNamespace}},
+					}
+					return nil
+				},
+			),
+			clnt.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(cr)).DoAndReturn(
+				func(_ interface{}, obj *appsv1.ControllerRevision, _ ...ctrlclient.DeleteOption) error {
+					Expect(obj.Name).To(Equal("modName-superseded"))
+					return nil
+				},
+			),
+		)
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, addSchedulingData("node1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Revision).To(Equal(cr.Name))
+	})
+
+	It("should not record the revision again if it did not change", func() {
+		revision.Generation = 2
+		revision.Configs = []kmmv1beta1.RevisionConfig{{ModuleConfig: kmmv1beta1.ModuleConfig{ContainerImage: newImage}}}
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+		)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&appsv1.ControllerRevisionList{}), gomock.Any(), gomock.Any())
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, addSchedulingData("node1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Revision).To(Equal(revisionName))
+	})
+
+	It("should return an error if the revision cannot be fetched", func() {
+		mod.Status.Upgrade.Revision = "other"
+
+		expectNMCs()
+		clnt.EXPECT().Get(ctx, types.NamespacedName{Namespace: modNamespace, Name: "other"}, gomock.Any()).Return(errors.New("random error"))
+
+		_, _, err := mrh.applyUpgradeStrategy(ctx, mod, addSchedulingData("node1"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not start a canary without a revision to roll back to", func() {
+		mod.Status.Upgrade = nil
+
+		expectNMCs(
+			nmcWithImage("node1", oldImage, oldImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary).To(BeNil())
+		Expect(status.Revision).To(BeEmpty())
+		Expect(sdMap["node1"].action).To(Equal(actionAdd))
+	})
+
+	It("should upgrade the canary node first", func() {
+		expectNMCs(
+			nmcWithImage("node1", oldImage, oldImage),
+			nmcWithImage("node2", oldImage, oldImage),
+			nmcWithImage("node3", oldImage, oldImage),
+		)
+
+		sdMap := addSchedulingData("node1", "node2", "node3")
+
+		status, requeueAfter, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(Equal(canaryCheckInterval))
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanaryProgressing))
+		Expect(status.Canary.Generation).To(Equal(int64(2)))
+		Expect(status.Canary.Nodes).To(Equal([]string{"node1"}))
+		Expect(status.Canary.Message).To(ContainSubstring("node1"))
+		Expect(status.Revision).To(Equal(revisionName))
+		Expect(status.PendingNumber).To(Equal(int32(2)))
+		Expect(sdMap["node1"].action).To(Equal(actionAdd))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+		Expect(sdMap["node3"]).To(Equal(schedulingData{}))
+	})
+
+	It("should not hold back nodes that booted another kernel during the canary", func() {
+		rebooted := nmcWithImage("node3", oldImage, oldImage)
+		rebooted.Spec.Modules[0].Config.KernelVersion = "old-kernel"
+
+		expectNMCs(
+			nmcWithImage("node1", oldImage, oldImage),
+			nmcWithImage("node2", oldImage, oldImage),
+			rebooted,
+		)
+
+		sdMap := addSchedulingData("node1", "node2", "node3")
+		sdMap["node3"].mld.KernelVersion = "new-kernel"
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary.Nodes).To(Equal([]string{"node1"}))
+		Expect(status.PendingNumber).To(Equal(int32(1)))
+		Expect(sdMap["node1"].action).To(Equal(actionAdd))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+		Expect(sdMap["node3"].action).To(Equal(actionAdd))
+	})
+
+	It("should use the nodes that match the canary node selector", func() {
+		mod.Spec.UpgradeStrategy.MaxUnavailable = ptr.To(intstr.FromInt32(2))
+		mod.Spec.UpgradeStrategy.Canary.NodeSelector = map[string]string{"name": "node2"}
+
+		expectNMCs(
+			nmcWithImage("node1", oldImage, oldImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary.Nodes).To(Equal([]string{"node2"}))
+		Expect(sdMap["node1"]).To(Equal(schedulingData{}))
+		Expect(sdMap["node2"].action).To(Equal(actionAdd))
+	})
+
+	It("should fall back to the first nodes if the canary node selector matches none of them", func() {
+		mod.Spec.UpgradeStrategy.Canary.NodeSelector = map[string]string{"name": "other-node"}
+
+		expectNMCs(
+			nmcWithImage("node1", oldImage, oldImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanaryProgressing))
+		Expect(status.Canary.Nodes).To(Equal([]string{"node1"}))
+		Expect(sdMap["node1"].action).To(Equal(actionAdd))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+	})
+
+	It("should start soaking once the canary nodes are healthy", func() {
+		mod.Spec.DevicePlugin = &kmmv1beta1.DevicePluginSpec{}
+		mod.Status.Upgrade.Canary = &kmmv1beta1.CanaryStatus{
+			Generation: 2,
+			Phase:      kmmv1beta1.CanaryProgressing,
+			Nodes:      []string{"node1"},
+		}
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+		expectPods(podOnNode("node1", true))
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, requeueAfter, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(Equal(canaryCheckInterval))
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanarySoaking))
+		Expect(status.Canary.SoakStartTime).NotTo(BeNil())
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+	})
+
+	It("should wait for the device plugin to be ready on the canary nodes", func() {
+		mod.Spec.DevicePlugin = &kmmv1beta1.DevicePluginSpec{}
+		mod.Status.Upgrade.Canary = &kmmv1beta1.CanaryStatus{
+			Generation: 2,
+			Phase:      kmmv1beta1.CanaryProgressing,
+			Nodes:      []string{"node1"},
+		}
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+		expectPods(podOnNode("node1", false), podOnNode("node2", true))
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, addSchedulingData("node1", "node2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanaryProgressing))
+		Expect(status.Canary.Message).To(Equal("waiting for the device plugin to be ready on node node1"))
+	})
+
+	It("should continue the rollout once the soak time elapsed", func() {
+		mod.Status.Upgrade.Canary = &kmmv1beta1.CanaryStatus{
+			Generation:    2,
+			Phase:         kmmv1beta1.CanarySoaking,
+			Nodes:         []string{"node1"},
+			SoakStartTime: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+		}
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanarySucceeded))
+		Expect(sdMap["node2"].action).To(Equal(actionAdd))
+		Expect(status.Revision).To(Equal(revisionName))
+	})
+
+	It("should fail the canary if a probe Pod is not ready while soaking", func() {
+		mod.Spec.UpgradeStrategy.Canary.Probe = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "probe"}}
+		mod.Status.Upgrade.Canary = &kmmv1beta1.CanaryStatus{
+			Generation:    2,
+			Phase:         kmmv1beta1.CanarySoaking,
+			Nodes:         []string{"node1"},
+			SoakStartTime: &metav1.Time{Time: time.Now()},
+		}
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+		expectPods(podOnNode("node1", false))
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanaryFailed))
+		Expect(status.Canary.Message).To(Equal("waiting for the probe Pods to be ready on node node1"))
+		Expect(sdMap["node1"].action).To(Equal(actionRollback))
+		Expect(sdMap["node1"].config).To(Equal(&revision.Configs[0].ModuleConfig))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+	})
+
+	It("should roll back if the module failed to load on a canary node", func() {
+		mod.Status.Upgrade.Canary = &kmmv1beta1.CanaryStatus{
+			Generation: 2,
+			Phase:      kmmv1beta1.CanaryProgressing,
+			Nodes:      []string{"node1"},
+		}
+
+		failed := nmcWithImage("node1", newImage, oldImage)
+		failed.Status.Modules[0].LastFailure = &kmmv1beta1.WorkerFailure{
+			Action:   kmmv1beta1.WorkerActionLoad,
+			Reason:   kmmv1beta1.WorkerFailureReasonUnknownSymbol,
+			SpecHash: specHash(&failed.Spec.Modules[0]),
+		}
+
+		expectNMCs(failed, nmcWithImage("node2", oldImage, oldImage))
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, requeueAfter, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanaryFailed))
+		Expect(status.Canary.Message).To(Equal("the module failed to load on node node1: UnknownSymbol"))
+		Expect(status.PendingNumber).To(Equal(int32(2)))
+		Expect(status.UpdatedNumber).To(BeZero())
+		Expect(sdMap["node1"].action).To(Equal(actionRollback))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+	})
+
+	It("should record one revision config per set of ModuleNodeOverrides", func() {
+		mod.Status.Upgrade = nil
+
+		large := nmcWithImage("node1", newImage, newImage)
+		large.Spec.Modules[0].Config.Modprobe.Parameters = []string{"queues=8"}
+		large.Status.Modules[0].Config.Modprobe.Parameters = []string{"queues=8"}
+
+		expectNMCs(large, nmcWithImage("node2", newImage, newImage))
+
+		sdMap := addSchedulingData("node1", "node2")
+		sdMap["node1"].mld.Modprobe.Parameters = []string{"queues=8"}
+		sd := sdMap["node1"]
+		sd.overrides = []string{"large"}
+		sdMap["node1"] = sd
+
+		cr := expectSavedRevision()
+
+		_, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decodeRevision(cr).Configs).To(Equal([]kmmv1beta1.RevisionConfig{
+			{ModuleConfig: kmmv1beta1.ModuleConfig{ContainerImage: newImage}},
+			{
+				ModuleConfig: kmmv1beta1.ModuleConfig{
+					ContainerImage: newImage,
+					Modprobe:       kmmv1beta1.ModprobeSpec{Parameters: []string{"queues=8"}},
+				},
+				NodeOverrides: []string{"large"},
+			},
+		}))
+	})
+
+	It("should roll back each node to the config of its ModuleNodeOverrides", func() {
+		largeConfig := kmmv1beta1.ModuleConfig{
+			ContainerImage: oldImage,
+			Modprobe:       kmmv1beta1.ModprobeSpec{Parameters: []string{"queues=8"}},
+		}
+
+		revision.Configs = []kmmv1beta1.RevisionConfig{
+			{ModuleConfig: kmmv1beta1.ModuleConfig{ContainerImage: oldImage}},
+			{ModuleConfig: largeConfig, NodeOverrides: []string{"large"}},
+		}
+		mod.Status.Upgrade.Canary = &kmmv1beta1.CanaryStatus{
+			Generation: 2,
+			Phase:      kmmv1beta1.CanaryFailed,
+			Nodes:      []string{"node1"},
+		}
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage),
+			nmcWithImage("node2", newImage, newImage),
+		)
+
+		sdMap := addSchedulingData("node1", "node2")
+		sdMap["node1"].mld.Modprobe.Parameters = []string{"queues=16"}
+		sd := sdMap["node1"]
+		sd.overrides = []string{"large"}
+		sdMap["node1"] = sd
+
+		_, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sdMap["node1"].action).To(Equal(actionRollback))
+		Expect(sdMap["node1"].config).To(Equal(&largeConfig))
+		Expect(sdMap["node2"].action).To(Equal(actionRollback))
+		Expect(sdMap["node2"].config).To(Equal(&kmmv1beta1.ModuleConfig{ContainerImage: oldImage}))
+	})
+
+	It("should keep the rollback until the Module changes", func() {
+		mod.Status.Upgrade.Canary = &kmmv1beta1.CanaryStatus{
+			Generation: 2,
+			Phase:      kmmv1beta1.CanaryFailed,
+			Nodes:      []string{"node1"},
+		}
+
+		expectNMCs(
+			nmcWithImage("node1", oldImage, oldImage),
+			nmcWithImage("node2", oldImage, oldImage),
+		)
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Canary.Phase).To(Equal(kmmv1beta1.CanaryFailed))
+		Expect(status.Revision).To(Equal(revisionName))
+		Expect(sdMap["node1"]).To(Equal(schedulingData{}))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+	})
+})
+
+var _ = Describe("canaryNodes", func() {
+	sdMap := map[string]schedulingData{}
+	upgrades := []string{"node3", "node1", "node2", "node4"}
+
+	DescribeTable("should pick the first nodes in name order",
+		func(nodes *intstr.IntOrString, expected []string) {
+			Expect(
+				canaryNodes(&kmmv1beta1.CanaryStrategy{Nodes: nodes}, sdMap, upgrades, 10),
+			).To(
+				Equal(expected),
+			)
+		},
+		Entry("default", nil, []string{"node1"}),
+		Entry("number", ptr.To(intstr.FromInt32(2)), []string{"node1", "node2"}),
+		Entry("percentage rounded up", ptr.To(intstr.FromString("15%")), []string{"node1", "node2"}),
+		Entry("more than the upgrades", ptr.To(intstr.FromInt32(5)), []string{"node1", "node2", "node3", "node4"}),
+	)
+})
@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxConditionNames is the maximum number of nodes or images listed in the message of a Module condition.
+const maxConditionNames = 10
+
+// namesWithLimit joins the first maxConditionNames names, and tells how many were left out.
+func namesWithLimit(names []string) string {
+	if len(names) <= maxConditionNames {
+		return strings.Join(names, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxConditionNames], ", "), len(names)-maxConditionNames)
+}
+
+func setModuleCondition(mod *kmmv1beta1.Module, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&mod.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: mod.Generation,
+	})
+}
+
+// moduleFailedOnNode returns true if the kernel module failed to load or unload on the node of status.
+func moduleFailedOnNode(status *kmmv1beta1.NodeModuleStatus) bool {
+	if status == nil {
+		return false
+	}
+
+	for _, t := range []kmmv1beta1.NodeModuleConditionType{kmmv1beta1.NodeModuleConditionLoadFailed, kmmv1beta1.NodeModuleConditionUnloadFailed} {
+		if c := nmc.FindModuleCondition(status.Conditions, t); c != nil && c.Status == metav1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateConditions sets the ImagesReady, BuildFailed, SignFailed, Progressing, Available and Degraded conditions of
+// mod from its MIC, its MBSC, upgradeStatus and the status of the module in the NMCs.
+// It uses the module loader counters of mod, so it must be called after updateModuleLoaderStatus.
+func (mrh *moduleReconcilerHelper) updateConditions(
+	ctx context.Context,
+	mod *kmmv1beta1.Module,
+	upgradeStatus *kmmv1beta1.UpgradeStatus) error {
+
+	imagesReady, imagesFailed, err := mrh.updateImagesConditions(ctx, mod)
+	if err != nil {
+		return err
+	}
+
+	return mrh.updateRolloutConditions(ctx, mod, upgradeStatus, imagesReady, imagesFailed)
+}
+
+// updateImagesConditions sets the ImagesReady, BuildFailed and SignFailed conditions of mod.
+// It returns whether all images exist, and whether some images could not be built or signed.
+func (mrh *moduleReconcilerHelper) updateImagesConditions(ctx context.Context, mod *kmmv1beta1.Module) (bool, bool, error) {
+	micObj, err := mrh.micAPI.Get(ctx, mod.Name, mod.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, false, fmt.Errorf("failed to get MIC %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	mbscObj, err := mrh.mbscAPI.Get(ctx, mod.Name, mod.Namespace)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get MBSC %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	var (
+		missing  []string
+		pending  []string
+		notReady = make(map[string]bool)
+	)
+
+	if micObj != nil {
+		for _, spec := range micObj.Spec.Images {
+			switch mrh.micAPI.GetImageState(micObj, spec.Image) {
+			case kmmv1beta1.ImageExists:
+				continue
+			case kmmv1beta1.ImageDoesNotExist:
+				missing = append(missing, spec.Image)
+			default:
+				pending = append(pending, spec.Image)
+			}
+
+			notReady[spec.Image] = true
+		}
+	}
+
+	switch {
+	case micObj == nil:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionImagesReady, metav1.ConditionFalse, "ImagesPending",
+			"waiting for the ModuleImagesConfig to be created")
+	case len(missing) > 0:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionImagesReady, metav1.ConditionFalse, "ImagesMissing",
+			"images do not exist: "+namesWithLimit(missing))
+	case len(pending) > 0:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionImagesReady, metav1.ConditionFalse, "ImagesPending",
+			"waiting for images: "+namesWithLimit(pending))
+	default:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionImagesReady, metav1.ConditionTrue, "AllImagesExist",
+			fmt.Sprintf("all %d images exist", len(micObj.Spec.Images)))
+	}
+
+	var buildFailures, signFailures []string
+
+	if mbscObj != nil {
+		for _, state := range mbscObj.Status.Images {
+			// failures of images that were removed from the MIC, or that exist since then, are stale
+			if state.Status != kmmv1beta1.ActionFailure || !notReady[state.Image] {
+				continue
+			}
+
+			switch state.Action {
+			case kmmv1beta1.BuildImage:
+				buildFailures = append(buildFailures, state.Image)
+			case kmmv1beta1.SignImage:
+				signFailures = append(signFailures, state.Image)
+			}
+		}
+	}
+
+	if len(buildFailures) > 0 {
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionBuildFailed, metav1.ConditionTrue, "BuildFailed",
+			"failed to build images: "+namesWithLimit(buildFailures))
+	} else {
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionBuildFailed, metav1.ConditionFalse, "NoFailure", "")
+	}
+
+	if len(signFailures) > 0 {
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionSignFailed, metav1.ConditionTrue, "SignFailed",
+			"failed to sign images: "+namesWithLimit(signFailures))
+	} else {
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionSignFailed, metav1.ConditionFalse, "NoFailure", "")
+	}
+
+	imagesReady := micObj != nil && len(notReady) == 0
+	imagesFailed := len(buildFailures) > 0 || len(signFailures) > 0
+
+	return imagesReady, imagesFailed, nil
+}
+
+// updateRolloutConditions sets the Progressing, Available and Degraded conditions of mod.
+func (mrh *moduleReconcilerHelper) updateRolloutConditions(
+	ctx context.Context,
+	mod *kmmv1beta1.Module,
+	upgradeStatus *kmmv1beta1.UpgradeStatus,
+	imagesReady bool,
+	imagesFailed bool) error {
+
+	nmcs, err := mrh.getNMCsForModule(ctx, mod)
+	if err != nil {
+		return fmt.Errorf("failed to get configured NMCs for module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	failedNodes := make([]string, 0)
+
+	for i := range nmcs {
+		if moduleFailedOnNode(mrh.nmcHelper.GetModuleStatusEntry(&nmcs[i], mod.Namespace, mod.Name)) {
+			failedNodes = append(failedNodes, nmcs[i].Name)
+		}
+	}
+
+	sort.Strings(failedNodes)
+
+	var (
+		desired      = int(mod.Status.ModuleLoader.DesiredNumber)
+		available    = int(mod.Status.ModuleLoader.AvailableNumber)
+		pending      int
+		canaryFailed bool
+	)
+
+	if upgradeStatus != nil {
+		pending = int(upgradeStatus.PendingNumber)
+		canaryFailed = upgradeStatus.Canary != nil && upgradeStatus.Canary.Phase == kmmv1beta1.CanaryFailed
+	}
+
+	nodesMessage := fmt.Sprintf("%d of %d nodes available", available, desired)
+	if pending > 0 {
+		nodesMessage += fmt.Sprintf(", %d nodes waiting for the new config", pending)
+	}
+
+	switch {
+	case len(failedNodes) > 0:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionDegraded, metav1.ConditionTrue, "NodesFailed",
+			fmt.Sprintf("kernel module failed on %d nodes: %s", len(failedNodes), namesWithLimit(failedNodes)))
+	case canaryFailed:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionDegraded, metav1.ConditionTrue, "CanaryFailed",
+			upgradeStatus.Canary.Message)
+	case imagesFailed:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionDegraded, metav1.ConditionTrue, "ImagesFailed",
+			"some images could not be built or signed")
+	default:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionDegraded, metav1.ConditionFalse, "AsExpected", "")
+	}
+
+	switch {
+	case !imagesReady:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionAvailable, metav1.ConditionFalse, "ImagesNotReady", nodesMessage)
+	case available < desired || pending > 0:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionAvailable, metav1.ConditionFalse, "NodesNotAvailable", nodesMessage)
+	default:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionAvailable, metav1.ConditionTrue, "AllNodesAvailable", nodesMessage)
+	}
+
+	switch {
+	case (!imagesReady && !imagesFailed) || available+len(failedNodes) < desired || (pending > 0 && !canaryFailed):
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionProgressing, metav1.ConditionTrue, "RollingOut", nodesMessage)
+	case imagesReady && available == desired && pending == 0:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionProgressing, metav1.ConditionFalse, "RolloutComplete", nodesMessage)
+	default:
+		setModuleCondition(mod, kmmv1beta1.ModuleConditionProgressing, metav1.ConditionFalse, "RolloutStalled", nodesMessage)
+	}
+
+	return nil
+}
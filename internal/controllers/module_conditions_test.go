@@ -0,0 +1,320 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mbsc"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mic"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("namesWithLimit", func() {
+	It("should join all names if there are not too many", func() {
+		Expect(namesWithLimit([]string{"a", "b"})).To(Equal("a, b"))
+	})
+
+	It("should tell how many names were left out", func() {
+		names := make([]string, 0, maxConditionNames+2)
+		for i := 0; i < maxConditionNames+2; i++ {
+			names = append(names, fmt.Sprintf("n%d", i))
+		}
+
+		Expect(namesWithLimit(names)).To(Equal("n0, n1, n2, n3, n4, n5, n6, n7, n8, n9 and 2 more"))
+	})
+})
+
+var _ = Describe("updateImagesConditions", func() {
+	var (
+		ctx         context.Context
+		mockMIC     *mic.MockMIC
+		mockMBSC    *mbsc.MockMBSC
+		mod         kmmv1beta1.Module
+		mrh         *moduleReconcilerHelper
+		micObj      *kmmv1beta1.ModuleImagesConfig
+		conditionOf = func(conditionType string) *metav1.Condition {
+			for i := range mod.Status.Conditions {
+				if mod.Status.Conditions[i].Type == conditionType {
+					return &mod.Status.Conditions[i]
+				}
+			}
+			return nil
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		mockMIC = mic.NewMockMIC(ctrl)
+		mockMBSC = mbsc.NewMockMBSC(ctrl)
+		mrh = &moduleReconcilerHelper{micAPI: mockMIC, mbscAPI: mockMBSC}
+		mod = kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: "mod", Namespace: "ns", Generation: 3},
+		}
+		micObj = &kmmv1beta1.ModuleImagesConfig{
+			Spec: kmmv1beta1.ModuleImagesConfigSpec{
+				Images: []kmmv1beta1.ModuleImageSpec{{Image: "image1"}, {Image: "image2"}},
+			},
+		}
+	})
+
+	It("should return an error if the MIC cannot be fetched", func() {
+		mockMIC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, errors.New("random error"))
+
+		_, _, err := mrh.updateImagesConditions(ctx, &mod)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the MBSC cannot be fetched", func() {
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(micObj, nil),
+			mockMBSC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, errors.New("random error")),
+		)
+
+		_, _, err := mrh.updateImagesConditions(ctx, &mod)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should wait for the MIC if it does not exist yet", func() {
+		notFound := apierrors.NewNotFound(schema.GroupResource{}, mod.Name)
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, notFound),
+			mockMBSC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, nil),
+		)
+
+		imagesReady, imagesFailed, err := mrh.updateImagesConditions(ctx, &mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(imagesReady).To(BeFalse())
+		Expect(imagesFailed).To(BeFalse())
+		Expect(conditionOf(kmmv1beta1.ModuleConditionImagesReady)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", "ImagesPending"),
+			HaveField("ObservedGeneration", int64(3)),
+		))
+	})
+
+	It("should report that all images exist", func() {
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(micObj, nil),
+			mockMBSC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, nil),
+			mockMIC.EXPECT().GetImageState(micObj, "image1").Return(kmmv1beta1.ImageExists),
+			mockMIC.EXPECT().GetImageState(micObj, "image2").Return(kmmv1beta1.ImageExists),
+		)
+
+		imagesReady, imagesFailed, err := mrh.updateImagesConditions(ctx, &mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(imagesReady).To(BeTrue())
+		Expect(imagesFailed).To(BeFalse())
+		Expect(mod.Status.Conditions).To(ConsistOf(
+			And(
+				HaveField("Type", kmmv1beta1.ModuleConditionImagesReady),
+				HaveField("Status", metav1.ConditionTrue),
+				HaveField("Reason", "AllImagesExist"),
+			),
+			And(
+				HaveField("Type", kmmv1beta1.ModuleConditionBuildFailed),
+				HaveField("Status", metav1.ConditionFalse),
+			),
+			And(
+				HaveField("Type", kmmv1beta1.ModuleConditionSignFailed),
+				HaveField("Status", metav1.ConditionFalse),
+			),
+		))
+	})
+
+	It("should report the images that could not be built or signed", func() {
+		mbscObj := &kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{Image: "image1", Status: kmmv1beta1.ActionFailure, Action: kmmv1beta1.BuildImage},
+					{Image: "image2", Status: kmmv1beta1.ActionFailure, Action: kmmv1beta1.SignImage},
+					{Image: "removed-image", Status: kmmv1beta1.ActionFailure, Action: kmmv1beta1.BuildImage},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(micObj, nil),
+			mockMBSC.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(mbscObj, nil),
+			mockMIC.EXPECT().GetImageState(micObj, "image1").Return(kmmv1beta1.ImageDoesNotExist),
+			mockMIC.EXPECT().GetImageState(micObj, "image2").Return(kmmv1beta1.ImageState("")),
+		)
+
+		imagesReady, imagesFailed, err := mrh.updateImagesConditions(ctx, &mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(imagesReady).To(BeFalse())
+		Expect(imagesFailed).To(BeTrue())
+		Expect(conditionOf(kmmv1beta1.ModuleConditionImagesReady)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", "ImagesMissing"),
+			HaveField("Message", "images do not exist: image1"),
+		))
+		Expect(conditionOf(kmmv1beta1.ModuleConditionBuildFailed)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Message", "failed to build images: image1"),
+		))
+		Expect(conditionOf(kmmv1beta1.ModuleConditionSignFailed)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Message", "failed to sign images: image2"),
+		))
+	})
+})
+
+var _ = Describe("updateRolloutConditions", func() {
+	var (
+		ctx         context.Context
+		clnt        *client.MockClient
+		helper      *nmc.MockHelper
+		mod         kmmv1beta1.Module
+		mrh         *moduleReconcilerHelper
+		conditionOf = func(conditionType string) *metav1.Condition {
+			for i := range mod.Status.Conditions {
+				if mod.Status.Conditions[i].Type == conditionType {
+					return &mod.Status.Conditions[i]
+				}
+			}
+			return nil
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		helper = nmc.NewMockHelper(ctrl)
+		mrh = &moduleReconcilerHelper{client: clnt, nmcHelper: helper}
+		mod = kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: "mod", Namespace: "ns"},
+		}
+	})
+
+	listNMCs := func(nmcs ...kmmv1beta1.NodeModulesConfig) {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+				list.Items = nmcs
+				return nil
+			},
+		)
+	}
+
+	It("should return an error if the NMCs cannot be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+
+		Expect(
+			mrh.updateRolloutConditions(ctx, &mod, nil, true, false),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should report a complete rollout", func() {
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+
+		listNMCs(nmc1)
+		helper.EXPECT().GetModuleStatusEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{})
+		mod.Status.ModuleLoader = kmmv1beta1.DaemonSetStatus{DesiredNumber: 1, AvailableNumber: 1}
+
+		Expect(
+			mrh.updateRolloutConditions(ctx, &mod, nil, true, false),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(conditionOf(kmmv1beta1.ModuleConditionAvailable)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", "AllNodesAvailable"),
+			HaveField("Message", "1 of 1 nodes available"),
+		))
+		Expect(conditionOf(kmmv1beta1.ModuleConditionProgressing)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", "RolloutComplete"),
+		))
+		Expect(conditionOf(kmmv1beta1.ModuleConditionDegraded)).To(HaveField("Status", metav1.ConditionFalse))
+	})
+
+	It("should report a rollout in progress", func() {
+		listNMCs()
+		mod.Status.ModuleLoader = kmmv1beta1.DaemonSetStatus{DesiredNumber: 2, AvailableNumber: 1}
+		upgradeStatus := &kmmv1beta1.UpgradeStatus{PendingNumber: 1}
+
+		Expect(
+			mrh.updateRolloutConditions(ctx, &mod, upgradeStatus, true, false),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(conditionOf(kmmv1beta1.ModuleConditionAvailable)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", "NodesNotAvailable"),
+			HaveField("Message", "1 of 2 nodes available, 1 nodes waiting for the new config"),
+		))
+		Expect(conditionOf(kmmv1beta1.ModuleConditionProgressing)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", "RollingOut"),
+		))
+	})
+
+	It("should report the nodes on which the kernel module failed", func() {
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+		nmc2 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "node2"}}
+
+		listNMCs(nmc1, nmc2)
+		gomock.InOrder(
+			helper.EXPECT().GetModuleStatusEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{}),
+			helper.EXPECT().GetModuleStatusEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{
+				Conditions: []kmmv1beta1.NodeModuleCondition{
+					{Type: kmmv1beta1.NodeModuleConditionLoadFailed, Status: metav1.ConditionTrue},
+				},
+			}),
+		)
+		mod.Status.ModuleLoader = kmmv1beta1.DaemonSetStatus{DesiredNumber: 2, AvailableNumber: 1}
+
+		Expect(
+			mrh.updateRolloutConditions(ctx, &mod, nil, true, false),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(conditionOf(kmmv1beta1.ModuleConditionDegraded)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", "NodesFailed"),
+			HaveField("Message", "kernel module failed on 1 nodes: node2"),
+		))
+		Expect(conditionOf(kmmv1beta1.ModuleConditionProgressing)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", "RolloutStalled"),
+		))
+	})
+
+	It("should report a failed canary", func() {
+		listNMCs()
+		mod.Status.ModuleLoader = kmmv1beta1.DaemonSetStatus{DesiredNumber: 2, AvailableNumber: 2}
+		upgradeStatus := &kmmv1beta1.UpgradeStatus{
+			PendingNumber: 1,
+			Canary:        &kmmv1beta1.CanaryStatus{Phase: kmmv1beta1.CanaryFailed, Message: "health check failed"},
+		}
+
+		Expect(
+			mrh.updateRolloutConditions(ctx, &mod, upgradeStatus, true, false),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(conditionOf(kmmv1beta1.ModuleConditionDegraded)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", "CanaryFailed"),
+			HaveField("Message", "health check failed"),
+		))
+		Expect(conditionOf(kmmv1beta1.ModuleConditionProgressing)).To(HaveField("Reason", "RolloutStalled"))
+	})
+})
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getNodeOverrides returns the ModuleNodeOverrides of mod, in the order in which they should be merged.
+func (mrh *moduleReconcilerHelper) getNodeOverrides(ctx context.Context, mod *kmmv1beta1.Module) ([]kmmv1beta1.ModuleNodeOverride, error) {
+	list := kmmv1beta1.ModuleNodeOverrideList{}
+
+	if err := mrh.client.List(ctx, &list, client.InNamespace(mod.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not list ModuleNodeOverrides in namespace %s: %v", mod.Namespace, err)
+	}
+
+	overrides := make([]kmmv1beta1.ModuleNodeOverride, 0, len(list.Items))
+
+	for _, o := range list.Items {
+		if o.Spec.ModuleName == mod.Name {
+			overrides = append(overrides, o)
+		}
+	}
+
+	sort.SliceStable(overrides, func(i, j int) bool {
+		if overrides[i].Spec.Priority != overrides[j].Spec.Priority {
+			return overrides[i].Spec.Priority < overrides[j].Spec.Priority
+		}
+
+		return overrides[i].Name < overrides[j].Name
+	})
+
+	return overrides, nil
+}
+
+// applyNodeOverrides merges the overrides that select node into the modprobe settings of mld, in order.
+// It returns the names of the overrides that were merged.
+func applyNodeOverrides(mld *api.ModuleLoaderData, node *v1.Node, overrides []kmmv1beta1.ModuleNodeOverride) ([]string, error) {
+	var applied []string
+
+	for _, o := range overrides {
+		selected, err := utils.IsObjectSelectedByLabels(node.GetLabels(), o.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("could not determine if node %s is selected by ModuleNodeOverride %s: %v", node.Name, o.Name, err)
+		}
+
+		if !selected {
+			continue
+		}
+
+		applied = append(applied, o.Name)
+
+		modprobe := &mld.Modprobe
+
+		if o.Spec.Parameters != nil {
+			modprobe.Parameters = slices.Clone(o.Spec.Parameters)
+		}
+
+		if len(o.Spec.ExtraParameters) > 0 {
+			modprobe.Parameters = slices.Concat(modprobe.Parameters, o.Spec.ExtraParameters)
+		}
+
+		if o.Spec.FirmwarePath != "" {
+			modprobe.FirmwarePath = o.Spec.FirmwarePath
+		}
+
+		if o.Spec.Args != nil {
+			modprobe.Args = o.Spec.Args.DeepCopy()
+		}
+	}
+
+	return applied, nil
+}
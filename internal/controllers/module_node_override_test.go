@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/module"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("getNodeOverrides", func() {
+	var (
+		ctx  context.Context
+		clnt *client.MockClient
+		mrh  *moduleReconcilerHelper
+		mod  *kmmv1beta1.Module
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		mrh = &moduleReconcilerHelper{client: clnt}
+		mod = &kmmv1beta1.Module{ObjectMeta: metav1.ObjectMeta{Name: "mod", Namespace: "ns"}}
+	})
+
+	It("should return an error if the overrides cannot be listed", func() {
+		clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleNodeOverrideList{}, ctrlclient.InNamespace("ns")).Return(errors.New("random error"))
+
+		_, err := mrh.getNodeOverrides(ctx, mod)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only return the overrides of the Module, by priority then name", func() {
+		newOverride := func(name, moduleName string, priority int32) kmmv1beta1.ModuleNodeOverride {
+			return kmmv1beta1.ModuleNodeOverride{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+				Spec:       kmmv1beta1.ModuleNodeOverrideSpec{ModuleName: moduleName, Priority: priority},
+			}
+		}
+
+		clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleNodeOverrideList{}, ctrlclient.InNamespace("ns")).DoAndReturn(
+			func(_ context.Context, list *kmmv1beta1.ModuleNodeOverrideList, _ ...ctrlclient.ListOption) error {
+				list.Items = []kmmv1beta1.ModuleNodeOverride{
+					newOverride("c", "mod", 10),
+					newOverride("b", "mod", 0),
+					newOverride("other", "other-mod", 0),
+					newOverride("a", "mod", 0),
+				}
+				return nil
+			},
+		)
+
+		overrides, err := mrh.getNodeOverrides(ctx, mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(overrides).To(HaveExactElements(
+			HaveField("Name", "a"),
+			HaveField("Name", "b"),
+			HaveField("Name", "c"),
+		))
+	})
+})
+
+var _ = Describe("applyNodeOverrides", func() {
+	var (
+		mld  *api.ModuleLoaderData
+		node *v1.Node
+	)
+
+	BeforeEach(func() {
+		mld = &api.ModuleLoaderData{
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				ModuleName:   "kmod",
+				Parameters:   []string{"a=1", "b=2"},
+				FirmwarePath: "/firmware",
+			},
+		}
+		node = &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"pool": "large"}},
+		}
+	})
+
+	It("should ignore overrides that do not select the node", func() {
+		overrides := []kmmv1beta1.ModuleNodeOverride{
+			{
+				Spec: kmmv1beta1.ModuleNodeOverrideSpec{
+					Selector:   map[string]string{"pool": "small"},
+					Parameters: []string{"a=3"},
+				},
+			},
+		}
+
+		applied, err := applyNodeOverrides(mld, node, overrides)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(BeEmpty())
+		Expect(mld.Modprobe.Parameters).To(Equal([]string{"a=1", "b=2"}))
+	})
+
+	It("should merge the overrides in order", func() {
+		params := mld.Modprobe.Parameters
+
+		overrides := []kmmv1beta1.ModuleNodeOverride{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "all"},
+				Spec: kmmv1beta1.ModuleNodeOverrideSpec{
+					ExtraParameters: []string{"c=3"},
+					FirmwarePath:    "/other-firmware",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "large"},
+				Spec: kmmv1beta1.ModuleNodeOverrideSpec{
+					Selector:        map[string]string{"pool": "large"},
+					Parameters:      []string{"a=4"},
+					ExtraParameters: []string{"d=5"},
+					Args:            &kmmv1beta1.ModprobeArgs{Load: []string{"-v"}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "last"},
+				Spec: kmmv1beta1.ModuleNodeOverrideSpec{
+					ExtraParameters: []string{"e=6"},
+				},
+			},
+		}
+
+		applied, err := applyNodeOverrides(mld, node, overrides)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(Equal([]string{"all", "large", "last"}))
+		Expect(mld.Modprobe).To(Equal(kmmv1beta1.ModprobeSpec{
+			ModuleName:   "kmod",
+			Parameters:   []string{"a=4", "d=5", "e=6"},
+			FirmwarePath: "/other-firmware",
+			Args:         &kmmv1beta1.ModprobeArgs{Load: []string{"-v"}},
+		}))
+		Expect(params).To(Equal([]string{"a=1", "b=2"}))
+	})
+})
+
+var _ = Describe("prepareSchedulingData with ModuleNodeOverrides", func() {
+	var (
+		ctx        context.Context
+		clnt       *client.MockClient
+		mockKernel *module.MockKernelMapper
+		mrh        moduleReconcilerHelperAPI
+		mod        *kmmv1beta1.Module
+		node       v1.Node
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ctx = context.Background()
+		clnt = client.NewMockClient(ctrl)
+		mockKernel = module.NewMockKernelMapper(ctrl)
+		mrh = newModuleReconcilerHelper(clnt, mockKernel, nil, nil, nil, nil, scheme, "")
+		mod = &kmmv1beta1.Module{ObjectMeta: metav1.ObjectMeta{Name: "mod", Namespace: "ns"}}
+		node = v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node"},
+			Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KernelVersion: "kernel"}},
+		}
+	})
+
+	It("should not change the NMCs of targeted nodes if the overrides cannot be listed", func() {
+		clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleNodeOverrideList{}, gomock.Any()).Return(errors.New("random error"))
+
+		sdMap, errs := mrh.prepareSchedulingData(ctx, mod, []v1.Node{node}, sets.New("node", "other-node"))
+		Expect(errs).To(HaveLen(1))
+		Expect(sdMap).To(Equal(map[string]schedulingData{"other-node": {action: actionDelete}}))
+	})
+
+	It("should merge the overrides into the mld of the node", func() {
+		mld := api.ModuleLoaderData{Name: "mod", Namespace: "ns", KernelVersion: "kernel"}
+
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleNodeOverrideList{}, gomock.Any()).DoAndReturn(
+				func(_ context.Context, list *kmmv1beta1.ModuleNodeOverrideList, _ ...ctrlclient.ListOption) error {
+					list.Items = []kmmv1beta1.ModuleNodeOverride{
+						{Spec: kmmv1beta1.ModuleNodeOverrideSpec{ModuleName: "mod", Parameters: []string{"queues=8"}}},
+					}
+					return nil
+				},
+			),
+			mockKernel.EXPECT().GetModuleLoaderDataForKernel(mod, "kernel").Return(&mld, nil),
+		)
+
+		sdMap, errs := mrh.prepareSchedulingData(ctx, mod, []v1.Node{node}, sets.New[string]())
+		Expect(errs).To(BeEmpty())
+		Expect(sdMap["node"].mld.Modprobe.Parameters).To(Equal([]string{"queues=8"}))
+	})
+})
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/filter"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/mbsc"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/meta"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/mic"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/module"
@@ -19,19 +23,24 @@ import (
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+//+kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=create;delete;get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=create;delete;deletecollection;get;list;patch;watch
 //+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=clusterclaims,resourceNames=kernel-versions.kmm.node.kubernetes.io,verbs=delete;patch;update
 //+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=clusterclaims,verbs=create;get;list;watch
@@ -39,12 +48,14 @@ import (
 //+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;patch;watch
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=create;delete;get;list;patch;watch
+//+kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modulebuildsignconfigs,verbs=get;list;watch;update;patch;create;delete
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modulebuildsignconfigs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=moduleimagesconfigs,verbs=get;list;watch;patch;create;delete
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=moduleimagesconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modulenodeoverrides,verbs=get;list;watch
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modules,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modules/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=nodemodulesconfigs,verbs=get;list;watch;patch;create;delete
@@ -57,12 +68,17 @@ const (
 	ModuleReconcilerName = "ModuleReconciler"
 	actionDelete         = "delete"
 	actionAdd            = "add"
+	actionRollback       = "rollback"
 )
 
 type schedulingData struct {
 	action string
 	mld    *api.ModuleLoaderData
 	node   *v1.Node
+	// config is the module config to restore on the node, for the rollback action
+	config *kmmv1beta1.ModuleConfig
+	// overrides are the names of the ModuleNodeOverrides merged into mld
+	overrides []string
 }
 
 type ModuleReconciler struct {
@@ -79,8 +95,11 @@ func NewModuleReconciler(client client.Client,
 	filter *filter.Filter,
 	nodeAPI node.Node,
 	micAPI mic.MIC,
-	scheme *runtime.Scheme) *ModuleReconciler {
-	reconHelper := newModuleReconcilerHelper(client, kernelAPI, micAPI, nmcHelper, scheme)
+	mbscAPI mbsc.MBSC,
+	recorder record.EventRecorder,
+	scheme *runtime.Scheme,
+	conflictPolicy config.ConflictPolicy) *ModuleReconciler {
+	reconHelper := newModuleReconcilerHelper(client, kernelAPI, micAPI, mbscAPI, nmcHelper, recorder, scheme, conflictPolicy)
 	return &ModuleReconciler{
 		filter:      filter,
 		nsLabeler:   newNamespaceLabeler(client),
@@ -137,27 +156,34 @@ func (mr *ModuleReconciler) Reconcile(ctx context.Context, mod *kmmv1beta1.Modul
 	}
 
 	sdMap, prepareErrs := mr.reconHelper.prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs)
+
+	upgradeStatus, requeueAfter, err := mr.reconHelper.applyUpgradeStrategy(ctx, mod, sdMap)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply the upgrade strategy of Module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
 	errs := make([]error, 0, len(sdMap)+1)
 	errs = append(errs, prepareErrs...)
 
 	for nodeName, sd := range sdMap {
-		if sd.action == actionAdd {
-			err = mr.reconHelper.enableModuleOnNode(ctx, sd.mld, sd.node)
+		switch sd.action {
+		case actionAdd:
+			errs = append(errs, mr.reconHelper.enableModuleOnNode(ctx, sd.mld, sd.node))
+		case actionDelete:
+			errs = append(errs, mr.reconHelper.disableModuleOnNode(ctx, mod.Namespace, mod.Name, nodeName))
+		case actionRollback:
+			errs = append(errs, mr.reconHelper.rollbackModuleOnNode(ctx, sd.mld, sd.config, sd.node))
 		}
-		if sd.action == actionDelete {
-			err = mr.reconHelper.disableModuleOnNode(ctx, mod.Namespace, mod.Name, nodeName)
-		}
-		errs = append(errs, err)
 	}
 
-	err = mr.reconHelper.updateModuleStatus(ctx, mod, targetedNodes)
+	err = mr.reconHelper.updateModuleStatus(ctx, mod, targetedNodes, upgradeStatus)
 	errs = append(errs, err)
 
 	err = errors.Join(errs...)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile module %s/%s config: %v", mod.Namespace, mod.Name, err)
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (mr *ModuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -177,6 +203,15 @@ func (mr *ModuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&kmmv1beta1.NodeModulesConfig{},
 			handler.EnqueueRequestsFromMapFunc(filter.ListModulesForNMC),
 		).
+		Watches(
+			&kmmv1beta1.ModuleNodeOverride{},
+			filter.ModuleNodeOverrideHandler(),
+		).
+		Watches(
+			&kmmv1beta1.Module{},
+			handler.EnqueueRequestsFromMapFunc(mr.filter.FindConflictingModules),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Named(ModuleReconcilerName).
 		Complete(
 			reconcile.AsReconciler[*kmmv1beta1.Module](mgr.GetClient(), mr),
@@ -191,31 +226,43 @@ type moduleReconcilerHelperAPI interface {
 	finalizeModule(ctx context.Context, mod *kmmv1beta1.Module) error
 	getNMCsByModuleSet(ctx context.Context, mod *kmmv1beta1.Module) (sets.Set[string], error)
 	prepareSchedulingData(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node, currentNMCs sets.Set[string]) (map[string]schedulingData, []error)
+	applyUpgradeStrategy(ctx context.Context, mod *kmmv1beta1.Module, sdMap map[string]schedulingData) (*kmmv1beta1.UpgradeStatus, time.Duration, error)
 	enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error
+	rollbackModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, config *kmmv1beta1.ModuleConfig, node *v1.Node) error
 	disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error
-	updateModuleStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error
+	updateModuleStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node, upgradeStatus *kmmv1beta1.UpgradeStatus) error
 }
 
 type moduleReconcilerHelper struct {
 	client    client.Client
 	kernelAPI module.KernelMapper
 	micAPI    mic.MIC
+	mbscAPI   mbsc.MBSC
 	nmcHelper nmc.Helper
+	recorder  record.EventRecorder
 	scheme    *runtime.Scheme
+	// conflictPolicy is the policy applied to Modules that use the same kernel module on a node.
+	conflictPolicy config.ConflictPolicy
 }
 
 func newModuleReconcilerHelper(
 	client client.Client,
 	kernelAPI module.KernelMapper,
 	micAPI mic.MIC,
+	mbscAPI mbsc.MBSC,
 	nmcHelper nmc.Helper,
-	scheme *runtime.Scheme) moduleReconcilerHelperAPI {
+	recorder record.EventRecorder,
+	scheme *runtime.Scheme,
+	conflictPolicy config.ConflictPolicy) moduleReconcilerHelperAPI {
 	return &moduleReconcilerHelper{
-		client:    client,
-		kernelAPI: kernelAPI,
-		micAPI:    micAPI,
-		nmcHelper: nmcHelper,
-		scheme:    scheme,
+		client:         client,
+		kernelAPI:      kernelAPI,
+		micAPI:         micAPI,
+		mbscAPI:        mbscAPI,
+		nmcHelper:      nmcHelper,
+		recorder:       recorder,
+		scheme:         scheme,
+		conflictPolicy: conflictPolicy,
 	}
 }
 
@@ -234,10 +281,6 @@ func (mrh *moduleReconcilerHelper) setFinalizerAndStatus(ctx context.Context, mo
 		return fmt.Errorf("failed to set finalizer for module %s/%s: %v", mod.Namespace, mod.Name, err)
 	}
 
-	//mod.Status.ModuleLoader.NodesMatchingSelectorNumber = 0
-	//mod.Status.ModuleLoader.DesiredNumber = 0
-	//mod.Status.ModuleLoader.AvailableNumber = 0
-
 	return mrh.client.Status().Update(ctx, mod)
 }
 
@@ -316,6 +359,7 @@ func (mrh *moduleReconcilerHelper) getNMCsForModule(ctx context.Context, mod *km
 
 // prepareSchedulingData prepare data needed to scheduling enable/disable module per node
 // in case there is an error during handling one of the nodes, function continues to the next node
+// The ModuleNodeOverrides of the Module that select a node are merged into the modprobe settings of its mld.
 // It returns the map of scheduling data per successfully processed node, and slice of errors
 // per unsuccessfuly processed nodes
 func (mrh *moduleReconcilerHelper) prepareSchedulingData(ctx context.Context,
@@ -326,6 +370,17 @@ func (mrh *moduleReconcilerHelper) prepareSchedulingData(ctx context.Context,
 	logger := log.FromContext(ctx)
 	result := make(map[string]schedulingData)
 	errs := make([]error, 0, len(targetedNodes))
+
+	overrides, err := mrh.getNodeOverrides(ctx, mod)
+	if err != nil {
+		// the config of targeted nodes cannot be determined without the overrides; do not change their NMC
+		for _, node := range targetedNodes {
+			currentNMCs.Delete(node.Name)
+		}
+		errs = append(errs, err)
+		targetedNodes = nil
+	}
+
 	for _, node := range targetedNodes {
 		kernelVersion := strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+")
 		mld, err := mrh.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
@@ -338,7 +393,19 @@ func (mrh *moduleReconcilerHelper) prepareSchedulingData(ctx context.Context,
 			errs = append(errs, err)
 			continue
 		}
-		result[node.Name] = prepareNodeSchedulingData(node, mld, currentNMCs)
+		var applied []string
+		if mld != nil {
+			if applied, err = applyNodeOverrides(mld, &node, overrides); err != nil {
+				currentNMCs.Delete(node.Name)
+				errs = append(errs, err)
+				continue
+			}
+		}
+		sd := prepareNodeSchedulingData(node, mld, currentNMCs)
+		if sd.action == actionAdd {
+			sd.overrides = applied
+		}
+		result[node.Name] = sd
 		currentNMCs.Delete(node.Name)
 	}
 	for _, nmcName := range currentNMCs.UnsortedList() {
@@ -347,6 +414,180 @@ func (mrh *moduleReconcilerHelper) prepareSchedulingData(ctx context.Context,
 	return result, errs
 }
 
+// applyUpgradeStrategy holds back the nodes of sdMap on which the module config would change, so that no more than
+// .spec.upgradeStrategy.maxUnavailable nodes are unavailable at the same time.
+// Nodes on which the module is not configured yet, or that booted another kernel than the one of their current config,
+// are not held back.
+// With a canary, only the canary nodes are upgraded until the canary succeeded.
+// Once all nodes run the same module generation, its configs are recorded as the revision to roll back to, in a
+// ControllerRevision owned by the Module; superseded ControllerRevisions are then deleted.
+// It returns the progress of the upgrade, and the delay after which the Module should be reconciled again for nodes
+// that are not available yet because of minReadySeconds, or to check the canary.
+func (mrh *moduleReconcilerHelper) applyUpgradeStrategy(
+	ctx context.Context,
+	mod *kmmv1beta1.Module,
+	sdMap map[string]schedulingData) (*kmmv1beta1.UpgradeStatus, time.Duration, error) {
+
+	strategy := mod.Spec.UpgradeStrategy
+	if strategy == nil {
+		return nil, 0, nil
+	}
+
+	logger := log.FromContext(ctx)
+
+	nmcs, err := mrh.getNMCsForModule(ctx, mod)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get configured NMCs for module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	nmcByName := make(map[string]*kmmv1beta1.NodeModulesConfig, len(nmcs))
+	for i := range nmcs {
+		nmcByName[nmcs[i].Name] = &nmcs[i]
+	}
+
+	var (
+		desired      int
+		requeueAfter time.Duration
+		status       kmmv1beta1.UpgradeStatus
+		upgrades     []string
+		minReady     = time.Duration(strategy.MinReadySeconds) * time.Second
+	)
+
+	revision, err := mrh.getRevision(ctx, mod)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// the revision that the current status of mod refers to
+	var prevRevision string
+
+	if prev := mod.Status.Upgrade; prev != nil {
+		prevRevision = prev.Revision
+		status.Revision = prevRevision
+	}
+
+	for nodeName, sd := range sdMap {
+		if sd.action != actionAdd {
+			continue
+		}
+
+		desired++
+
+		nmcObj := nmcByName[nodeName]
+		if nmcObj == nil {
+			status.UpdatedNumber++
+			continue
+		}
+
+		spec, _ := mrh.nmcHelper.GetModuleSpecEntry(nmcObj, mod.Namespace, mod.Name)
+		if spec == nil {
+			status.UpdatedNumber++
+			continue
+		}
+
+		// the node booted another kernel: no module is loaded there that the new config would replace
+		if spec.Config.KernelVersion != sd.mld.KernelVersion {
+			status.UpdatedNumber++
+			continue
+		}
+
+		if !reflect.DeepEqual(spec.Config, moduleConfigFromMLD(sd.mld)) {
+			upgrades = append(upgrades, nodeName)
+			continue
+		}
+
+		status.UpdatedNumber++
+
+		if d := unavailableFor(spec, mrh.nmcHelper.GetModuleStatusEntry(nmcObj, mod.Namespace, mod.Name), minReady); d != 0 {
+			status.UnavailableNumber++
+
+			if d > 0 && (requeueAfter == 0 || d < requeueAfter) {
+				requeueAfter = d
+			}
+		}
+	}
+
+	maxUnavailable := 1
+
+	if strategy.MaxUnavailable != nil {
+		if maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(strategy.MaxUnavailable, desired, false); err != nil {
+			return nil, 0, fmt.Errorf("invalid maxUnavailable: %v", err)
+		}
+
+		maxUnavailable = max(maxUnavailable, 1)
+	}
+
+	if strategy.Canary != nil {
+		var canaryRequeueAfter time.Duration
+
+		upgrades, canaryRequeueAfter, err = mrh.applyCanary(ctx, mod, sdMap, nmcByName, &status, revision, upgrades, desired)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if canaryRequeueAfter > 0 && (requeueAfter == 0 || canaryRequeueAfter < requeueAfter) {
+			requeueAfter = canaryRequeueAfter
+		}
+
+		if c := status.Canary; c != nil && c.Phase == kmmv1beta1.CanaryFailed {
+			return &status, requeueAfter, nil
+		}
+	}
+
+	// upgrade nodes in a stable order
+	sort.Strings(upgrades)
+
+	for _, nodeName := range upgrades {
+		if int(status.UnavailableNumber) >= maxUnavailable {
+			logger.V(1).Info("Holding back the new module config", "node", nodeName)
+			sdMap[nodeName] = schedulingData{}
+			status.PendingNumber++
+			continue
+		}
+
+		status.UnavailableNumber++
+		status.UpdatedNumber++
+	}
+
+	if len(upgrades) == 0 && status.PendingNumber == 0 && status.UnavailableNumber == 0 {
+		if newRev := newRevision(mod.Generation, sdMap); !reflect.DeepEqual(newRev, revision) {
+			if status.Revision, err = mrh.saveRevision(ctx, mod, newRev); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		// keep the revision in the current status of mod until the new one is written there
+		if err = mrh.pruneRevisions(ctx, mod, prevRevision, status.Revision); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return &status, requeueAfter, nil
+}
+
+// unavailableFor returns 0 if the config in spec was loaded on the node for at least minReady, the remaining time
+// if it was loaded more recently, and -1 if it was not loaded yet.
+func unavailableFor(spec *kmmv1beta1.NodeModuleSpec, status *kmmv1beta1.NodeModuleStatus, minReady time.Duration) time.Duration {
+	if status == nil || !reflect.DeepEqual(spec.Config, status.Config) {
+		return -1
+	}
+
+	progressing := nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionProgressing)
+	if progressing == nil {
+		return 0
+	}
+
+	if progressing.Status == metav1.ConditionTrue {
+		return -1
+	}
+
+	if d := time.Until(progressing.LastTransitionTime.Add(minReady)); d > 0 {
+		return d
+	}
+
+	return 0
+}
+
 func (mrh *moduleReconcilerHelper) handleMIC(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error {
 
 	var (
@@ -379,6 +620,20 @@ func (mrh *moduleReconcilerHelper) handleMIC(ctx context.Context, mod *kmmv1beta
 		images = append(images, mis)
 	}
 
+	// keep the images of the revision to roll back to until it is superseded
+	revision, err := mrh.getRevision(ctx, mod)
+	if err != nil {
+		errs = append(errs, err)
+	} else if revision != nil {
+		for _, cfg := range revision.Configs {
+			images = append(images, kmmv1beta1.ModuleImageSpec{
+				Image:         cfg.ContainerImage,
+				KernelVersion: cfg.KernelVersion,
+				DirName:       cfg.Modprobe.DirName,
+			})
+		}
+	}
+
 	if err := mrh.micAPI.CreateOrPatch(ctx, mod.Name, mod.Namespace, images, mod.Spec.ImageRepoSecret,
 		mod.Spec.ModuleLoader.Container.ImagePullPolicy, true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.Tolerations, mod); err != nil {
 		errs = append(errs, fmt.Errorf("failed to apply %s/%s MIC: %v", mod.Namespace, mod.Name, err))
@@ -403,38 +658,199 @@ func (mrh *moduleReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *
 		return nil
 	}
 
-	moduleConfig := kmmv1beta1.ModuleConfig{
-		KernelVersion:         mld.KernelVersion,
-		ContainerImage:        mld.ContainerImage,
-		ImagePullPolicy:       mld.ImagePullPolicy,
-		InTreeModulesToRemove: mld.InTreeModulesToRemove,
-		Modprobe:              mld.Modprobe,
+	moduleConfig := moduleConfigFromMLD(mld)
+
+	opRes, err := mrh.setModuleConfigInNMC(ctx, mld, &moduleConfig, node)
+	if err != nil {
+		return fmt.Errorf("failed to enable module %s/%s in NMC %s: %v", mld.Namespace, mld.Name, node.Name, err)
 	}
+	logger.Info("Enable module in NMC", "name", mld.Name, "namespace", mld.Namespace, "node", node.Name, "result", opRes)
+	return nil
+}
 
-	if tls := mld.RegistryTLS; tls != nil {
-		moduleConfig.InsecurePull = tls.Insecure || tls.InsecureSkipTLSVerify
+// rollbackModuleOnNode sets config, the module config of a previous revision, in the NMC of node.
+// Unlike enableModuleOnNode, it does not wait for the image of config to exist, since it was already loaded on nodes.
+func (mrh *moduleReconcilerHelper) rollbackModuleOnNode(
+	ctx context.Context,
+	mld *api.ModuleLoaderData,
+	config *kmmv1beta1.ModuleConfig,
+	node *v1.Node) error {
+
+	opRes, err := mrh.setModuleConfigInNMC(ctx, mld, config, node)
+	if err != nil {
+		return fmt.Errorf("failed to roll back module %s/%s in NMC %s: %v", mld.Namespace, mld.Name, node.Name, err)
 	}
 
+	log.FromContext(ctx).Info("Rolled back module in NMC", "name", mld.Name, "namespace", mld.Namespace, "node", node.Name, "result", opRes)
+	return nil
+}
+
+// setModuleConfigInNMC sets config in the NMC of node.
+// If other modules of the NMC use the same kernel module, the NMC is neither created nor patched if the conflict policy
+// rejects the config.
+func (mrh *moduleReconcilerHelper) setModuleConfigInNMC(
+	ctx context.Context,
+	mld *api.ModuleLoaderData,
+	config *kmmv1beta1.ModuleConfig,
+	node *v1.Node) (controllerutil.OperationResult, error) {
+
 	nmcObj := &kmmv1beta1.NodeModulesConfig{
 		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
 	}
 
-	opRes, err := controllerutil.CreateOrPatch(ctx, mrh.client, nmcObj, func() error {
-		if err := mrh.nmcHelper.SetModuleConfig(nmcObj, mld, &moduleConfig); err != nil {
+	if err := mrh.client.Get(ctx, types.NamespacedName{Name: node.Name}, nmcObj); err != nil && !apierrors.IsNotFound(err) {
+		return controllerutil.OperationResultNone, fmt.Errorf("failed to get NMC %s: %v", node.Name, err)
+	}
+
+	// check for conflicts on a copy first, so that a rejected config does not create an empty NMC
+	conflictErr, err := mrh.setModuleConfig(ctx, nmcObj.DeepCopy(), mld, config)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	if conflictErr != nil {
+		message := conflictErr.Error()
+		if conflictErr.Rejected {
+			message = fmt.Sprintf("Module %s/%s was not configured: %s", mld.Namespace, mld.Name, message)
+		}
+
+		log.FromContext(ctx).Info(utils.WarnString(message))
+
+		if conflictErr.Rejected {
+			return controllerutil.OperationResultNone, nil
+		}
+	}
+
+	return controllerutil.CreateOrPatch(ctx, mrh.client, nmcObj, func() error {
+		// the NMC may have changed since the conflicts were checked
+		ce, err := mrh.setModuleConfig(ctx, nmcObj, mld, config)
+		if err != nil {
 			return err
 		}
 
+		if ce != nil && ce.Rejected {
+			return ce
+		}
+
 		meta.SetLabel(nmcObj, nmc.ModuleConfiguredLabel(mld.Namespace, mld.Name), "")
 		meta.SetLabel(nmcObj, nmc.ModuleInUseLabel(mld.Namespace, mld.Name), "")
 
 		return controllerutil.SetOwnerReference(node, nmcObj, mrh.scheme)
 	})
+}
+
+// setModuleConfig sets moduleConfig in the spec of nmcObj and returns the conflicts found, if any.
+// With the FirstWins conflict policy, the conflicting modules whose Module was created after the Module of mld are
+// removed from nmcObj, so that the oldest Module keeps the node even if a newer one was configured first.
+func (mrh *moduleReconcilerHelper) setModuleConfig(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	mld *api.ModuleLoaderData,
+	moduleConfig *kmmv1beta1.ModuleConfig) (*nmc.ConflictError, error) {
 
+	var conflictErr *nmc.ConflictError
+
+	err := mrh.nmcHelper.SetModuleConfig(nmcObj, mld, moduleConfig)
+	if err == nil {
+		return nil, nil
+	}
+
+	if !errors.As(err, &conflictErr) {
+		return nil, err
+	}
+
+	if !conflictErr.Rejected || mrh.conflictPolicy != config.ConflictPolicyFirstWins {
+		return conflictErr, nil
+	}
+
+	newer, err := mrh.newerConflicts(ctx, mld, conflictErr.Conflicts)
 	if err != nil {
-		return fmt.Errorf("failed to enable module %s/%s in NMC %s: %v", mld.Namespace, mld.Name, node.Name, err)
+		return nil, err
 	}
-	logger.Info("Enable module in NMC", "name", mld.Name, "namespace", mld.Namespace, "node", node.Name, "result", opRes)
-	return nil
+
+	if len(newer) == 0 {
+		return conflictErr, nil
+	}
+
+	for _, c := range newer {
+		if err = mrh.nmcHelper.RemoveModuleConfig(nmcObj, c.Namespace, c.Name); err != nil {
+			return nil, fmt.Errorf("failed to remove module %s/%s from NMC %s: %v", c.Namespace, c.Name, nmcObj.Name, err)
+		}
+
+		meta.RemoveLabel(nmcObj, nmc.ModuleConfiguredLabel(c.Namespace, c.Name))
+
+		log.FromContext(ctx).Info(
+			utils.WarnString("Removing a newer conflicting module from NMC"),
+			"nmc", nmcObj.Name,
+			"namespace", c.Namespace,
+			"name", c.Name,
+		)
+	}
+
+	err = mrh.nmcHelper.SetModuleConfig(nmcObj, mld, moduleConfig)
+	if err == nil {
+		return nil, nil
+	}
+
+	if !errors.As(err, &conflictErr) {
+		return nil, err
+	}
+
+	return conflictErr, nil
+}
+
+// newerConflicts returns the conflicts whose Module was created after the Module of mld, or no longer exists.
+// Modules created at the same time are ordered by namespace and name.
+func (mrh *moduleReconcilerHelper) newerConflicts(ctx context.Context, mld *api.ModuleLoaderData, conflicts []nmc.Conflict) ([]nmc.Conflict, error) {
+	if mld.Owner == nil {
+		return nil, nil
+	}
+
+	created := mld.Owner.GetCreationTimestamp()
+	newer := make([]nmc.Conflict, 0, len(conflicts))
+
+	for _, c := range conflicts {
+		other := kmmv1beta1.Module{}
+
+		if err := mrh.client.Get(ctx, types.NamespacedName{Namespace: c.Namespace, Name: c.Name}, &other); err != nil {
+			if apierrors.IsNotFound(err) {
+				newer = append(newer, c)
+				continue
+			}
+
+			return nil, fmt.Errorf("could not get Module %s/%s: %v", c.Namespace, c.Name, err)
+		}
+
+		otherCreated := other.GetCreationTimestamp()
+
+		switch {
+		case created.Before(&otherCreated):
+			newer = append(newer, c)
+		case otherCreated.Equal(&created):
+			if (types.NamespacedName{Namespace: mld.Namespace, Name: mld.Name}).String() < (types.NamespacedName{Namespace: c.Namespace, Name: c.Name}).String() {
+				newer = append(newer, c)
+			}
+		}
+	}
+
+	return newer, nil
+}
+
+func moduleConfigFromMLD(mld *api.ModuleLoaderData) kmmv1beta1.ModuleConfig {
+	moduleConfig := kmmv1beta1.ModuleConfig{
+		KernelVersion:          mld.KernelVersion,
+		ContainerImage:         mld.ContainerImage,
+		ImagePullPolicy:        mld.ImagePullPolicy,
+		InTreeModulesToRemove:  mld.InTreeModulesToRemove,
+		BlacklistInTreeModules: mld.BlacklistInTreeModules,
+		Modprobe:               mld.Modprobe,
+	}
+
+	if tls := mld.RegistryTLS; tls != nil {
+		moduleConfig.InsecurePull = tls.Insecure || tls.InsecureSkipTLSVerify
+	}
+
+	return moduleConfig
 }
 
 func (mrh *moduleReconcilerHelper) disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error {
@@ -465,19 +881,38 @@ func (mrh *moduleReconcilerHelper) removeModuleFromNMC(ctx context.Context, nmcO
 	return nil
 }
 
-func (mrh *moduleReconcilerHelper) updateModuleStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error {
+func (mrh *moduleReconcilerHelper) updateModuleStatus(
+	ctx context.Context,
+	mod *kmmv1beta1.Module,
+	targetedNodes []v1.Node,
+	upgradeStatus *kmmv1beta1.UpgradeStatus) error {
+
 	unmodifiedMod := mod.DeepCopy()
 
+	mod.Status.Upgrade = upgradeStatus
+
 	var errs []error
 
 	if err := mrh.updateModuleLoaderStatus(ctx, mod, targetedNodes); err != nil {
 		errs = append(errs, fmt.Errorf("failed to update module loader status for module %s/%s: %v", mod.Namespace, mod.Name, err))
 	}
 
+	if err := mrh.updateKernelsStatus(ctx, mod, targetedNodes); err != nil {
+		errs = append(errs, fmt.Errorf("failed to update kernels status for module %s/%s: %v", mod.Namespace, mod.Name, err))
+	}
+
 	if err := mrh.updateImageRebuildTriggerGenerationStatus(ctx, mod); err != nil {
 		errs = append(errs, fmt.Errorf("failed to update ImageRebuildTriggerGeneration status for module %s/%s: %v", mod.Namespace, mod.Name, err))
 	}
 
+	if err := mrh.updateConditions(ctx, mod, upgradeStatus); err != nil {
+		errs = append(errs, fmt.Errorf("failed to update conditions for module %s/%s: %v", mod.Namespace, mod.Name, err))
+	}
+
+	if err := mrh.updateConflictCondition(ctx, mod, targetedNodes); err != nil {
+		errs = append(errs, fmt.Errorf("failed to update the %s condition for module %s/%s: %v", kmmv1beta1.ModuleConditionConflict, mod.Namespace, mod.Name, err))
+	}
+
 	if err := mrh.client.Status().Patch(ctx, mod, client.MergeFrom(unmodifiedMod)); err != nil {
 		errs = append(errs, fmt.Errorf("failed to patch module status for module %s/%s: %v", mod.Namespace, mod.Name, err))
 	}
@@ -493,7 +928,7 @@ func (mrh *moduleReconcilerHelper) updateModuleLoaderStatus(ctx context.Context,
 		return fmt.Errorf("failed to get configured NMCs for module %s/%s: %v", mod.Namespace, mod.Name, err)
 	}
 
-	numAvailable := 0
+	numTainted := 0
 	for _, nmc := range nmcs {
 		modSpec, _ := mrh.nmcHelper.GetModuleSpecEntry(&nmc, mod.Namespace, mod.Name)
 		if modSpec == nil {
@@ -504,14 +939,116 @@ func (mrh *moduleReconcilerHelper) updateModuleLoaderStatus(ctx context.Context,
 			continue
 		}
 		modStatus := mrh.nmcHelper.GetModuleStatusEntry(&nmc, mod.Namespace, mod.Name)
-		if modStatus != nil && reflect.DeepEqual(modSpec.Config, modStatus.Config) {
-			numAvailable += 1
+		if modStatus != nil && modStatus.Taint != nil && len(modStatus.Taint.Module) > 0 {
+			numTainted += 1
 		}
 	}
 
 	mod.Status.ModuleLoader.NodesMatchingSelectorNumber = int32(len(targetedNodes))
 	mod.Status.ModuleLoader.DesiredNumber = int32(len(nmcs))
-	mod.Status.ModuleLoader.AvailableNumber = int32(numAvailable)
+	mod.Status.ModuleLoader.TaintedNumber = int32(numTainted)
+
+	return nil
+}
+
+// updateKernelsStatus sets the status of mod for each kernel version that targetedNodes run: the module image, its
+// state in the MIC, and on how many nodes the kernel module is loaded with the latest config, or failed.
+// The latest config of a node includes the ModuleNodeOverrides that apply to it, and may not be in its NMC yet if
+// the upgrade strategy holds it back.
+// It also sets the AvailableNumber of the module loader to the number of nodes that run the latest config, for all
+// kernels.
+func (mrh *moduleReconcilerHelper) updateKernelsStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error {
+	micObj, err := mrh.micAPI.Get(ctx, mod.Name, mod.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get MIC %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	nmcs, err := mrh.getNMCsForModule(ctx, mod)
+	if err != nil {
+		return fmt.Errorf("failed to get configured NMCs for module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	overrides, err := mrh.getNodeOverrides(ctx, mod)
+	if err != nil {
+		return err
+	}
+
+	nmcByName := make(map[string]*kmmv1beta1.NodeModulesConfig, len(nmcs))
+	for i := range nmcs {
+		nmcByName[nmcs[i].Name] = &nmcs[i]
+	}
+
+	var (
+		kernels = make(map[string]*kmmv1beta1.KernelStatus)
+		mlds    = make(map[string]*api.ModuleLoaderData)
+	)
+
+	for _, node := range targetedNodes {
+		kernelVersion := strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+")
+
+		ks := kernels[kernelVersion]
+		if ks == nil {
+			ks = &kmmv1beta1.KernelStatus{KernelVersion: kernelVersion}
+
+			mld, err := mrh.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
+			if err != nil && !errors.Is(err, module.ErrNoMatchingKernelMapping) {
+				return fmt.Errorf("failed to get the module loader data for kernel %s: %v", kernelVersion, err)
+			}
+
+			if mld != nil {
+				ks.Image = mld.ContainerImage
+
+				if micObj != nil {
+					ks.ImageState = mrh.micAPI.GetImageState(micObj, mld.ContainerImage)
+				}
+			}
+
+			kernels[kernelVersion] = ks
+			mlds[kernelVersion] = mld
+		}
+
+		ks.NodesNumber++
+
+		nmcObj := nmcByName[node.Name]
+		if nmcObj == nil {
+			continue
+		}
+
+		modStatus := mrh.nmcHelper.GetModuleStatusEntry(nmcObj, mod.Namespace, mod.Name)
+
+		if moduleFailedOnNode(modStatus) {
+			ks.FailedNumber++
+		}
+
+		if modStatus == nil || mlds[kernelVersion] == nil {
+			continue
+		}
+
+		// applyNodeOverrides replaces the modprobe settings of the copy without changing those of the kernel's mld
+		nodeMLD := *mlds[kernelVersion]
+		if _, err = applyNodeOverrides(&nodeMLD, &node, overrides); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(moduleConfigFromMLD(&nodeMLD), modStatus.Config) {
+			ks.LoadedNumber++
+		}
+	}
+
+	mod.Status.Kernels = make([]kmmv1beta1.KernelStatus, 0, len(kernels))
+
+	var numAvailable int32
+
+	for _, ks := range kernels {
+		mod.Status.Kernels = append(mod.Status.Kernels, *ks)
+		numAvailable += ks.LoadedNumber
+	}
+
+	mod.Status.ModuleLoader.AvailableNumber = numAvailable
+
+	sort.Slice(mod.Status.Kernels, func(i, j int) bool {
+		return mod.Status.Kernels[i].KernelVersion < mod.Status.Kernels[j].KernelVersion
+	})
 
 	return nil
 }
@@ -532,6 +1069,74 @@ func (mrh *moduleReconcilerHelper) updateImageRebuildTriggerGenerationStatus(ctx
 	return nil
 }
 
+// updateConflictCondition sets the Conflict condition of mod to True if other Modules use the same kernel module on
+// some of targetedNodes, and records an event on mod when the condition changes.
+func (mrh *moduleReconcilerHelper) updateConflictCondition(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error {
+	mods := kmmv1beta1.ModuleList{}
+
+	if err := mrh.client.List(ctx, &mods); err != nil {
+		return fmt.Errorf("could not list Modules: %v", err)
+	}
+
+	conflicts := make([]string, 0)
+
+	for _, other := range mods.Items {
+		if other.Namespace == mod.Namespace && other.Name == mod.Name {
+			continue
+		}
+
+		km := module.ConflictingKernelModule(mod, &other)
+		if km == "" {
+			continue
+		}
+
+		for _, n := range targetedNodes {
+			selected, err := utils.IsObjectSelectedByLabels(n.GetLabels(), other.Spec.Selector)
+			if err != nil {
+				return fmt.Errorf("could not determine if node %s is selected by Module %s/%s: %v", n.Name, other.Namespace, other.Name, err)
+			}
+
+			if selected {
+				conflicts = append(conflicts, fmt.Sprintf("%s by Module %s/%s", km, other.Namespace, other.Name))
+				break
+			}
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               kmmv1beta1.ModuleConditionConflict,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoConflict",
+		ObservedGeneration: mod.Generation,
+	}
+
+	if len(conflicts) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ConflictingModules"
+		condition.Message = "kernel modules also used on targeted nodes: " + strings.Join(conflicts, ", ")
+
+		if mrh.conflictPolicy == config.ConflictPolicyReject {
+			condition.Reason = "ConflictRejected"
+			condition.Message += "; only the Module configured first on each node is loaded there"
+		}
+	}
+
+	previous := apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionConflict)
+
+	// only record events when the condition changes, not on every reconciliation
+	switch {
+	case condition.Status == metav1.ConditionTrue &&
+		(previous == nil || previous.Status != condition.Status || previous.Reason != condition.Reason || previous.Message != condition.Message):
+		mrh.recorder.Event(mod, v1.EventTypeWarning, "ModuleConflict", condition.Message)
+	case condition.Status == metav1.ConditionFalse && previous != nil && previous.Status == metav1.ConditionTrue:
+		mrh.recorder.Event(mod, v1.EventTypeNormal, "ModuleConflictResolved", "no other Module uses the same kernel modules on targeted nodes")
+	}
+
+	apimeta.SetStatusCondition(&mod.Status.Conditions, condition)
+
+	return nil
+}
+
 type namespaceLabeler interface {
 	setLabel(ctx context.Context, name string) error
 	tryRemovingLabel(ctx context.Context, name, moduleName string) error
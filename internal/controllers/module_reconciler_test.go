@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/kubernetes-sigs/kernel-module-management/internal/mic"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/node"
@@ -11,6 +13,7 @@ import (
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/meta"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/module"
@@ -19,12 +22,15 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -98,6 +104,7 @@ var _ = Describe("ModuleReconciler_Reconcile", func() {
 		shouldBeOnNode             bool
 		disableEnableError         bool
 		moduleUpdateStatusErr      bool
+		applyUpgradeStrategyError  bool
 		setLabelError              bool
 	}
 
@@ -136,9 +143,15 @@ var _ = Describe("ModuleReconciler_Reconcile", func() {
 		mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil)
 		if c.prepareSchedulingError {
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nil, []error{returnedError})
+			mockReconHelper.EXPECT().applyUpgradeStrategy(ctx, mod, nil)
 			goto moduleStatusUpdateFunction
 		}
 		mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, []error{})
+		if c.applyUpgradeStrategyError {
+			mockReconHelper.EXPECT().applyUpgradeStrategy(ctx, mod, nmcMLDConfigs).Return(nil, time.Duration(0), returnedError)
+			goto executeTestFunction
+		}
+		mockReconHelper.EXPECT().applyUpgradeStrategy(ctx, mod, nmcMLDConfigs)
 		if c.disableEnableError {
 			if c.shouldBeOnNode {
 				mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(returnedError)
@@ -155,9 +168,9 @@ var _ = Describe("ModuleReconciler_Reconcile", func() {
 
 	moduleStatusUpdateFunction:
 		if c.moduleUpdateStatusErr {
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(returnedError)
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, nil).Return(returnedError)
 		} else {
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(nil)
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, nil).Return(nil)
 		}
 
 	executeTestFunction:
@@ -171,6 +184,7 @@ var _ = Describe("ModuleReconciler_Reconcile", func() {
 		Entry("handleMIC failed", errorFlowTestCase{handleMICError: true}),
 		Entry("getNMCsByModuleMap failed", errorFlowTestCase{getNMCsMapError: true}),
 		Entry("prepareSchedulingData failed", errorFlowTestCase{prepareSchedulingError: true}),
+		Entry("applyUpgradeStrategy failed", errorFlowTestCase{applyUpgradeStrategyError: true}),
 		Entry("enableModuleOnNode failed", errorFlowTestCase{shouldBeOnNode: true, disableEnableError: true}),
 		Entry("disableModuleOnNode failed", errorFlowTestCase{disableEnableError: true}),
 		Entry("updateModuleStatus failed", errorFlowTestCase{moduleUpdateStatusErr: true}),
@@ -186,8 +200,9 @@ var _ = Describe("ModuleReconciler_Reconcile", func() {
 			mockReconHelper.EXPECT().handleMIC(ctx, mod, targetedNodes).Return(nil),
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().applyUpgradeStrategy(ctx, mod, nmcMLDConfigs),
 			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(nil),
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(nil),
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, nil).Return(nil),
 		)
 
 		res, err := mr.Reconcile(ctx, mod)
@@ -205,8 +220,30 @@ var _ = Describe("ModuleReconciler_Reconcile", func() {
 			mockReconHelper.EXPECT().handleMIC(ctx, mod, targetedNodes).Return(nil),
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().applyUpgradeStrategy(ctx, mod, nmcMLDConfigs),
 			mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(nil),
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(nil),
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, nil).Return(nil),
+		)
+
+		res, err := mr.Reconcile(ctx, mod)
+
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Good flow, should roll back the module config on node", func() {
+		cfg := kmmv1beta1.ModuleConfig{ContainerImage: "previous-image"}
+		nmcMLDConfigs := map[string]schedulingData{nodeName: {action: actionRollback, mld: &mld, node: &node, config: &cfg}}
+		gomock.InOrder(
+			mockNamespaceHelper.EXPECT().setLabel(ctx, mod.Namespace),
+			mockReconHelper.EXPECT().setFinalizerAndStatus(ctx, mod).Return(nil),
+			mn.EXPECT().GetSchedulableNodesBySelector(ctx, mod.Spec.Selector, module.InternalTolerations).Return(targetedNodes, nil),
+			mockReconHelper.EXPECT().handleMIC(ctx, mod, targetedNodes).Return(nil),
+			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
+			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().applyUpgradeStrategy(ctx, mod, nmcMLDConfigs),
+			mockReconHelper.EXPECT().rollbackModuleOnNode(ctx, &mld, &cfg, &node).Return(nil),
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, nil).Return(nil),
 		)
 
 		res, err := mr.Reconcile(ctx, mod)
@@ -244,7 +281,7 @@ var _ = Describe("setFinalizerAndStatus", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		statusWriter = client.NewMockStatusWriter(ctrl)
-		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, scheme)
+		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, nil, nil, scheme, "")
 		mod = kmmv1beta1.Module{}
 		expectedMod = mod.DeepCopy()
 	})
@@ -307,7 +344,7 @@ var _ = Describe("finalizeModule", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		helper = nmc.NewMockHelper(ctrl)
-		mrh = newModuleReconcilerHelper(clnt, nil, nil, helper, scheme)
+		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, helper, nil, scheme, "")
 		mod = &kmmv1beta1.Module{
 			ObjectMeta: metav1.ObjectMeta{Name: moduleName, Namespace: moduleNamespace},
 		}
@@ -433,7 +470,7 @@ var _ = Describe("handleMIC", func() {
 		mockKernelMapper = module.NewMockKernelMapper(ctrl)
 		mockMICAPI = mic.NewMockMIC(ctrl)
 		helper = nmc.NewMockHelper(ctrl)
-		mrh = newModuleReconcilerHelper(clnt, mockKernelMapper, mockMICAPI, helper, scheme)
+		mrh = newModuleReconcilerHelper(clnt, mockKernelMapper, mockMICAPI, nil, helper, nil, scheme, "")
 		mod = &kmmv1beta1.Module{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      moduleName,
@@ -508,6 +545,77 @@ var _ = Describe("handleMIC", func() {
 		err := mrh.handleMIC(ctx, mod, targetedNodes)
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("should keep the images of the revision to roll back to", func() {
+		mod.Status.Upgrade = &kmmv1beta1.UpgradeStatus{Revision: "revision"}
+
+		img := "example.registry.com/org/image:new"
+		oldImg := "example.registry.com/org/image:old"
+		mld := &api.ModuleLoaderData{ContainerImage: img, KernelVersion: "some version"}
+		revision := kmmv1beta1.ModuleRevision{
+			Generation: 1,
+			Configs: []kmmv1beta1.RevisionConfig{
+				{ModuleConfig: kmmv1beta1.ModuleConfig{ContainerImage: oldImg, KernelVersion: "some version"}},
+			},
+		}
+
+		mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, gomock.Any()).Return(mld, nil)
+		clnt.EXPECT().Get(ctx, types.NamespacedName{Namespace: moduleNamespace, Name: "revision"}, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, cr *appsv1.ControllerRevision, _ ...ctrlclient.GetOption) error {
+				data, err := json.Marshal(revision)
+				cr.Data.Raw = data
+				return err
+			},
+		)
+		mockMICAPI.EXPECT().CreateOrPatch(
+			ctx,
+			mod.Name,
+			mod.Namespace,
+			[]kmmv1beta1.ModuleImageSpec{
+				{Image: img, KernelVersion: "some version"},
+				{Image: oldImg, KernelVersion: "some version"},
+			},
+			mod.Spec.ImageRepoSecret,
+			v1.PullPolicy(""),
+			true,
+			mod.Spec.ImageRebuildTriggerGeneration,
+			mod.Spec.Tolerations,
+			mod,
+		)
+
+		Expect(
+			mrh.handleMIC(ctx, mod, targetedNodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should not keep images if the revision was deleted", func() {
+		mod.Status.Upgrade = &kmmv1beta1.UpgradeStatus{Revision: "revision"}
+
+		mld := &api.ModuleLoaderData{ContainerImage: "example.registry.com/org/image:new", KernelVersion: "some version"}
+
+		mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, gomock.Any()).Return(mld, nil)
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "revision"))
+		mockMICAPI.EXPECT().CreateOrPatch(
+			ctx,
+			mod.Name,
+			mod.Namespace,
+			[]kmmv1beta1.ModuleImageSpec{{Image: mld.ContainerImage, KernelVersion: "some version"}},
+			mod.Spec.ImageRepoSecret,
+			v1.PullPolicy(""),
+			true,
+			mod.Spec.ImageRebuildTriggerGeneration,
+			mod.Spec.Tolerations,
+			mod,
+		)
+
+		Expect(
+			mrh.handleMIC(ctx, mod, targetedNodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
 })
 
 var _ = Describe("getNMCsByModuleSet", func() {
@@ -520,7 +628,7 @@ var _ = Describe("getNMCsByModuleSet", func() {
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
-		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, scheme)
+		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, nil, nil, scheme, "")
 	})
 
 	ctx := context.Background()
@@ -583,7 +691,7 @@ var _ = Describe("prepareSchedulingData", func() {
 		clnt = client.NewMockClient(ctrl)
 		mockKernel = module.NewMockKernelMapper(ctrl)
 		mockHelper = nmc.NewMockHelper(ctrl)
-		mrh = newModuleReconcilerHelper(clnt, mockKernel, nil, mockHelper, scheme)
+		mrh = newModuleReconcilerHelper(clnt, mockKernel, nil, nil, mockHelper, nil, scheme, "")
 		node = v1.Node{
 			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
 			Status: v1.NodeStatus{
@@ -591,6 +699,7 @@ var _ = Describe("prepareSchedulingData", func() {
 			},
 		}
 		targetedNodes = []v1.Node{node}
+		clnt.EXPECT().List(context.Background(), &kmmv1beta1.ModuleNodeOverrideList{}, gomock.Any())
 	})
 
 	ctx := context.Background()
@@ -731,6 +840,189 @@ var _ = Describe("prepareSchedulingData", func() {
 	})
 })
 
+var _ = Describe("applyUpgradeStrategy", func() {
+	const (
+		modName      = "modName"
+		modNamespace = "modNamespace"
+		oldImage     = "old-image"
+		newImage     = "new-image"
+	)
+
+	var (
+		ctx  context.Context
+		clnt *client.MockClient
+		mod  *kmmv1beta1.Module
+		mrh  moduleReconcilerHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		mod = &kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: modName, Namespace: modNamespace},
+			Spec: kmmv1beta1.ModuleSpec{
+				UpgradeStrategy: &kmmv1beta1.UpgradeStrategy{},
+			},
+		}
+		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, nmc.NewHelper(clnt, ""), nil, scheme, "")
+	})
+
+	// nmcWithImage returns the NMC of nodeName, with a module config using image in its spec and loadedImage in its
+	// status.
+	nmcWithImage := func(nodeName, image, loadedImage string, loadedAgo time.Duration) kmmv1beta1.NodeModulesConfig {
+		item := kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace}
+
+		return kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{ModuleItem: item, Config: kmmv1beta1.ModuleConfig{ContainerImage: image}},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: item,
+						Config:     kmmv1beta1.ModuleConfig{ContainerImage: loadedImage},
+						Conditions: []kmmv1beta1.NodeModuleCondition{
+							{
+								Type:               kmmv1beta1.NodeModuleConditionProgressing,
+								Status:             metav1.ConditionFalse,
+								LastTransitionTime: metav1.NewTime(time.Now().Add(-loadedAgo)),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	addSchedulingData := func(nodeNames ...string) map[string]schedulingData {
+		sdMap := make(map[string]schedulingData)
+
+		for _, n := range nodeNames {
+			sdMap[n] = schedulingData{
+				action: actionAdd,
+				mld:    &api.ModuleLoaderData{Name: modName, Namespace: modNamespace, ContainerImage: newImage},
+				node:   &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: n}},
+			}
+		}
+
+		return sdMap
+	}
+
+	expectNMCs := func(nmcs ...kmmv1beta1.NodeModulesConfig) {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+				list.Items = nmcs
+				return nil
+			},
+		)
+	}
+
+	It("should do nothing if the Module has no upgrade strategy", func() {
+		mod.Spec.UpgradeStrategy = nil
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, requeueAfter, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(BeNil())
+		Expect(requeueAfter).To(BeZero())
+		Expect(sdMap).To(Equal(addSchedulingData("node1", "node2")))
+	})
+
+	It("should return an error if the NMCs cannot be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+
+		_, _, err := mrh.applyUpgradeStrategy(ctx, mod, addSchedulingData("node1"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should upgrade one node at a time by default and not hold back new nodes", func() {
+		expectNMCs(
+			nmcWithImage("node1", oldImage, oldImage, time.Hour),
+			nmcWithImage("node2", oldImage, oldImage, time.Hour),
+			nmcWithImage("node3", oldImage, oldImage, time.Hour),
+		)
+
+		sdMap := addSchedulingData("node1", "node2", "node3", "node4")
+
+		status, requeueAfter, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+		Expect(status).To(Equal(&kmmv1beta1.UpgradeStatus{UpdatedNumber: 2, PendingNumber: 2, UnavailableNumber: 1}))
+		Expect(sdMap["node1"].action).To(Equal(actionAdd))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+		Expect(sdMap["node3"]).To(Equal(schedulingData{}))
+		Expect(sdMap["node4"].action).To(Equal(actionAdd))
+	})
+
+	It("should not count nodes that booted another kernel against maxUnavailable", func() {
+		rebooted1 := nmcWithImage("node1", oldImage, oldImage, time.Hour)
+		rebooted1.Spec.Modules[0].Config.KernelVersion = "old-kernel"
+		rebooted2 := nmcWithImage("node2", oldImage, oldImage, time.Hour)
+		rebooted2.Spec.Modules[0].Config.KernelVersion = "old-kernel"
+
+		expectNMCs(
+			rebooted1,
+			rebooted2,
+			nmcWithImage("node3", oldImage, oldImage, time.Hour),
+			nmcWithImage("node4", oldImage, oldImage, time.Hour),
+		)
+
+		sdMap := addSchedulingData("node1", "node2", "node3", "node4")
+		sdMap["node1"].mld.KernelVersion = "new-kernel"
+		sdMap["node2"].mld.KernelVersion = "new-kernel"
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(&kmmv1beta1.UpgradeStatus{UpdatedNumber: 3, PendingNumber: 1, UnavailableNumber: 1}))
+		Expect(sdMap["node1"].action).To(Equal(actionAdd))
+		Expect(sdMap["node2"].action).To(Equal(actionAdd))
+		Expect(sdMap["node3"].action).To(Equal(actionAdd))
+		Expect(sdMap["node4"]).To(Equal(schedulingData{}))
+	})
+
+	It("should count nodes that are still loading the new config as unavailable", func() {
+		maxUnavailable := intstr.FromString("50%")
+		mod.Spec.UpgradeStrategy.MaxUnavailable = &maxUnavailable
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, oldImage, time.Hour),
+			nmcWithImage("node2", oldImage, oldImage, time.Hour),
+			nmcWithImage("node3", oldImage, oldImage, time.Hour),
+			nmcWithImage("node4", oldImage, oldImage, time.Hour),
+		)
+
+		sdMap := addSchedulingData("node1", "node2", "node3", "node4")
+
+		status, _, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(&kmmv1beta1.UpgradeStatus{UpdatedNumber: 2, PendingNumber: 2, UnavailableNumber: 2}))
+		Expect(sdMap["node2"].action).To(Equal(actionAdd))
+		Expect(sdMap["node3"]).To(Equal(schedulingData{}))
+		Expect(sdMap["node4"]).To(Equal(schedulingData{}))
+	})
+
+	It("should wait for minReadySeconds before upgrading the next node", func() {
+		mod.Spec.UpgradeStrategy.MinReadySeconds = 60
+
+		expectNMCs(
+			nmcWithImage("node1", newImage, newImage, 30*time.Second),
+			nmcWithImage("node2", oldImage, oldImage, time.Hour),
+		)
+
+		sdMap := addSchedulingData("node1", "node2")
+
+		status, requeueAfter, err := mrh.applyUpgradeStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeNumerically("~", 30*time.Second, time.Second))
+		Expect(status).To(Equal(&kmmv1beta1.UpgradeStatus{UpdatedNumber: 1, PendingNumber: 1, UnavailableNumber: 1}))
+		Expect(sdMap["node2"]).To(Equal(schedulingData{}))
+	})
+})
+
 var _ = Describe("enableModuleOnNode", func() {
 	const (
 		moduleNamespace = "moduleNamespace"
@@ -749,6 +1041,7 @@ var _ = Describe("enableModuleOnNode", func() {
 		node                 v1.Node
 		expectedModuleConfig *kmmv1beta1.ModuleConfig
 		kernelVersion        string
+		fakeRecorder         *record.FakeRecorder
 	)
 
 	BeforeEach(func() {
@@ -756,7 +1049,8 @@ var _ = Describe("enableModuleOnNode", func() {
 		clnt = client.NewMockClient(ctrl)
 		helper = nmc.NewMockHelper(ctrl)
 		mockMIC = mic.NewMockMIC(ctrl)
-		mrh = newModuleReconcilerHelper(clnt, nil, mockMIC, helper, scheme)
+		fakeRecorder = record.NewFakeRecorder(10)
+		mrh = newModuleReconcilerHelper(clnt, nil, mockMIC, nil, helper, fakeRecorder, scheme, "")
 		node = v1.Node{
 			ObjectMeta: metav1.ObjectMeta{Name: "nodeName"},
 		}
@@ -808,6 +1102,8 @@ var _ = Describe("enableModuleOnNode", func() {
 			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
 			helper.EXPECT().SetModuleConfig(nmc, mld, expectedModuleConfig).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			helper.EXPECT().SetModuleConfig(nmc, mld, expectedModuleConfig).Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
 		)
 
@@ -842,12 +1138,284 @@ var _ = Describe("enableModuleOnNode", func() {
 				},
 			),
 			helper.EXPECT().SetModuleConfig(nmcObj, mld, expectedModuleConfig).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, nmc *kmmv1beta1.NodeModulesConfig, _ ...ctrlclient.GetOption) error {
+					nmc.SetName(node.Name)
+					return nil
+				},
+			),
+			helper.EXPECT().SetModuleConfig(nmcObj, mld, expectedModuleConfig).Return(nil),
 			clnt.EXPECT().Patch(ctx, &nmcWithLabels, gomock.Any()).Return(nil),
 		)
 
 		err := mrh.enableModuleOnNode(ctx, mld, &node)
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("should set the config if another module uses the same kernel module", func() {
+		conflictErr := &nmc.ConflictError{
+			Node:      node.Name,
+			Conflicts: []nmc.Conflict{{Namespace: "other-ns", Name: "other-module", KernelModule: "kmod"}},
+		}
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, nmc *kmmv1beta1.NodeModulesConfig, _ ...ctrlclient.GetOption) error {
+					nmc.SetName(node.Name)
+					return nil
+				},
+			),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, nmc *kmmv1beta1.NodeModulesConfig, _ ...ctrlclient.GetOption) error {
+					nmc.SetName(node.Name)
+					return nil
+				},
+			),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+		)
+
+		Expect(
+			mrh.enableModuleOnNode(ctx, mld, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should not configure the module if the conflict policy rejects it", func() {
+		conflictErr := &nmc.ConflictError{
+			Node:      node.Name,
+			Conflicts: []nmc.Conflict{{Namespace: "other-ns", Name: "other-module", KernelModule: "kmod"}},
+			Rejected:  true,
+		}
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, nmc *kmmv1beta1.NodeModulesConfig, _ ...ctrlclient.GetOption) error {
+					nmc.SetName(node.Name)
+					return nil
+				},
+			),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+		)
+
+		Expect(
+			mrh.enableModuleOnNode(ctx, mld, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should not create an NMC if the conflict policy rejects the module", func() {
+		conflictErr := &nmc.ConflictError{
+			Node:      node.Name,
+			Conflicts: []nmc.Conflict{{Namespace: "other-ns", Name: "other-module", KernelModule: "kmod"}},
+			Rejected:  true,
+		}
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+		)
+
+		Expect(
+			mrh.enableModuleOnNode(ctx, mld, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should return an error if the NMC was rejected after the conflicts were checked", func() {
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(&nmc.ConflictError{Rejected: true}),
+		)
+
+		Expect(
+			mrh.enableModuleOnNode(ctx, mld, &node),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return other errors of SetModuleConfig", func() {
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(errors.New("random error")),
+		)
+
+		Expect(
+			mrh.enableModuleOnNode(ctx, mld, &node),
+		).To(
+			HaveOccurred(),
+		)
+
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	Context("with the FirstWins conflict policy", func() {
+		var (
+			conflictErr *nmc.ConflictError
+			created     metav1.Time
+		)
+
+		BeforeEach(func() {
+			mrh = newModuleReconcilerHelper(clnt, nil, mockMIC, nil, helper, fakeRecorder, scheme, config.ConflictPolicyFirstWins)
+			created = metav1.Now()
+			mld.Owner = &kmmv1beta1.Module{
+				ObjectMeta: metav1.ObjectMeta{Namespace: moduleNamespace, Name: moduleName, CreationTimestamp: created},
+			}
+			conflictErr = &nmc.ConflictError{
+				Node:      node.Name,
+				Conflicts: []nmc.Conflict{{Namespace: "other-ns", Name: "other-module", KernelModule: "kmod"}},
+				Rejected:  true,
+			}
+		})
+
+		getNMC := func(_ interface{}, _ interface{}, nmcObj *kmmv1beta1.NodeModulesConfig, _ ...ctrlclient.GetOption) error {
+			nmcObj.SetName(node.Name)
+			meta.SetLabel(nmcObj, nmc.ModuleConfiguredLabel("other-ns", "other-module"), "")
+			return nil
+		}
+
+		getOtherModule := func(otherCreated metav1.Time) func(_ interface{}, _ interface{}, m *kmmv1beta1.Module, _ ...ctrlclient.GetOption) error {
+			return func(_ interface{}, _ interface{}, m *kmmv1beta1.Module, _ ...ctrlclient.GetOption) error {
+				m.SetCreationTimestamp(otherCreated)
+				return nil
+			}
+		}
+
+		It("should replace a conflicting module that was created after this one", func() {
+			otherKey := types.NamespacedName{Namespace: "other-ns", Name: "other-module"}
+			newer := metav1.NewTime(created.Add(time.Hour))
+
+			gomock.InOrder(
+				mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+				mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+				clnt.EXPECT().Get(ctx, types.NamespacedName{Name: node.Name}, gomock.Any()).DoAndReturn(getNMC),
+				helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+				clnt.EXPECT().Get(ctx, otherKey, gomock.Any()).DoAndReturn(getOtherModule(newer)),
+				helper.EXPECT().RemoveModuleConfig(gomock.Any(), "other-ns", "other-module"),
+				helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig),
+				clnt.EXPECT().Get(ctx, types.NamespacedName{Name: node.Name}, gomock.Any()).DoAndReturn(getNMC),
+				helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+				clnt.EXPECT().Get(ctx, otherKey, gomock.Any()).DoAndReturn(getOtherModule(newer)),
+				helper.EXPECT().RemoveModuleConfig(gomock.Any(), "other-ns", "other-module"),
+				helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig),
+				clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ interface{}, nmcObj *kmmv1beta1.NodeModulesConfig, _ ctrlclient.Patch, _ ...ctrlclient.PatchOption) error {
+						Expect(nmcObj.GetLabels()).To(HaveKey(nmc.ModuleConfiguredLabel(moduleNamespace, moduleName)))
+						Expect(nmcObj.GetLabels()).NotTo(HaveKey(nmc.ModuleConfiguredLabel("other-ns", "other-module")))
+						return nil
+					},
+				),
+			)
+
+			Expect(
+				mrh.enableModuleOnNode(ctx, mld, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should not configure the module if the conflicting module was created before this one", func() {
+			gomock.InOrder(
+				mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+				mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+				clnt.EXPECT().Get(ctx, types.NamespacedName{Name: node.Name}, gomock.Any()).DoAndReturn(getNMC),
+				helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+				clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getOtherModule(metav1.NewTime(created.Add(-time.Hour)))),
+			)
+
+			Expect(
+				mrh.enableModuleOnNode(ctx, mld, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should return an error if the conflicting Module cannot be fetched", func() {
+			gomock.InOrder(
+				mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+				mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+				clnt.EXPECT().Get(ctx, types.NamespacedName{Name: node.Name}, gomock.Any()).DoAndReturn(getNMC),
+				helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(conflictErr),
+				clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error")),
+			)
+
+			Expect(
+				mrh.enableModuleOnNode(ctx, mld, &node),
+			).To(
+				HaveOccurred(),
+			)
+		})
+	})
+})
+
+var _ = Describe("rollbackModuleOnNode", func() {
+	It("should set the previous config in the NMC without checking the image", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		clnt := client.NewMockClient(ctrl)
+		helper := nmc.NewMockHelper(ctrl)
+		mrh := newModuleReconcilerHelper(clnt, nil, nil, nil, helper, nil, scheme, "")
+		ctx := context.Background()
+
+		node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "nodeName"}}
+		mld := &api.ModuleLoaderData{Name: "moduleName", Namespace: "moduleNamespace", ContainerImage: "new-image"}
+		cfg := &kmmv1beta1.ModuleConfig{ContainerImage: "previous-image"}
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, cfg).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, cfg).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		)
+
+		Expect(
+			mrh.rollbackModuleOnNode(ctx, mld, cfg, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the NMC cannot be patched", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		clnt := client.NewMockClient(ctrl)
+		helper := nmc.NewMockHelper(ctrl)
+		mrh := newModuleReconcilerHelper(clnt, nil, nil, nil, helper, nil, scheme, "")
+		ctx := context.Background()
+
+		node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "nodeName"}}
+		mld := &api.ModuleLoaderData{Name: "moduleName", Namespace: "moduleNamespace"}
+
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+
+		Expect(
+			mrh.rollbackModuleOnNode(ctx, mld, &kmmv1beta1.ModuleConfig{}, &node),
+		).To(
+			HaveOccurred(),
+		)
+	})
 })
 
 var _ = Describe("disableModuleOnNode", func() {
@@ -867,7 +1435,7 @@ var _ = Describe("disableModuleOnNode", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		helper = nmc.NewMockHelper(ctrl)
-		mrh = newModuleReconcilerHelper(clnt, nil, nil, helper, scheme)
+		mrh = newModuleReconcilerHelper(clnt, nil, nil, nil, helper, nil, scheme, "")
 		nodeName = "node name"
 		moduleName = "moduleName"
 		moduleNamespace = "moduleNamespace"
@@ -1025,15 +1593,12 @@ var _ = Describe("updateModuleLoaderStatus", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(mod.Status.ModuleLoader.NodesMatchingSelectorNumber).To(Equal(int32(2)))
 		Expect(mod.Status.ModuleLoader.DesiredNumber).To(Equal(int32(1)))
-		Expect(mod.Status.ModuleLoader.AvailableNumber).To(Equal(int32(0)))
 	})
 
 	DescribeTable("module present in spec", func(numTargetedNodes int,
-		modulePresentInStatus,
-		configsEqual bool,
+		modulePresentInStatus bool,
 		expectedNodesMatchingSelectorNumber,
-		expectedDesiredNumber,
-		expectedAvailableNumber int) {
+		expectedDesiredNumber int) {
 
 		targetedNodes := []v1.Node{}
 		for i := 0; i < numTargetedNodes; i++ {
@@ -1043,16 +1608,10 @@ var _ = Describe("updateModuleLoaderStatus", func() {
 			ObjectMeta: metav1.ObjectMeta{Name: "nmc1"},
 		}
 		moduleConfig1 := kmmv1beta1.ModuleConfig{ContainerImage: "some image1"}
-		moduleConfig2 := kmmv1beta1.ModuleConfig{ContainerImage: "some image2"}
 		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{
 			Config: moduleConfig1,
 		}
-		nmcModuleStatus := kmmv1beta1.NodeModuleStatus{}
-		if configsEqual {
-			nmcModuleStatus.Config = moduleConfig1
-		} else {
-			nmcModuleStatus.Config = moduleConfig2
-		}
+		nmcModuleStatus := kmmv1beta1.NodeModuleStatus{Config: moduleConfig1}
 		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
 			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
 				list.Items = []kmmv1beta1.NodeModulesConfig{nmc1}
@@ -1070,11 +1629,9 @@ var _ = Describe("updateModuleLoaderStatus", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(mod.Status.ModuleLoader.NodesMatchingSelectorNumber).To(Equal(int32(expectedNodesMatchingSelectorNumber)))
 		Expect(mod.Status.ModuleLoader.DesiredNumber).To(Equal(int32(expectedDesiredNumber)))
-		Expect(mod.Status.ModuleLoader.AvailableNumber).To(Equal(int32(expectedAvailableNumber)))
 	},
-		Entry("2 targeted nodes, module not in status", 2, false, false, 2, 1, 0),
-		Entry("3 targeted nodes, module in status, configs not equal", 2, true, false, 2, 1, 0),
-		Entry("3 targeted nodes, module in status, configs equal", 2, true, true, 2, 1, 1),
+		Entry("2 targeted nodes, module not in status", 2, false, 2, 1),
+		Entry("3 targeted nodes, module in status", 3, true, 3, 1),
 	)
 
 	It("multiple module in spec and status", func() {
@@ -1103,6 +1660,174 @@ var _ = Describe("updateModuleLoaderStatus", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(mod.Status.ModuleLoader.NodesMatchingSelectorNumber).To(Equal(int32(2)))
 		Expect(mod.Status.ModuleLoader.DesiredNumber).To(Equal(int32(1)))
+	})
+
+	It("should count the nodes on which the module tainted the kernel", func() {
+		moduleConfig := kmmv1beta1.ModuleConfig{ContainerImage: "some image1"}
+		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{Config: moduleConfig}
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "nmc1"}}
+		nmc2 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "nmc2"}}
+		nmc3 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "nmc3"}}
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+				list.Items = []kmmv1beta1.NodeModulesConfig{nmc1, nmc2, nmc3}
+				return nil
+			},
+		)
+		helper.EXPECT().GetModuleSpecEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0).Times(3)
+		gomock.InOrder(
+			helper.EXPECT().GetModuleStatusEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{
+				Config: moduleConfig,
+				Taint:  &kmmv1beta1.KernelTaint{Kernel: []kmmv1beta1.TaintFlag{"OutOfTreeModule"}, Module: []kmmv1beta1.TaintFlag{"OutOfTreeModule"}},
+			}),
+			helper.EXPECT().GetModuleStatusEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{
+				Config: moduleConfig,
+				Taint:  &kmmv1beta1.KernelTaint{Kernel: []kmmv1beta1.TaintFlag{"Warning"}},
+			}),
+			helper.EXPECT().GetModuleStatusEntry(gomock.Any(), mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{
+				Config: moduleConfig,
+			}),
+		)
+
+		err := mrh.updateModuleLoaderStatus(ctx, &mod, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mod.Status.ModuleLoader.TaintedNumber).To(Equal(int32(1)))
+	})
+})
+
+var _ = Describe("updateKernelsStatus", func() {
+	var (
+		ctx        context.Context
+		clnt       *client.MockClient
+		helper     *nmc.MockHelper
+		mockKernel *module.MockKernelMapper
+		mockMicAPI *mic.MockMIC
+		mod        kmmv1beta1.Module
+		mrh        *moduleReconcilerHelper
+		nodes      []v1.Node
+	)
+
+	newNode := func(name, kernelVersion string) v1.Node {
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{KernelVersion: kernelVersion},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		helper = nmc.NewMockHelper(ctrl)
+		mockKernel = module.NewMockKernelMapper(ctrl)
+		mockMicAPI = mic.NewMockMIC(ctrl)
+		mod = kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: "modName", Namespace: "modNamespace"},
+		}
+		mrh = &moduleReconcilerHelper{client: clnt, nmcHelper: helper, kernelAPI: mockKernel, micAPI: mockMicAPI}
+		nodes = []v1.Node{
+			newNode("node1", "kernel-2"),
+			newNode("node2", "kernel-1+"),
+			newNode("node3", "kernel-1"),
+		}
+	})
+
+	It("should return an error if the MIC cannot be fetched", func() {
+		mockMicAPI.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, errors.New("random error"))
+
+		err := mrh.updateKernelsStatus(ctx, &mod, nodes)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the module loader data cannot be determined", func() {
+		gomock.InOrder(
+			mockMicAPI.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, mod.Name)),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleNodeOverrideList{}, gomock.Any()).Return(nil),
+			mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, "kernel-2").Return(nil, errors.New("random error")),
+		)
+
+		err := mrh.updateKernelsStatus(ctx, &mod, nodes)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the ModuleNodeOverrides cannot be listed", func() {
+		gomock.InOrder(
+			mockMicAPI.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, mod.Name)),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleNodeOverrideList{}, gomock.Any()).Return(errors.New("random error")),
+		)
+
+		err := mrh.updateKernelsStatus(ctx, &mod, nodes)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should report the status of the module for each kernel version", func() {
+		micObj := &kmmv1beta1.ModuleImagesConfig{}
+		nmc1 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "node2"}}
+		nmc2 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "node3"}}
+		nmc3 := kmmv1beta1.NodeModulesConfig{ObjectMeta: metav1.ObjectMeta{Name: "node4"}}
+		oldConfig := kmmv1beta1.ModuleConfig{ContainerImage: "image-0"}
+		overrideConfig := kmmv1beta1.ModuleConfig{
+			ContainerImage: "image-1",
+			Modprobe:       kmmv1beta1.ModprobeSpec{Parameters: []string{"a=1"}},
+		}
+
+		nodes[1].Labels = map[string]string{"override": "true"}
+		nodes = append(nodes, newNode("node4", "kernel-1"))
+
+		mockMicAPI.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(micObj, nil)
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+				list.Items = []kmmv1beta1.NodeModulesConfig{nmc1, nmc2, nmc3}
+				return nil
+			},
+		)
+		clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleNodeOverrideList{}, gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.ModuleNodeOverrideList, _ ...interface{}) error {
+				list.Items = []kmmv1beta1.ModuleNodeOverride{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "override", Namespace: mod.Namespace},
+						Spec: kmmv1beta1.ModuleNodeOverrideSpec{
+							ModuleName: mod.Name,
+							Selector:   map[string]string{"override": "true"},
+							Parameters: []string{"a=1"},
+						},
+					},
+				}
+				return nil
+			},
+		)
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, "kernel-2").Return(nil, module.ErrNoMatchingKernelMapping)
+		mockKernel.EXPECT().GetModuleLoaderDataForKernel(&mod, "kernel-1").Return(&api.ModuleLoaderData{ContainerImage: "image-1"}, nil)
+		mockMicAPI.EXPECT().GetImageState(micObj, "image-1").Return(kmmv1beta1.ImageExists)
+		helper.EXPECT().GetModuleStatusEntry(&nmc1, mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{Config: overrideConfig})
+		helper.EXPECT().GetModuleStatusEntry(&nmc2, mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{
+			Conditions: []kmmv1beta1.NodeModuleCondition{
+				{Type: kmmv1beta1.NodeModuleConditionLoadFailed, Status: metav1.ConditionTrue},
+			},
+		})
+		// node4 is held back by the upgrade strategy: its NMC spec and status still have the previous config
+		helper.EXPECT().GetModuleStatusEntry(&nmc3, mod.Namespace, mod.Name).Return(&kmmv1beta1.NodeModuleStatus{Config: oldConfig})
+
+		err := mrh.updateKernelsStatus(ctx, &mod, nodes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mod.Status.Kernels).To(Equal([]kmmv1beta1.KernelStatus{
+			{
+				KernelVersion: "kernel-1",
+				NodesNumber:   3,
+				Image:         "image-1",
+				ImageState:    kmmv1beta1.ImageExists,
+				LoadedNumber:  1,
+				FailedNumber:  1,
+			},
+			{
+				KernelVersion: "kernel-2",
+				NodesNumber:   1,
+			},
+		}))
 		Expect(mod.Status.ModuleLoader.AvailableNumber).To(Equal(int32(1)))
 	})
 })
@@ -1162,6 +1887,166 @@ var _ = Describe("updateImageRebuildTriggerGenerationStatus", func() {
 	})
 })
 
+var _ = Describe("updateConflictCondition", func() {
+	var (
+		ctx      context.Context
+		clnt     *client.MockClient
+		mod      kmmv1beta1.Module
+		mrh      *moduleReconcilerHelper
+		nodes    []v1.Node
+		recorder *record.FakeRecorder
+	)
+
+	newModule := func(namespace, name, moduleName string, selector map[string]string) kmmv1beta1.Module {
+		return kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: kmmv1beta1.ModuleSpec{
+				ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{
+					Container: kmmv1beta1.ModuleLoaderContainerSpec{
+						Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: moduleName},
+					},
+				},
+				Selector: selector,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		recorder = record.NewFakeRecorder(10)
+		mrh = &moduleReconcilerHelper{client: clnt, recorder: recorder}
+		mod = newModule("ns", "mod", "kmod", map[string]string{"role": "gpu"})
+		nodes = []v1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"role": "gpu"}}},
+		}
+	})
+
+	listModules := func(mods ...kmmv1beta1.Module) {
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.ModuleList, _ ...interface{}) error {
+				list.Items = append([]kmmv1beta1.Module{mod}, mods...)
+				return nil
+			},
+		)
+	}
+
+	It("should return an error if the Modules cannot be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any()).Return(errors.New("random error"))
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should set the condition to True if another Module loads the same kernel module on a targeted node", func() {
+		listModules(
+			newModule("other-ns", "other", "kmod", map[string]string{"role": "gpu"}),
+			newModule("ns", "different-kmod", "other-kmod", map[string]string{"role": "gpu"}),
+		)
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(mod.Status.Conditions).To(ConsistOf(And(
+			HaveField("Type", kmmv1beta1.ModuleConditionConflict),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Message", ContainSubstring("kmod by Module other-ns/other")),
+		)))
+	})
+
+	It("should set the condition to False if the conflicting Module targets other nodes", func() {
+		listModules(
+			newModule("other-ns", "other", "kmod", map[string]string{"role": "storage"}),
+		)
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(mod.Status.Conditions).To(ConsistOf(And(
+			HaveField("Type", kmmv1beta1.ModuleConditionConflict),
+			HaveField("Status", metav1.ConditionFalse),
+		)))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should record a warning event only when the condition changes", func() {
+		other := newModule("other-ns", "other", "kmod", map[string]string{"role": "gpu"})
+
+		listModules(other)
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring("Warning ModuleConflict"),
+			ContainSubstring("kmod by Module other-ns/other"),
+		)))
+
+		listModules(other)
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should record an event when the conflict is resolved", func() {
+		listModules(newModule("other-ns", "other", "kmod", map[string]string{"role": "gpu"}))
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning ModuleConflict")))
+
+		listModules()
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal ModuleConflictResolved")))
+	})
+
+	It("should report that conflicting Modules are rejected with the Reject policy", func() {
+		mrh.conflictPolicy = config.ConflictPolicyReject
+
+		listModules(newModule("other-ns", "other", "kmod", map[string]string{"role": "gpu"}))
+
+		Expect(
+			mrh.updateConflictCondition(ctx, &mod, nodes),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(mod.Status.Conditions).To(ConsistOf(And(
+			HaveField("Type", kmmv1beta1.ModuleConditionConflict),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", "ConflictRejected"),
+		)))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning ModuleConflict")))
+	})
+})
+
 var _ = Describe("namespaceHelper_setLabel", func() {
 	var (
 		ctx = context.TODO()
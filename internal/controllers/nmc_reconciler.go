@@ -5,6 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kubernetes-sigs/kernel-module-management/internal/node"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/pod"
@@ -12,11 +16,16 @@ import (
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/drain"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/filter"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/maintenance"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
+	"github.com/mitchellh/hashstructure/v2"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -38,10 +47,13 @@ const (
 )
 
 type NMCReconciler struct {
-	client     client.Client
-	helper     nmcReconcilerHelper
-	nodeAPI    node.Node
-	podManager pod.WorkerPodManager
+	client      client.Client
+	helper      nmcReconcilerHelper
+	nodeAPI     node.Node
+	podManager  pod.WorkerPodManager
+	retryPolicy *config.RetryPolicy
+	// maintenanceWindows are the default maintenance windows of modules.
+	maintenanceWindows []config.MaintenanceWindow
 }
 
 func NewNMCReconciler(
@@ -53,12 +65,22 @@ func NewNMCReconciler(
 	nodeAPI node.Node,
 	podManager pod.WorkerPodManager,
 ) *NMCReconciler {
-	helper := newNMCReconcilerHelper(client, podManager, recorder, nodeAPI)
+	helper := newNMCReconcilerHelper(
+		client,
+		podManager,
+		recorder,
+		nodeAPI,
+		drain.NewDrainer(client),
+		workerCfg.RetryPolicy,
+		workerCfg.MaintenanceWindows,
+	)
 	return &NMCReconciler{
-		client:     client,
-		helper:     helper,
-		nodeAPI:    nodeAPI,
-		podManager: podManager,
+		client:             client,
+		helper:             helper,
+		nodeAPI:            nodeAPI,
+		podManager:         podManager,
+		retryPolicy:        workerCfg.RetryPolicy,
+		maintenanceWindows: workerCfg.MaintenanceWindows,
 	}
 }
 
@@ -94,11 +116,12 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 
 	// Statuses are now up-to-date.
 
-	statusMap := make(map[string]*kmmv1beta1.NodeModuleStatus, len(nmcObj.Status.Modules))
+	// Statuses are looked up by name every time, as processing a module may add or remove entries from
+	// nmcObj.Status.Modules.
+	orphanStatuses := make(map[string]types.NamespacedName, len(nmcObj.Status.Modules))
 
-	for i := 0; i < len(nmcObj.Status.Modules); i++ {
-		status := nmcObj.Status.Modules[i]
-		statusMap[status.Namespace+"/"+status.Name] = &nmcObj.Status.Modules[i]
+	for _, status := range nmcObj.Status.Modules {
+		orphanStatuses[status.Namespace+"/"+status.Name] = types.NamespacedName{Namespace: status.Namespace, Name: status.Name}
 	}
 
 	errs := make([]error, 0, len(nmcObj.Spec.Modules)+len(nmcObj.Status.Modules))
@@ -108,31 +131,38 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 
 		logger := logger.WithValues("module", moduleNameKey)
 
+		// deleting status always (even in case of an error), so that it won't be treated
+		// as an orphaned status later in reconciliation
+		delete(orphanStatuses, moduleNameKey)
+
 		// skipping handling NMC spec module until node is ready
 		if !r.nodeAPI.IsNodeSchedulable(&node, mod.Tolerations) {
 			readyLabelsToRemove[utils.GetKernelModuleReadyNodeLabel(mod.Namespace, mod.Name)] = ""
 			readyLabelsToRemove[utils.GetKernelModuleVersionReadyNodeLabel(mod.Namespace, mod.Name)] = ""
-			delete(statusMap, moduleNameKey)
 			continue
 		}
-		if err := r.helper.ProcessModuleSpec(ctrl.LoggerInto(ctx, logger), &nmcObj, &mod, statusMap[moduleNameKey], &node); err != nil {
+
+		status := nmc.FindModuleStatus(nmcObj.Status.Modules, mod.Namespace, mod.Name)
+
+		if err := r.helper.ProcessModuleSpec(ctrl.LoggerInto(ctx, logger), &nmcObj, &mod, status, &node); err != nil {
 			errs = append(
 				errs,
 				fmt.Errorf("error processing Module %s: %v", moduleNameKey, err),
 			)
 		}
-
-		// deleting status always (even in case of an error), so that it won't be treated
-		// as an orphaned status later in reconciliation
-		delete(statusMap, moduleNameKey)
 	}
 
 	// We have processed all module specs.
 	// Now, go through the remaining, "orphan" statuses that do not have a corresponding spec; those must be unloaded.
 
-	for statusNameKey, status := range statusMap {
+	for statusNameKey, nsn := range orphanStatuses {
 		logger := logger.WithValues("status", statusNameKey)
 
+		status := nmc.FindModuleStatus(nmcObj.Status.Modules, nsn.Namespace, nsn.Name)
+		if status == nil {
+			continue
+		}
+
 		if err := r.helper.ProcessUnconfiguredModuleStatus(ctrl.LoggerInto(ctx, logger), &nmcObj, status, &node); err != nil {
 			errs = append(
 				errs,
@@ -141,6 +171,10 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 		}
 	}
 
+	if err := r.helper.CompleteDrain(ctx, &nmcObj, &node); err != nil {
+		errs = append(errs, fmt.Errorf("could not complete the drain of node %s: %v", node.Name, err))
+	}
+
 	// removing label of loaded kmods
 	if len(readyLabelsToRemove) != 0 {
 		if err := r.nodeAPI.UpdateLabels(ctx, &node, nil, readyLabelsToRemove); err != nil {
@@ -163,7 +197,69 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 		r.helper.RecordEvents(&node, loaded, unloaded)
 	}
 
-	return ctrl.Result{}, errors.Join(errs...)
+	res := ctrl.Result{}
+
+	// Requeue to retry the unload of modules that were in use or failed to unload.
+	for i := range nmcObj.Status.Modules {
+		status := &nmcObj.Status.Modules[i]
+
+		var spec *kmmv1beta1.NodeModuleSpec
+
+		for j := range nmcObj.Spec.Modules {
+			if s := &nmcObj.Spec.Modules[j]; s.Namespace == status.Namespace && s.Name == status.Name {
+				spec = s
+			}
+		}
+
+		policy := effectiveRetryPolicy(r.retryPolicy, retryPolicyItem(spec, status))
+
+		if d, _ := unloadRetryDelay(policy, spec, status); d > 0 && (res.RequeueAfter == 0 || d < res.RequeueAfter) {
+			res.RequeueAfter = d
+		}
+	}
+
+	// Requeue to evict the Pods that were still running on the node.
+	if d := nmcObj.Status.Drain; d != nil && d.Phase == kmmv1beta1.NodeDrainEvicting {
+		if res.RequeueAfter == 0 || drainRetryDelay < res.RequeueAfter {
+			res.RequeueAfter = drainRetryDelay
+		}
+	}
+
+	// Requeue to retry loading modules once their backoff has elapsed.
+	for i := range nmcObj.Spec.Modules {
+		spec := &nmcObj.Spec.Modules[i]
+
+		status := nmc.FindModuleStatus(nmcObj.Status.Modules, spec.Namespace, spec.Name)
+
+		if d, _ := loadRetryDelay(effectiveRetryPolicy(r.retryPolicy, &spec.ModuleItem), spec, status); d > 0 && (res.RequeueAfter == 0 || d < res.RequeueAfter) {
+			res.RequeueAfter = d
+		}
+	}
+
+	// Requeue to apply the changes that are held back once a maintenance window opens.
+	now := time.Now()
+
+	for i := range nmcObj.Status.Modules {
+		status := &nmcObj.Status.Modules[i]
+
+		if !maintenanceWindowPending(status) {
+			continue
+		}
+
+		item := &status.ModuleItem
+
+		for j := range nmcObj.Spec.Modules {
+			if spec := &nmcObj.Spec.Modules[j]; spec.Namespace == status.Namespace && spec.Name == status.Name {
+				item = &spec.ModuleItem
+			}
+		}
+
+		if d, err := maintenanceDelay(r.maintenanceWindows, item, now); err == nil && d > 0 && (res.RequeueAfter == 0 || d < res.RequeueAfter) {
+			res.RequeueAfter = d
+		}
+	}
+
+	return res, errors.Join(errs...)
 }
 
 func (r *NMCReconciler) SetupWithManager(ctx context.Context, mgr manager.Manager) error {
@@ -209,6 +305,7 @@ func GetContainerStatus(statuses []v1.ContainerStatus, name string) v1.Container
 //go:generate mockgen -source=nmc_reconciler.go -package=controllers -destination=mock_nmc_reconciler.go workerHelper
 
 type nmcReconcilerHelper interface {
+	CompleteDrain(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, node *v1.Node) error
 	GarbageCollectInUseLabels(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig) error
 	GarbageCollectWorkerPods(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig) error
 	ProcessModuleSpec(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, spec *kmmv1beta1.NodeModuleSpec, status *kmmv1beta1.NodeModuleStatus, node *v1.Node) error
@@ -224,16 +321,32 @@ type nmcReconcilerHelperImpl struct {
 	podManager pod.WorkerPodManager
 	recorder   record.EventRecorder
 	nodeAPI    node.Node
+	drainer    drain.Drainer
 	lph        labelPreparationHelper
+	// retryPolicy is the default retry policy of loader Pods; nil means that they are retried without limit.
+	retryPolicy *config.RetryPolicy
+	// maintenanceWindows are the default maintenance windows of modules; empty means that changes are always applied.
+	maintenanceWindows []config.MaintenanceWindow
 }
 
-func newNMCReconcilerHelper(client client.Client, podManager pod.WorkerPodManager, recorder record.EventRecorder, nodeAPI node.Node) nmcReconcilerHelper {
+func newNMCReconcilerHelper(
+	client client.Client,
+	podManager pod.WorkerPodManager,
+	recorder record.EventRecorder,
+	nodeAPI node.Node,
+	drainer drain.Drainer,
+	retryPolicy *config.RetryPolicy,
+	maintenanceWindows []config.MaintenanceWindow,
+) nmcReconcilerHelper {
 	return &nmcReconcilerHelperImpl{
-		client:     client,
-		podManager: podManager,
-		recorder:   recorder,
-		nodeAPI:    nodeAPI,
-		lph:        newLabelPreparationHelper(),
+		client:             client,
+		podManager:         podManager,
+		recorder:           recorder,
+		nodeAPI:            nodeAPI,
+		drainer:            drainer,
+		lph:                newLabelPreparationHelper(),
+		retryPolicy:        retryPolicy,
+		maintenanceWindows: maintenanceWindows,
 	}
 }
 
@@ -330,8 +443,15 @@ func (h *nmcReconcilerHelperImpl) GarbageCollectInUseLabels(ctx context.Context,
 //     of the Ready condition on the node. This makes sure that we always load modules after maintenance operations
 //     that would make a node not Ready, such as a reboot.
 //
+// Loading worker Pods are held back until the modules that the entry depends on are loaded, and, after a failed load,
+// until the backoff of the retry policy elapsed.
+//
 // An unloading worker Pod is created when the entry in .spec.modules has a different config compared to the entry in
 // .status.modules.
+// If only the module parameters changed, a worker Pod that sets them at runtime is created instead; if that fails,
+// the module is reloaded.
+// Both are held back until one of the module's maintenance windows opens; loading worker Pods are not, since the
+// module is missing from the node.
 func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	ctx context.Context,
 	nmcObj *kmmv1beta1.NodeModulesConfig,
@@ -351,6 +471,14 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	if p == nil {
 		// new module is introduced, need to load it
 		if status == nil {
+			if h.holdUntilLoadRetry(ctx, spec, status) {
+				return nil
+			}
+
+			if held, err := h.holdUntilDependenciesLoaded(ctx, nmcObj, spec, node); held {
+				return err
+			}
+
 			logger.Info("Missing status; creating loader Pod")
 			return h.podManager.CreateLoaderPod(ctx, nmcObj, spec)
 		}
@@ -361,16 +489,82 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 		*/
 		if !reflect.DeepEqual(spec.Config, status.Config) {
 			if spec.Config.KernelVersion == status.Config.KernelVersion {
+				if held, err := h.holdUntilMaintenanceWindow(ctx, nmcObj, &spec.ModuleItem, status); held {
+					return err
+				}
+			}
+
+			if onlyParametersChanged(status.Config, spec.Config) && !setParametersFailed(status) {
+				logger.Info("Only module parameters changed; creating set-parameters Pod")
+				if err = h.podManager.CreateSetParametersPod(ctx, nmcObj, spec); err != nil {
+					return err
+				}
+
+				return h.patchProgressing(ctx, nmcObj, status, kmmv1beta1.WorkerActionSetParameters)
+			}
+
+			if spec.Config.KernelVersion == status.Config.KernelVersion {
+				if h.holdUntilUnloadRetry(ctx, spec, status) {
+					return nil
+				}
+
+				if held, err := h.holdUntilDependentsUnloaded(ctx, nmcObj, status, node); held {
+					return err
+				}
+
+				if drained, err := h.drainBeforeUnload(ctx, nmcObj, node, spec.Namespace, spec.Name, spec.DrainPolicy); !drained {
+					return err
+				}
+
 				logger.Info("Outdated config in status; creating unloader Pod")
-				return h.podManager.CreateUnloaderPod(ctx, nmcObj, status)
+				if err = h.podManager.CreateUnloaderPod(ctx, nmcObj, status); err != nil {
+					return err
+				}
+
+				return h.patchProgressing(ctx, nmcObj, status, kmmv1beta1.WorkerActionUnload)
+			}
+			if h.holdUntilLoadRetry(ctx, spec, status) {
+				return nil
 			}
+
+			if held, err := h.holdUntilDependenciesLoaded(ctx, nmcObj, spec, node); held {
+				return err
+			}
+
 			logger.Info("Outdated config in status and kernels differ, probably due to upgrade; creating loader Pod")
-			return h.podManager.CreateLoaderPod(ctx, nmcObj, spec)
+			if err = h.podManager.CreateLoaderPod(ctx, nmcObj, spec); err != nil {
+				return err
+			}
+
+			return h.patchProgressing(ctx, nmcObj, status, kmmv1beta1.WorkerActionLoad)
 		}
 
 		if h.nodeAPI.IsNodeRebooted(node, status.BootId) {
+			if h.holdUntilLoadRetry(ctx, spec, status) {
+				return nil
+			}
+
+			if held, err := h.holdUntilDependenciesLoaded(ctx, nmcObj, spec, node); held {
+				return err
+			}
+
 			logger.Info("node has been rebooted and become ready after kernel module was loaded; creating loader Pod")
-			return h.podManager.CreateLoaderPod(ctx, nmcObj, spec)
+			if err = h.podManager.CreateLoaderPod(ctx, nmcObj, spec); err != nil {
+				return err
+			}
+
+			return h.patchProgressing(ctx, nmcObj, status, kmmv1beta1.WorkerActionLoad)
+		}
+
+		if maintenanceWindowPending(status) {
+			logger.Info("The held back change was reverted; clearing the pending condition")
+			patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+			nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+				Type:   kmmv1beta1.NodeModuleConditionMaintenanceWindowPending,
+				Status: metav1.ConditionFalse,
+				Reason: conditionReasonUpToDate,
+			})
+			return h.client.Status().Patch(ctx, nmcObj, patchFrom)
 		}
 
 		return nil
@@ -399,13 +593,68 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	return nil
 }
 
+// patchProgressing sets the Progressing condition of status, which must be an entry of nmcObj's status, after a
+// worker Pod doing action was created.
+func (h *nmcReconcilerHelperImpl) patchProgressing(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	status *kmmv1beta1.NodeModuleStatus,
+	action kmmv1beta1.WorkerAction,
+) error {
+	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+	setProgressing(status, action)
+
+	if err := h.client.Status().Patch(ctx, nmcObj, patchFrom); err != nil {
+		return fmt.Errorf("could not patch the status of NodeModulesConfig %s: %v", nmcObj.Name, err)
+	}
+
+	return nil
+}
+
+// onlyParametersChanged returns true if the module parameters are the only difference between the old and the new
+// config, and if no parameter was removed: only a reload resets a parameter to its default value.
+func onlyParametersChanged(old, new kmmv1beta1.ModuleConfig) bool {
+	if old.Modprobe.RawArgs != nil || new.Modprobe.RawArgs != nil {
+		return false
+	}
+
+	if reflect.DeepEqual(old.Modprobe.Parameters, new.Modprobe.Parameters) {
+		return false
+	}
+
+	newKeys := sets.New[string]()
+
+	for _, p := range new.Modprobe.Parameters {
+		key, _, _ := strings.Cut(p, "=")
+		newKeys.Insert(key)
+	}
+
+	for _, p := range old.Modprobe.Parameters {
+		if key, _, _ := strings.Cut(p, "="); !newKeys.Has(key) {
+			return false
+		}
+	}
+
+	old.Modprobe.Parameters = nil
+	new.Modprobe.Parameters = nil
+
+	return reflect.DeepEqual(old, new)
+}
+
+// setParametersFailed returns true if the last attempt to change the module parameters at runtime failed.
+func setParametersFailed(status *kmmv1beta1.NodeModuleStatus) bool {
+	return status.LastFailure != nil && status.LastFailure.Action == kmmv1beta1.WorkerActionSetParameters
+}
+
 // ProcessUnconfiguredModuleStatus cleans up a NodeModuleStatus.
 // It should be called for each status entry for which the NodeModulesConfigs does not have a spec entry; this means
 // that KMM wants the module unloaded from the node.
-// If status.Config field is nil, then it represents a module that could not be loaded by a worker Pod.
+// If status.Config field is empty, then it represents a module that a worker Pod did not load (yet).
 // ProcessUnconfiguredModuleStatus will then remove status from nmcObj's Status.Modules.
 // If status.Config is not nil, it means that the module was successfully loaded.
-// ProcessUnconfiguredModuleStatus will then create a worker pod to unload the module.
+// ProcessUnconfiguredModuleStatus will then create a worker pod to unload the module, once the modules that depend on it
+// were unloaded.
 func (h *nmcReconcilerHelperImpl) ProcessUnconfiguredModuleStatus(
 	ctx context.Context,
 	nmcObj *kmmv1beta1.NodeModulesConfig,
@@ -426,18 +675,41 @@ func (h *nmcReconcilerHelperImpl) ProcessUnconfiguredModuleStatus(
 		return h.client.Status().Patch(ctx, nmcObj, patchFrom)
 	}
 
+	if reflect.ValueOf(status.Config).IsZero() {
+		logger.Info("Module was never loaded; deleting the status")
+		patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+		nmc.RemoveModuleStatus(&nmcObj.Status.Modules, status.Namespace, status.Name)
+		return h.client.Status().Patch(ctx, nmcObj, patchFrom)
+	}
+
 	p, err := h.podManager.GetWorkerPod(ctx, podName, status.Namespace)
 	if err != nil {
 		return fmt.Errorf("error while getting the worker Pod %s: %v", podName, err)
 	}
 
 	if p == nil {
+		if h.holdUntilUnloadRetry(ctx, nil, status) {
+			return nil
+		}
+
+		if held, err := h.holdUntilDependentsUnloaded(ctx, nmcObj, status, node); held {
+			return err
+		}
+
+		if held, err := h.holdUntilMaintenanceWindow(ctx, nmcObj, &status.ModuleItem, status); held {
+			return err
+		}
+
+		if drained, err := h.drainBeforeUnload(ctx, nmcObj, node, status.Namespace, status.Name, status.DrainPolicy); !drained {
+			return err
+		}
+
 		logger.Info("Worker Pod does not exist; creating it")
 		return h.podManager.CreateUnloaderPod(ctx, nmcObj, status)
 	}
 
-	if h.podManager.IsLoaderPod(p) {
-		logger.Info("Worker Pod is loading the kmod; deleting it")
+	if h.podManager.IsLoaderPod(p) || h.podManager.IsSetParametersPod(p) {
+		logger.Info("Worker Pod is loading the kmod or setting its parameters; deleting it")
 		return h.podManager.DeletePod(ctx, p)
 	}
 
@@ -459,6 +731,566 @@ func (h *nmcReconcilerHelperImpl) ProcessUnconfiguredModuleStatus(
 	return nil
 }
 
+// holdUntilLoadRetry returns true if no loader Pod must be created for spec yet, because the module failed to load and
+// the retry policy asks to wait before retrying, or to stop retrying.
+func (h *nmcReconcilerHelperImpl) holdUntilLoadRetry(
+	ctx context.Context,
+	spec *kmmv1beta1.NodeModuleSpec,
+	status *kmmv1beta1.NodeModuleStatus,
+) bool {
+	logger := ctrl.LoggerFrom(ctx)
+
+	d, exhausted := loadRetryDelay(effectiveRetryPolicy(h.retryPolicy, &spec.ModuleItem), spec, status)
+	if exhausted {
+		logger.Info("Module reached its maximum number of load attempts; not retrying")
+		return true
+	}
+
+	if d > 0 {
+		logger.Info("Module failed to load; waiting before retrying", "delay", d)
+		return true
+	}
+
+	return false
+}
+
+// holdUntilUnloadRetry returns true if no unloader Pod must be created for status yet, because the module failed to
+// unload and the retry policy asks to wait before retrying, or to stop retrying.
+// spec is nil if the module is not configured on the node anymore.
+func (h *nmcReconcilerHelperImpl) holdUntilUnloadRetry(
+	ctx context.Context,
+	spec *kmmv1beta1.NodeModuleSpec,
+	status *kmmv1beta1.NodeModuleStatus,
+) bool {
+	logger := ctrl.LoggerFrom(ctx)
+
+	d, exhausted := unloadRetryDelay(effectiveRetryPolicy(h.retryPolicy, retryPolicyItem(spec, status)), spec, status)
+	if exhausted {
+		logger.Info("Module reached its maximum number of unload attempts; not retrying")
+		return true
+	}
+
+	if d <= 0 {
+		return false
+	}
+
+	if status.LastFailure.Reason == kmmv1beta1.WorkerFailureReasonModuleInUse {
+		logger.Info("Module was in use during the last unload attempt; retrying later", "delay", d)
+	} else {
+		logger.Info("Module failed to unload; waiting before retrying", "delay", d)
+	}
+
+	return true
+}
+
+// holdUntilDependenciesLoaded returns true if the module that spec describes must not be loaded until the modules it
+// depends on are loaded on node; the DependenciesPending condition of the module's status then lists them.
+func (h *nmcReconcilerHelperImpl) holdUntilDependenciesLoaded(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	spec *kmmv1beta1.NodeModuleSpec,
+	node *v1.Node,
+) (bool, error) {
+	pending := make([]string, 0, len(spec.DependsOn))
+
+	for _, dep := range spec.DependsOn {
+		if !h.moduleLoaded(nmcObj, dep.Namespace, dep.Name, node) {
+			pending = append(pending, dep.Namespace+"/"+dep.Name)
+		}
+	}
+
+	if len(pending) == 0 {
+		return false, nil
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Waiting for dependencies to be loaded before loading the module", "dependencies", pending)
+
+	return true, h.patchDependenciesPending(
+		ctx,
+		nmcObj,
+		spec.Namespace,
+		spec.Name,
+		conditionReasonWaitingForDependencies,
+		fmt.Sprintf("waiting for %s to be loaded", strings.Join(pending, ", ")),
+	)
+}
+
+// moduleLoaded returns true if the module namespace/name is loaded on node with the config in nmcObj's spec, and if no
+// worker Pod is changing it.
+// Statuses written before KMM set the Loaded condition are trusted if their config is the one in nmcObj's spec.
+func (h *nmcReconcilerHelperImpl) moduleLoaded(nmcObj *kmmv1beta1.NodeModulesConfig, namespace, name string, node *v1.Node) bool {
+	status := nmc.FindModuleStatus(nmcObj.Status.Modules, namespace, name)
+	if status == nil || h.nodeAPI.IsNodeRebooted(node, status.BootId) {
+		return false
+	}
+
+	loaded := nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionLoaded)
+	if loaded != nil && loaded.Status != metav1.ConditionTrue {
+		return false
+	}
+
+	if c := nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionProgressing); c != nil && c.Status == metav1.ConditionTrue {
+		return false
+	}
+
+	for _, spec := range nmcObj.Spec.Modules {
+		if spec.Namespace == namespace && spec.Name == name {
+			return reflect.DeepEqual(spec.Config, status.Config)
+		}
+	}
+
+	return loaded != nil
+}
+
+// holdUntilDependentsUnloaded returns true if the module that status describes must not be unloaded from node until
+// the modules that depend on it and that are being unloaded as well are; the DependenciesPending condition of status
+// then lists them.
+// Modules that depend on it and that stay loaded do not hold the unload back.
+func (h *nmcReconcilerHelperImpl) holdUntilDependentsUnloaded(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	status *kmmv1beta1.NodeModuleStatus,
+	node *v1.Node,
+) (bool, error) {
+	ref := kmmv1beta1.ModuleReference{Name: status.Name, Namespace: status.Namespace}
+
+	pending := make([]string, 0)
+
+	for _, s := range nmcObj.Status.Modules {
+		dependsOn := s.DependsOn
+		unloading := true
+
+		for _, spec := range nmcObj.Spec.Modules {
+			if spec.Namespace == s.Namespace && spec.Name == s.Name {
+				dependsOn = spec.DependsOn
+				unloading = !reflect.DeepEqual(spec.Config, s.Config) && spec.Config.KernelVersion == s.Config.KernelVersion
+			}
+		}
+
+		if !unloading || !slices.Contains(dependsOn, ref) {
+			continue
+		}
+
+		// modules that were never loaded or that were lost in a reboot need not be unloaded
+		if reflect.ValueOf(s.Config).IsZero() || h.nodeAPI.IsNodeRebooted(node, s.BootId) {
+			continue
+		}
+
+		pending = append(pending, s.Namespace+"/"+s.Name)
+	}
+
+	if len(pending) == 0 {
+		return false, nil
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Waiting for dependent modules to be unloaded before unloading the module", "dependents", pending)
+
+	return true, h.patchDependenciesPending(
+		ctx,
+		nmcObj,
+		status.Namespace,
+		status.Name,
+		conditionReasonWaitingForDependents,
+		fmt.Sprintf("waiting for %s to be unloaded", strings.Join(pending, ", ")),
+	)
+}
+
+// patchDependenciesPending sets the DependenciesPending condition of the status of the module namespace/name to True.
+// If nmcObj has no status for the module yet, an entry is added to hold the condition.
+// It only patches nmcObj if the condition changed.
+func (h *nmcReconcilerHelperImpl) patchDependenciesPending(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	namespace, name string,
+	reason, message string,
+) error {
+	status := nmc.FindModuleStatus(nmcObj.Status.Modules, namespace, name)
+
+	if status != nil {
+		if existing := nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionDependenciesPending); existing != nil &&
+			existing.Status == metav1.ConditionTrue && existing.Reason == reason && existing.Message == message {
+			return nil
+		}
+	}
+
+	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+	if status == nil {
+		nmcObj.Status.Modules = append(nmcObj.Status.Modules, kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+		})
+
+		status = &nmcObj.Status.Modules[len(nmcObj.Status.Modules)-1]
+	}
+
+	nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+		Type:    kmmv1beta1.NodeModuleConditionDependenciesPending,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+
+	if err := h.client.Status().Patch(ctx, nmcObj, patchFrom); err != nil {
+		return fmt.Errorf("could not patch the status of NodeModulesConfig %s: %v", nmcObj.Name, err)
+	}
+
+	return nil
+}
+
+// holdUntilMaintenanceWindow returns true if the change to the module that status describes must wait until one of the
+// maintenance windows of item opens; the MaintenanceWindowPending condition of status then tells when.
+// Changes are never held back while the node is being drained for the module, so that it is not left cordoned.
+func (h *nmcReconcilerHelperImpl) holdUntilMaintenanceWindow(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	item *kmmv1beta1.ModuleItem,
+	status *kmmv1beta1.NodeModuleStatus,
+) (bool, error) {
+	if d := nmcObj.Status.Drain; d != nil && d.Phase != kmmv1beta1.NodeDrainCompleted &&
+		slices.Contains(d.Modules, kmmv1beta1.NodeDrainModule{Namespace: status.Namespace, Name: status.Name}) {
+		return false, nil
+	}
+
+	now := time.Now()
+
+	delay, err := maintenanceDelay(h.maintenanceWindows, item, now)
+	if err != nil {
+		return true, fmt.Errorf("invalid maintenance windows: %v", err)
+	}
+
+	if delay == 0 {
+		return false, nil
+	}
+
+	c := kmmv1beta1.NodeModuleCondition{
+		Type:   kmmv1beta1.NodeModuleConditionMaintenanceWindowPending,
+		Status: metav1.ConditionTrue,
+		Reason: conditionReasonWaitingForMaintenanceWindow,
+		Message: fmt.Sprintf(
+			"the change will be applied in the maintenance window opening at %s",
+			now.Add(delay).UTC().Format(time.RFC3339),
+		),
+	}
+
+	if existing := nmc.FindModuleCondition(status.Conditions, c.Type); existing != nil &&
+		existing.Status == c.Status && existing.Message == c.Message {
+		return true, nil
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Holding back the change until the next maintenance window", "delay", delay)
+
+	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+	nmc.SetModuleCondition(status, c)
+
+	if err = h.client.Status().Patch(ctx, nmcObj, patchFrom); err != nil {
+		return true, fmt.Errorf("could not patch the status of NodeModulesConfig %s: %v", nmcObj.Name, err)
+	}
+
+	return true, nil
+}
+
+// maintenanceDelay returns how long changes to the module that item describes must wait until one of its maintenance
+// windows opens, or one of defaultWindows if it has none.
+// It returns 0 if a window is open at now or if there are no windows.
+func maintenanceDelay(defaultWindows []config.MaintenanceWindow, item *kmmv1beta1.ModuleItem, now time.Time) (time.Duration, error) {
+	windows := make([]*maintenance.Window, 0, max(len(item.MaintenanceWindows), len(defaultWindows)))
+
+	if len(item.MaintenanceWindows) > 0 {
+		for _, mw := range item.MaintenanceWindows {
+			w, err := maintenance.NewWindow(mw.Schedule, mw.Duration.Duration)
+			if err != nil {
+				return 0, err
+			}
+
+			windows = append(windows, w)
+		}
+	} else {
+		for _, mw := range defaultWindows {
+			w, err := maintenance.NewWindow(mw.Schedule, mw.Duration)
+			if err != nil {
+				return 0, err
+			}
+
+			windows = append(windows, w)
+		}
+	}
+
+	return maintenance.Delay(windows, now)
+}
+
+// maintenanceWindowPending returns true if a change to the module is held back until a maintenance window opens.
+func maintenanceWindowPending(status *kmmv1beta1.NodeModuleStatus) bool {
+	c := nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionMaintenanceWindowPending)
+
+	return c != nil && c.Status == metav1.ConditionTrue
+}
+
+// drainBeforeUnload drains the node according to policy before the module namespace/name is unloaded from it.
+// The first module that needs a drain cordons the node; the drain and the modules it was started for are reported in
+// the status of nmcObj.
+// It returns true if policy is nil or if no Pod that policy selects is running on the node anymore.
+func (h *nmcReconcilerHelperImpl) drainBeforeUnload(
+	ctx context.Context,
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	node *v1.Node,
+	namespace, name string,
+	policy *kmmv1beta1.DrainPolicy,
+) (bool, error) {
+	if policy == nil {
+		return true, nil
+	}
+
+	logger := ctrl.LoggerFrom(ctx)
+
+	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+	d := nmcObj.Status.Drain
+
+	if d == nil || d.Phase == kmmv1beta1.NodeDrainCompleted {
+		logger.Info("Cordoning the node before unloading the module")
+
+		cordoned, err := h.drainer.Cordon(ctx, node)
+		if err != nil {
+			return false, err
+		}
+
+		d = &kmmv1beta1.NodeDrainStatus{Cordoned: cordoned, StartTime: metav1.Now()}
+		nmcObj.Status.Drain = d
+	}
+
+	if m := (kmmv1beta1.NodeDrainModule{Namespace: namespace, Name: name}); !slices.Contains(d.Modules, m) {
+		d.Modules = append(d.Modules, m)
+	}
+
+	remaining, err := h.drainer.EvictPods(ctx, node.Name, policy)
+
+	d.Phase = kmmv1beta1.NodeDrainEvicting
+	d.RemainingPods = int32(remaining)
+
+	switch {
+	case err != nil:
+		d.Message = err.Error()
+		err = fmt.Errorf("could not evict Pods: %v", err)
+	case remaining > 0:
+		logger.Info("Waiting for Pods to be evicted before unloading the module", "count", remaining)
+		d.Message = fmt.Sprintf("waiting for %d Pods to be evicted", remaining)
+	default:
+		d.Phase = kmmv1beta1.NodeDrainDrained
+		d.Message = ""
+	}
+
+	if perr := h.client.Status().Patch(ctx, nmcObj, patchFrom); perr != nil {
+		return false, errors.Join(err, fmt.Errorf("could not patch the status of NodeModulesConfig %s: %v", nmcObj.Name, perr))
+	}
+
+	return d.Phase == kmmv1beta1.NodeDrainDrained, err
+}
+
+// CompleteDrain uncordons the node if KMM cordoned it, once all modules for which it was drained were either unloaded
+// or loaded again with the config in the spec.
+func (h *nmcReconcilerHelperImpl) CompleteDrain(ctx context.Context, nmcObj *kmmv1beta1.NodeModulesConfig, node *v1.Node) error {
+	d := nmcObj.Status.Drain
+
+	if d == nil || d.Phase == kmmv1beta1.NodeDrainCompleted {
+		return nil
+	}
+
+	specs := make(map[kmmv1beta1.NodeDrainModule]*kmmv1beta1.NodeModuleSpec, len(nmcObj.Spec.Modules))
+
+	for i := range nmcObj.Spec.Modules {
+		s := &nmcObj.Spec.Modules[i]
+		specs[kmmv1beta1.NodeDrainModule{Namespace: s.Namespace, Name: s.Name}] = s
+	}
+
+	for _, m := range d.Modules {
+		spec := specs[m]
+		status := nmc.FindModuleStatus(nmcObj.Status.Modules, m.Namespace, m.Name)
+
+		if spec == nil && status != nil {
+			// still to be unloaded
+			return nil
+		}
+
+		if spec != nil && (status == nil || !reflect.DeepEqual(spec.Config, status.Config)) {
+			// still to be loaded again
+			return nil
+		}
+	}
+
+	if d.Cordoned {
+		ctrl.LoggerFrom(ctx).Info("Uncordoning the node")
+
+		if err := h.drainer.Uncordon(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+	d.Phase = kmmv1beta1.NodeDrainCompleted
+	d.RemainingPods = 0
+	d.Message = ""
+	now := metav1.Now()
+	d.CompletionTime = &now
+
+	if err := h.client.Status().Patch(ctx, nmcObj, patchFrom); err != nil {
+		return fmt.Errorf("could not patch the status of NodeModulesConfig %s: %v", nmcObj.Name, err)
+	}
+
+	return nil
+}
+
+const drainRetryDelay = 10 * time.Second
+
+const (
+	defaultRetryInitialBackoff = 10 * time.Second
+	defaultRetryMaxBackoff     = 5 * time.Minute
+)
+
+// effectiveRetryPolicy returns the retry policy of item if it has one, and defaultPolicy otherwise.
+func effectiveRetryPolicy(defaultPolicy *config.RetryPolicy, item *kmmv1beta1.ModuleItem) *config.RetryPolicy {
+	rp := item.RetryPolicy
+	if rp == nil {
+		return defaultPolicy
+	}
+
+	policy := config.RetryPolicy{MaxAttempts: rp.MaxAttempts}
+
+	if rp.InitialBackoff != nil {
+		policy.InitialBackoff = rp.InitialBackoff.Duration
+	}
+
+	if rp.MaxBackoff != nil {
+		policy.MaxBackoff = rp.MaxBackoff.Duration
+	}
+
+	return &policy
+}
+
+// retryBackoff returns how long to wait after the n-th consecutive failure before retrying.
+func retryBackoff(policy *config.RetryPolicy, n int32) time.Duration {
+	backoff := policy.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryInitialBackoff
+	}
+
+	maxBackoff := policy.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	for i := int32(1); i < n && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxBackoff)
+}
+
+// loadRetryDelay returns how long to wait before creating a new loader Pod for spec, and whether the module reached
+// the maximum number of attempts of policy.
+// Failures of a previous config or retry token are not taken into account.
+func loadRetryDelay(
+	policy *config.RetryPolicy,
+	spec *kmmv1beta1.NodeModuleSpec,
+	status *kmmv1beta1.NodeModuleStatus,
+) (time.Duration, bool) {
+	if policy == nil || status == nil {
+		return 0, false
+	}
+
+	f := status.LastFailure
+	if f == nil || f.Action != kmmv1beta1.WorkerActionLoad || f.SpecHash != specHash(spec) {
+		return 0, false
+	}
+
+	c := nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionLoadFailed)
+	if c == nil || c.Status != metav1.ConditionTrue {
+		return 0, false
+	}
+
+	if policy.MaxAttempts > 0 && c.FailureCount >= policy.MaxAttempts {
+		return 0, true
+	}
+
+	return time.Until(f.Time.Add(retryBackoff(policy, c.FailureCount))), false
+}
+
+// specHash returns a hash of the config and the retry token of spec.
+func specHash(spec *kmmv1beta1.NodeModuleSpec) string {
+	h, err := hashstructure.Hash(
+		struct {
+			Config     kmmv1beta1.ModuleConfig
+			RetryToken string
+		}{Config: spec.Config, RetryToken: spec.RetryToken},
+		hashstructure.FormatV2,
+		nil,
+	)
+	if err != nil {
+		return ""
+	}
+
+	return strconv.FormatUint(h, 16)
+}
+
+// unloadBlockedRetryPeriod is the minimum duration between two attempts to unload a module that was in use.
+const unloadBlockedRetryPeriod = 5 * time.Minute
+
+// unloadRetryDelay returns how long to wait before creating a new unloader Pod for status, and whether the module
+// reached the maximum number of unload attempts of policy.
+// A module that was in use is retried every unloadBlockedRetryPeriod, without limit, since it can be unloaded once it
+// is released.
+// spec is nil if the module is not configured on the node anymore; failures that happened while spec was different
+// are not taken into account.
+func unloadRetryDelay(
+	policy *config.RetryPolicy,
+	spec *kmmv1beta1.NodeModuleSpec,
+	status *kmmv1beta1.NodeModuleStatus,
+) (time.Duration, bool) {
+	f := status.LastFailure
+	if f == nil || f.Action != kmmv1beta1.WorkerActionUnload {
+		return 0, false
+	}
+
+	if f.Reason == kmmv1beta1.WorkerFailureReasonModuleInUse {
+		return time.Until(f.Time.Add(unloadBlockedRetryPeriod)), false
+	}
+
+	if policy == nil {
+		return 0, false
+	}
+
+	hash := ""
+	if spec != nil {
+		hash = specHash(spec)
+	}
+
+	if f.SpecHash != hash {
+		return 0, false
+	}
+
+	c := nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionUnloadFailed)
+	if c == nil || c.Status != metav1.ConditionTrue {
+		return 0, false
+	}
+
+	if policy.MaxAttempts > 0 && c.FailureCount >= policy.MaxAttempts {
+		return 0, true
+	}
+
+	return time.Until(f.Time.Add(retryBackoff(policy, c.FailureCount))), false
+}
+
+// retryPolicyItem returns the ModuleItem whose retry policy applies to the module: the one of spec if the module is
+// configured on the node, and the one of status otherwise.
+func retryPolicyItem(spec *kmmv1beta1.NodeModuleSpec, status *kmmv1beta1.NodeModuleStatus) *kmmv1beta1.ModuleItem {
+	if spec != nil {
+		return &spec.ModuleItem
+	}
+
+	return &status.ModuleItem
+}
+
 func (h *nmcReconcilerHelperImpl) RemovePodFinalizers(ctx context.Context, nodeName string) error {
 	pods, err := h.podManager.ListWorkerPodsOnNode(ctx, nodeName)
 	if err != nil {
@@ -503,10 +1335,11 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 		return nil
 	}
 
-	specEntries := sets.New[types.NamespacedName]()
+	specs := make(map[types.NamespacedName]*kmmv1beta1.NodeModuleSpec, len(nmcObj.Spec.Modules))
 
-	for _, e := range nmcObj.Spec.Modules {
-		specEntries.Insert(types.NamespacedName{Namespace: e.Namespace, Name: e.Name})
+	for i := range nmcObj.Spec.Modules {
+		e := &nmcObj.Spec.Modules[i]
+		specs[types.NamespacedName{Namespace: e.Namespace, Name: e.Name}] = e
 	}
 
 	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
@@ -525,15 +1358,88 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 		logger.Info("Processing worker Pod")
 
 		status := nmc.FindModuleStatus(nmcObj.Status.Modules, modNamespace, modName)
+		spec := specs[types.NamespacedName{Namespace: modNamespace, Name: modName}]
 
 		switch phase {
+		case v1.PodPending:
+			if status == nil && spec == nil {
+				break
+			}
+
+			setProgressing(moduleStatusForPod(&nmcObj.Status.Modules, &p), h.workerAction(&p))
 		case v1.PodRunning:
 			// Delete Pod if orphan
-			if !specEntries.Has(types.NamespacedName{Namespace: modNamespace, Name: modName}) && status == nil {
+			if spec == nil && status == nil {
 				logger.Info("Orphan pod; deleting")
 				podsToDelete = append(podsToDelete, p)
+				break
+			}
+
+			action := h.workerAction(&p)
+			status = moduleStatusForPod(&nmcObj.Status.Modules, &p)
+
+			setProgressing(status, action)
+
+			// Do not let the kubelet restart a worker that cannot unload a busy module; ProcessUnconfiguredModuleStatus
+			// retries the unload later.
+			f := workerFailure(&p)
+			if f == nil {
+				break
+			}
+
+			f.Action = action
+			setSpecHash(f, spec)
+			recordWorkerFailure(status, f)
+
+			// With a retry policy, loader Pods are recreated by ProcessModuleSpec after a backoff.
+			if f.Action == kmmv1beta1.WorkerActionLoad && spec != nil {
+				if policy := effectiveRetryPolicy(h.retryPolicy, &spec.ModuleItem); policy != nil {
+					logger.Info("Loader failed and a retry policy is set; deleting the worker Pod")
+					podsToDelete = append(podsToDelete, p)
+					setWorkerStopped(status, policy, spec, p.Name)
+				}
+			}
+
+			// Unloader Pods are recreated by ProcessModuleSpec or ProcessUnconfiguredModuleStatus after a backoff.
+			if f.Action == kmmv1beta1.WorkerActionUnload && f.Reason != kmmv1beta1.WorkerFailureReasonModuleInUse {
+				if policy := effectiveRetryPolicy(h.retryPolicy, retryPolicyItem(spec, status)); policy != nil {
+					logger.Info("Unloader failed and a retry policy is set; deleting the worker Pod")
+					podsToDelete = append(podsToDelete, p)
+					setWorkerStopped(status, policy, spec, p.Name)
+				}
+			}
+
+			if f.Reason == kmmv1beta1.WorkerFailureReasonModuleInUse {
+				logger.Info("Module is in use and cannot be unloaded; deleting the worker Pod")
+				podsToDelete = append(podsToDelete, p)
+
+				if spec != nil {
+					nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+						Type:    kmmv1beta1.NodeModuleConditionRebootRequired,
+						Status:  metav1.ConditionTrue,
+						Reason:  string(kmmv1beta1.WorkerFailureReasonModuleInUse),
+						Message: "the new config will be applied once the module is released or the node is rebooted",
+					})
+				}
+			}
+
+			// Retrying would not help; ProcessModuleSpec reloads the module instead.
+			if f.Action == kmmv1beta1.WorkerActionSetParameters {
+				logger.Info("Could not set the module parameters at runtime; deleting the worker Pod")
+				podsToDelete = append(podsToDelete, p)
 			}
 		case v1.PodFailed:
+			if f := workerFailure(&p); f != nil {
+				f.Action = h.workerAction(&p)
+				setSpecHash(f, spec)
+				status = moduleStatusForPod(&nmcObj.Status.Modules, &p)
+				recordWorkerFailure(status, f)
+			}
+
+			if status != nil {
+				setWorkerStopped(status, effectiveRetryPolicy(h.retryPolicy, retryPolicyItem(spec, status)), spec, p.Name)
+			}
+
 			podsToDelete = append(podsToDelete, p)
 		case v1.PodSucceeded:
 			if h.podManager.IsUnloaderPod(&p) {
@@ -577,6 +1483,29 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 
 			status.Version = h.podManager.GetModuleVersionAnnotation(&p)
 
+			status.LastFailure = nil
+
+			if h.podManager.IsSetParametersPod(&p) {
+				setParametersResult(status, &p)
+				nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+					Type:   kmmv1beta1.NodeModuleConditionProgressing,
+					Status: metav1.ConditionFalse,
+					Reason: conditionReasonParametersSet,
+				})
+			} else {
+				setLoadResult(status, &p)
+				setLoaded(status)
+
+				// keep the drain policy, the maintenance windows, the dependencies and the retry policy, in case the
+				// module is unloaded after it was removed from the spec
+				if spec != nil {
+					status.DrainPolicy = spec.DrainPolicy
+					status.MaintenanceWindows = spec.MaintenanceWindows
+					status.DependsOn = spec.DependsOn
+					status.RetryPolicy = spec.RetryPolicy
+				}
+			}
+
 			nmc.SetModuleStatus(&nmcObj.Status.Modules, *status)
 
 			podsToDelete = append(podsToDelete, p)
@@ -602,6 +1531,281 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 	return errors.Join(errs...)
 }
 
+// Reasons of the NodeModuleStatus conditions that are not worker failure reasons.
+const (
+	conditionReasonLoaded                      = "Loaded"
+	conditionReasonLoading                     = "Loading"
+	conditionReasonParametersSet               = "ParametersSet"
+	conditionReasonRetryLimitReached           = "RetryLimitReached"
+	conditionReasonSettingParameters           = "SettingParameters"
+	conditionReasonUnloading                   = "Unloading"
+	conditionReasonUpToDate                    = "UpToDate"
+	conditionReasonWaitingForDependencies      = "WaitingForDependencies"
+	conditionReasonWaitingForDependents        = "WaitingForDependents"
+	conditionReasonWaitingForMaintenanceWindow = "WaitingForMaintenanceWindow"
+	conditionReasonWorkerFailed                = "WorkerFailed"
+)
+
+// workerAction returns what the worker Pod p is doing.
+func (h *nmcReconcilerHelperImpl) workerAction(p *v1.Pod) kmmv1beta1.WorkerAction {
+	if h.podManager.IsLoaderPod(p) {
+		return kmmv1beta1.WorkerActionLoad
+	}
+
+	if h.podManager.IsSetParametersPod(p) {
+		return kmmv1beta1.WorkerActionSetParameters
+	}
+
+	return kmmv1beta1.WorkerActionUnload
+}
+
+// moduleStatusForPod returns the status entry of the module that the worker Pod p works on.
+// An entry without Config is added for modules that were never loaded on the node.
+func moduleStatusForPod(statuses *[]kmmv1beta1.NodeModuleStatus, p *v1.Pod) *kmmv1beta1.NodeModuleStatus {
+	name := p.Labels[constants.ModuleNameLabel]
+
+	if s := nmc.FindModuleStatus(*statuses, p.Namespace, name); s != nil {
+		return s
+	}
+
+	*statuses = append(*statuses, kmmv1beta1.NodeModuleStatus{
+		ModuleItem: kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: p.Namespace,
+		},
+	})
+
+	return &(*statuses)[len(*statuses)-1]
+}
+
+// setProgressing sets the Progressing condition of status to True for a worker Pod doing action.
+func setProgressing(status *kmmv1beta1.NodeModuleStatus, action kmmv1beta1.WorkerAction) {
+	reason := conditionReasonUnloading
+
+	switch action {
+	case kmmv1beta1.WorkerActionLoad:
+		reason = conditionReasonLoading
+	case kmmv1beta1.WorkerActionSetParameters:
+		reason = conditionReasonSettingParameters
+	}
+
+	nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+		Type:   kmmv1beta1.NodeModuleConditionProgressing,
+		Status: metav1.ConditionTrue,
+		Reason: reason,
+	})
+
+	for _, t := range []kmmv1beta1.NodeModuleConditionType{
+		kmmv1beta1.NodeModuleConditionMaintenanceWindowPending,
+		kmmv1beta1.NodeModuleConditionDependenciesPending,
+	} {
+		if c := nmc.FindModuleCondition(status.Conditions, t); c != nil && c.Status == metav1.ConditionTrue {
+			nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+				Type:   t,
+				Status: metav1.ConditionFalse,
+				Reason: reason,
+			})
+		}
+	}
+}
+
+// setLoaded updates the conditions of status after a successful load.
+func setLoaded(status *kmmv1beta1.NodeModuleStatus) {
+	for _, c := range []kmmv1beta1.NodeModuleCondition{
+		{Type: kmmv1beta1.NodeModuleConditionProgressing, Status: metav1.ConditionFalse, Reason: conditionReasonLoaded},
+		{
+			Type:    kmmv1beta1.NodeModuleConditionLoaded,
+			Status:  metav1.ConditionTrue,
+			Reason:  conditionReasonLoaded,
+			Message: fmt.Sprintf("loaded on kernel %s", status.Config.KernelVersion),
+		},
+		{Type: kmmv1beta1.NodeModuleConditionLoadFailed, Status: metav1.ConditionFalse, Reason: conditionReasonLoaded},
+	} {
+		nmc.SetModuleCondition(status, c)
+	}
+
+	if nmc.FindModuleCondition(status.Conditions, kmmv1beta1.NodeModuleConditionRebootRequired) != nil {
+		nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+			Type:   kmmv1beta1.NodeModuleConditionRebootRequired,
+			Status: metav1.ConditionFalse,
+			Reason: conditionReasonLoaded,
+		})
+	}
+}
+
+// setWorkerStopped sets the Progressing condition of status to False after the worker Pod podName failed.
+// The reason tells if KMM stopped retrying because of policy.
+func setWorkerStopped(
+	status *kmmv1beta1.NodeModuleStatus,
+	policy *config.RetryPolicy,
+	spec *kmmv1beta1.NodeModuleSpec,
+	podName string,
+) {
+	c := kmmv1beta1.NodeModuleCondition{
+		Type:    kmmv1beta1.NodeModuleConditionProgressing,
+		Status:  metav1.ConditionFalse,
+		Reason:  conditionReasonWorkerFailed,
+		Message: fmt.Sprintf("worker Pod %s failed", podName),
+	}
+
+	if f := status.LastFailure; f != nil && f.Action == kmmv1beta1.WorkerActionUnload {
+		if _, exhausted := unloadRetryDelay(policy, spec, status); exhausted {
+			c.Reason = conditionReasonRetryLimitReached
+			c.Message = fmt.Sprintf("the module failed to unload %d times; reboot the node to retry", policy.MaxAttempts)
+
+			if spec != nil {
+				c.Message = fmt.Sprintf(
+					"the module failed to unload %d times; change the Module or its %s annotation to retry",
+					policy.MaxAttempts,
+					constants.RetryAnnotation,
+				)
+			}
+		}
+	} else if spec != nil {
+		if _, exhausted := loadRetryDelay(policy, spec, status); exhausted {
+			c.Reason = conditionReasonRetryLimitReached
+			c.Message = fmt.Sprintf(
+				"the module failed to load %d times; change the Module or its %s annotation to retry",
+				policy.MaxAttempts,
+				constants.RetryAnnotation,
+			)
+		}
+	}
+
+	nmc.SetModuleCondition(status, c)
+}
+
+// setSpecHash identifies in failure the spec that a failed loader or unloader Pod was applying.
+func setSpecHash(failure *kmmv1beta1.WorkerFailure, spec *kmmv1beta1.NodeModuleSpec) {
+	if (failure.Action == kmmv1beta1.WorkerActionLoad || failure.Action == kmmv1beta1.WorkerActionUnload) && spec != nil {
+		failure.SpecHash = specHash(spec)
+	}
+}
+
+// recordWorkerFailure sets the LastFailure field of status and, for load and unload failures, the corresponding
+// condition.
+// Nothing changes if failure was already recorded, so that the failure count is only incremented once per failure.
+// The count restarts from 1 if the spec that the worker was applying changed.
+func recordWorkerFailure(status *kmmv1beta1.NodeModuleStatus, failure *kmmv1beta1.WorkerFailure) {
+	if reflect.DeepEqual(status.LastFailure, failure) {
+		return
+	}
+
+	previous := status.LastFailure
+	status.LastFailure = failure
+
+	var conditionType kmmv1beta1.NodeModuleConditionType
+
+	switch failure.Action {
+	case kmmv1beta1.WorkerActionLoad:
+		conditionType = kmmv1beta1.NodeModuleConditionLoadFailed
+	case kmmv1beta1.WorkerActionUnload:
+		conditionType = kmmv1beta1.NodeModuleConditionUnloadFailed
+	default:
+		return
+	}
+
+	var count int32 = 1
+
+	c := nmc.FindModuleCondition(status.Conditions, conditionType)
+	if c != nil && c.Status == metav1.ConditionTrue && previous != nil && previous.SpecHash == failure.SpecHash {
+		count = c.FailureCount + 1
+	}
+
+	nmc.SetModuleCondition(status, kmmv1beta1.NodeModuleCondition{
+		Type:         conditionType,
+		Status:       metav1.ConditionTrue,
+		Reason:       string(failure.Reason),
+		Message:      failure.Message,
+		FailureCount: count,
+	})
+}
+
+// workerFailure returns information about the last failed run of the worker container in p, or nil if it has not
+// failed.
+func workerFailure(p *v1.Pod) *kmmv1beta1.WorkerFailure {
+	cs := GetContainerStatus(p.Status.ContainerStatuses, pod.WorkerContainerName)
+
+	terminated := cs.State.Terminated
+	if terminated == nil || terminated.ExitCode == 0 {
+		terminated = cs.LastTerminationState.Terminated
+	}
+
+	if terminated == nil || terminated.ExitCode == 0 {
+		return nil
+	}
+
+	failure := kmmv1beta1.WorkerFailure{
+		Action:       kmmv1beta1.WorkerActionUnload,
+		Reason:       kmmv1beta1.WorkerFailureReasonError,
+		ExitCode:     terminated.ExitCode,
+		Message:      terminated.Message,
+		RestartCount: cs.RestartCount,
+		Time:         terminated.FinishedAt,
+	}
+
+	// Older workers and workers that crashed do not write a result; the message then contains their logs.
+	if res, err := worker.ParseResult(terminated.Message); err == nil && res.Error != nil {
+		failure.Reason = res.Error.Reason
+		failure.Message = res.Error.Message
+	} else if terminated.ExitCode == worker.ExitCodeModuleBusy {
+		failure.Reason = kmmv1beta1.WorkerFailureReasonModuleInUse
+	}
+
+	return &failure
+}
+
+// setLoadResult copies the result written by the worker container of a successful loader Pod into status.
+// Those fields are cleared if the worker did not write a result.
+func setLoadResult(status *kmmv1beta1.NodeModuleStatus, p *v1.Pod) {
+	status.LoadedModules = nil
+	status.Parameters = nil
+	status.FirmwareFiles = nil
+	status.BlacklistedModules = nil
+	status.Livepatch = nil
+	status.Taint = nil
+	status.LoadDuration = nil
+
+	res := workerResult(p)
+	if res == nil {
+		return
+	}
+
+	status.LoadedModules = res.Modules
+	status.Parameters = res.Parameters
+	status.FirmwareFiles = res.FirmwareFiles
+	status.BlacklistedModules = res.BlacklistedModules
+	status.Livepatch = res.Livepatch
+	status.Taint = res.Taint
+	status.LoadDuration = &res.Duration
+}
+
+// setParametersResult copies the parameters applied by the worker container of a successful set-parameters Pod into
+// status.
+// The other fields describe the last load and are left unchanged.
+func setParametersResult(status *kmmv1beta1.NodeModuleStatus, p *v1.Pod) {
+	status.Parameters = nil
+
+	if res := workerResult(p); res != nil {
+		status.Parameters = res.Parameters
+	}
+}
+
+// workerResult returns the result written by the worker container of a successful Pod, or nil if there is none.
+func workerResult(p *v1.Pod) *worker.Result {
+	terminated := GetContainerStatus(p.Status.ContainerStatuses, pod.WorkerContainerName).State.Terminated
+	if terminated == nil {
+		return nil
+	}
+
+	res, err := worker.ParseResult(terminated.Message)
+	if err != nil {
+		return nil
+	}
+
+	return res
+}
+
 func (h *nmcReconcilerHelperImpl) UpdateNodeLabels(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, node *v1.Node) ([]types.NamespacedName, []types.NamespacedName, error) {
 
 	// get all the kernel module ready labels of the node
@@ -5,16 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/node"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/pod"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	testclient "github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/drain"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/worker"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -166,6 +171,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 			),
 			wh.EXPECT().SyncStatus(ctx, nmc, &node),
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(false),
+			wh.EXPECT().CompleteDrain(ctx, nmc, &node),
 			nm.EXPECT().UpdateLabels(ctx, &node, nil, map[string]string{kmodReadyLabel: "", kmodVersionReadyLabel: ""}).DoAndReturn(
 				func(_ context.Context, obj ctrlclient.Object, _, _ map[string]string) error {
 					delete(node.ObjectMeta.Labels, kmodReadyLabel)
@@ -224,6 +230,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 			),
 			wh.EXPECT().SyncStatus(ctx, nmc, &node),
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(false),
+			wh.EXPECT().CompleteDrain(ctx, nmc, &node),
 			nm.EXPECT().UpdateLabels(ctx, &node, nil, map[string]string{kmodReadyLabel: "", kmodVersionReadyLabel: ""}).DoAndReturn(
 				func(_ context.Context, obj ctrlclient.Object, _, _ map[string]string) error {
 					return fmt.Errorf("some error")
@@ -304,6 +311,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(true),
 			wh.EXPECT().ProcessModuleSpec(contextWithValueMatch, nmc, &spec1, nil, &node),
 			wh.EXPECT().ProcessUnconfiguredModuleStatus(contextWithValueMatch, nmc, &status2, &node),
+			wh.EXPECT().CompleteDrain(ctx, nmc, &node),
 			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
 			wh.EXPECT().GarbageCollectWorkerPods(ctx, nmc),
 			wh.EXPECT().UpdateNodeLabels(ctx, nmc, &node).Return(loaded, unloaded, err),
@@ -332,6 +340,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 		expectedErrors := []error{
 			fmt.Errorf("error processing Module %s: %v", namespace+"/"+mod0Name, errorMeassge),
 			fmt.Errorf("error processing orphan status for Module %s: %v", namespace+"/"+mod2Name, errorMeassge),
+			fmt.Errorf("could not complete the drain of node %s: %v", "", errorMeassge),
 			fmt.Errorf("failed to GC in-use labels for NMC %s: %v", types.NamespacedName{Name: nmcName}, errorMeassge),
 			fmt.Errorf("failed to GC orphan worker pods for NMC %s: %v", types.NamespacedName{Name: nmcName}, errorMeassge),
 			fmt.Errorf("could not update node's labels for NMC %s: %v", types.NamespacedName{Name: nmcName}, errorMeassge),
@@ -384,6 +393,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(true),
 			wh.EXPECT().ProcessModuleSpec(contextWithValueMatch, nmc, &spec0, &status0, &node).Return(errors.New(errorMeassge)),
 			wh.EXPECT().ProcessUnconfiguredModuleStatus(contextWithValueMatch, nmc, &status2, &node).Return(errors.New(errorMeassge)),
+			wh.EXPECT().CompleteDrain(ctx, nmc, &node).Return(errors.New(errorMeassge)),
 			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc).Return(errors.New(errorMeassge)),
 			wh.EXPECT().GarbageCollectWorkerPods(ctx, nmc).Return(errors.New(errorMeassge)),
 			wh.EXPECT().UpdateNodeLabels(ctx, nmc, &node).Return(nil, nil, errors.New(errorMeassge)),
@@ -406,7 +416,7 @@ var _ = Describe("nmcReconcilerHelperImpl_GarbageCollectWorkerPods", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		client = testclient.NewMockClient(ctrl)
 		pm = pod.NewMockWorkerPodManager(ctrl)
-		nrh = newNMCReconcilerHelper(client, pm, nil, nil)
+		nrh = newNMCReconcilerHelper(client, pm, nil, nil, nil, nil, nil)
 	})
 
 	It("should delete orphaned worker pod", func() {
@@ -492,7 +502,7 @@ var _ = Describe("nmcReconcilerHelperImpl_GarbageCollectInUseLabels", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		client = testclient.NewMockClient(ctrl)
 		mockWorkerPodManager = pod.NewMockWorkerPodManager(ctrl)
-		wh = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nil)
+		wh = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nil, nil, nil, nil)
 	})
 
 	It("should do nothing if no labels should be collected", func() {
@@ -600,6 +610,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		podName = pod.WorkerPodName(nmcName, name)
 
 		client               *testclient.MockClient
+		sw                   *testclient.MockStatusWriter
 		mockWorkerPodManager *pod.MockWorkerPodManager
 		wh                   nmcReconcilerHelper
 		nm                   *node.MockNode
@@ -624,9 +635,10 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 	BeforeEach(func() {
 		ctrl := gomock.NewController(GinkgoT())
 		client = testclient.NewMockClient(ctrl)
+		sw = testclient.NewMockStatusWriter(ctrl)
 		mockWorkerPodManager = pod.NewMockWorkerPodManager(ctrl)
 		nm = node.NewMockNode(ctrl)
-		wh = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nm)
+		wh = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nm, nil, nil, nil)
 	})
 
 	It("should create a loader Pod if there is no existing Pod and the status is missing", func() {
@@ -676,6 +688,8 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		gomock.InOrder(
 			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
 			mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
 		)
 
 		Expect(
@@ -685,6 +699,95 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		)
 	})
 
+	Context("only the module parameters changed", func() {
+		var (
+			nmc    *kmmv1beta1.NodeModulesConfig
+			spec   *kmmv1beta1.NodeModuleSpec
+			status *kmmv1beta1.NodeModuleStatus
+		)
+
+		BeforeEach(func() {
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			}
+
+			spec = &kmmv1beta1.NodeModuleSpec{
+				ModuleItem: kmmv1beta1.ModuleItem{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Config: *moduleConfig.DeepCopy(),
+			}
+
+			spec.Config.Modprobe.Parameters = []string{"a=2", "b"}
+
+			status = &kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Config: *moduleConfig.DeepCopy(),
+			}
+		})
+
+		It("should create a set-parameters Pod", func() {
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateSetParametersPod(ctx, nmc, spec),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(status.Conditions).To(HaveLen(1))
+			Expect(status.Conditions[0].Type).To(Equal(kmmv1beta1.NodeModuleConditionProgressing))
+			Expect(status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(status.Conditions[0].Reason).To(Equal(conditionReasonSettingParameters))
+		})
+
+		It("should create an unloader Pod if a parameter was removed", func() {
+			spec.Config.Modprobe.Parameters = []string{"a=2"}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create an unloader Pod if setting the parameters failed", func() {
+			status.LastFailure = &kmmv1beta1.WorkerFailure{
+				Action: kmmv1beta1.WorkerActionSetParameters,
+				Reason: kmmv1beta1.WorkerFailureReasonParameterNotWritable,
+			}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+
 	It("should create an loader Pod if the spec is different from the status and kernels different equal", func() {
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
@@ -709,6 +812,8 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		gomock.InOrder(
 			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
 			mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
 		)
 
 		Expect(
@@ -754,7 +859,11 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 			)
 
 			if shouldCreate {
-				mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec)
+				gomock.InOrder(
+					mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+					client.EXPECT().Status().Return(sw),
+					sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				)
 			}
 
 			Expect(
@@ -848,275 +957,2095 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 			HaveOccurred(),
 		)
 	})
-})
 
-var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func() {
-	const name = "name"
+	Context("with a retry policy", func() {
+		var (
+			nmc    *kmmv1beta1.NodeModulesConfig
+			spec   *kmmv1beta1.NodeModuleSpec
+			status *kmmv1beta1.NodeModuleStatus
+		)
 
-	var (
-		ctx     = context.TODO()
-		podName = pod.WorkerPodName(nmcName, name)
+		BeforeEach(func() {
+			wh = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nm, nil, &config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute}, nil)
 
-		client *testclient.MockClient
-		sw     *testclient.MockStatusWriter
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			}
 
-		mockWorkerPodManager *pod.MockWorkerPodManager
-		nm                   *node.MockNode
-		helper               nmcReconcilerHelper
-	)
+			spec = &kmmv1beta1.NodeModuleSpec{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+				Config:     moduleConfig,
+			}
 
-	BeforeEach(func() {
-		ctrl := gomock.NewController(GinkgoT())
-		client = testclient.NewMockClient(ctrl)
-		sw = testclient.NewMockStatusWriter(ctrl)
-		mockWorkerPodManager = pod.NewMockWorkerPodManager(ctrl)
-		nm = node.NewMockNode(ctrl)
-		helper = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nm)
-	})
+			status = &kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+				LastFailure: &kmmv1beta1.WorkerFailure{
+					Action:   kmmv1beta1.WorkerActionLoad,
+					Time:     metav1.Now(),
+					SpecHash: specHash(spec),
+				},
+				Conditions: []kmmv1beta1.NodeModuleCondition{
+					{
+						Type:         kmmv1beta1.NodeModuleConditionLoadFailed,
+						Status:       metav1.ConditionTrue,
+						FailureCount: 1,
+					},
+				},
+			}
+		})
 
-	nmc := &kmmv1beta1.NodeModulesConfig{
-		ObjectMeta: metav1.ObjectMeta{Name: nmcName},
-	}
+		It("should not create a loader Pod during the backoff", func() {
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace)
 
-	status := &kmmv1beta1.NodeModuleStatus{
-		ModuleItem: kmmv1beta1.ModuleItem{
-			Name:      name,
-			Namespace: namespace,
-		},
-	}
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
 
-	node := v1.Node{}
+		It("should not create a loader Pod after the maximum number of attempts", func() {
+			status.LastFailure.Time = metav1.NewTime(time.Now().Add(-time.Hour))
+			status.Conditions[0].FailureCount = 3
 
-	It("should do nothing , if the node has been rebooted/ready lately", func() {
-		gomock.InOrder(
-			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(true),
-			client.EXPECT().Status().Return(sw),
-			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
-		)
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace)
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
 
-	It("should create an unloader Pod if no worker Pod exists", func() {
-		gomock.InOrder(
-			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
-			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
-			mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, status),
-		)
+		It("should not recreate a loader Pod that keeps failing after a reboot during the backoff", func() {
+			node := &v1.Node{}
+			status.Config = spec.Config
+			status.BootId = "previous-boot"
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(node, status.BootId).Return(true),
+			)
 
-	It("should delete the current worker if it is loading a module", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      podName,
-				Namespace: namespace,
-			},
-		}
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
 
-		gomock.InOrder(
-			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
-			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
-			mockWorkerPodManager.EXPECT().IsLoaderPod(&pod).Return(true),
-			mockWorkerPodManager.EXPECT().DeletePod(ctx, &pod),
-		)
+		It("should recreate a loader Pod that failed after a reboot once the backoff elapsed", func() {
+			node := &v1.Node{}
+			status.Config = spec.Config
+			status.BootId = "previous-boot"
+			status.LastFailure.Time = metav1.NewTime(time.Now().Add(-time.Hour))
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(node, status.BootId).Return(true),
+				mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
 
-	It("should do nothing if the pod has not restarted yet", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      podName,
-				Namespace: namespace,
-			},
-		}
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
 
-		gomock.InOrder(
-			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
-			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
-			mockWorkerPodManager.EXPECT().IsLoaderPod(&pod).Return(false),
-		)
+		It("should create a loader Pod once the retry token changed", func() {
+			status.Conditions[0].FailureCount = 3
+			spec.RetryToken = "retry"
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
 
-	It("should return an error if there was an error while making the unloader Pod", func() {
-		pod := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      podName,
-				Namespace: namespace,
-			},
-			Status: v1.PodStatus{
-				ContainerStatuses: []v1.ContainerStatus{
-					{
-						Name:         pod.WorkerContainerName,
-						RestartCount: 1,
-					},
-				},
-			},
-		}
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
 
-		gomock.InOrder(
-			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
-			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
-			mockWorkerPodManager.EXPECT().IsLoaderPod(&pod).Return(false),
-			mockWorkerPodManager.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(nil, errors.New("random error")),
+	Context("with maintenance windows", func() {
+		var (
+			nmc    *kmmv1beta1.NodeModulesConfig
+			spec   *kmmv1beta1.NodeModuleSpec
+			status *kmmv1beta1.NodeModuleStatus
 		)
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
-		).To(
-			HaveOccurred(),
-		)
-	})
+		BeforeEach(func() {
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			}
 
-	It("should delete the existing pod if its hash annotation is outdated", func() {
-		p := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      podName,
-				Namespace: namespace,
-			},
-			Status: v1.PodStatus{
-				ContainerStatuses: []v1.ContainerStatus{
-					{
-						Name:         pod.WorkerContainerName,
-						RestartCount: 1,
-					},
+			spec = &kmmv1beta1.NodeModuleSpec{
+				ModuleItem: kmmv1beta1.ModuleItem{
+					Name:               name,
+					Namespace:          namespace,
+					MaintenanceWindows: closedMaintenanceWindows(),
 				},
-			},
-		}
+				Config: kmmv1beta1.ModuleConfig{ContainerImage: "new-container-image", KernelVersion: "same kernel"},
+			}
 
-		podTemplate := p.DeepCopy()
+			status = &kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+				Config:     kmmv1beta1.ModuleConfig{ContainerImage: "old-container-image", KernelVersion: "same kernel"},
+			}
+		})
 
-		gomock.InOrder(
-			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
-			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&p, nil),
-			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(false),
-			mockWorkerPodManager.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(podTemplate, nil),
-			mockWorkerPodManager.EXPECT().HashAnnotationDiffer(gomock.Any(), gomock.Any()).Return(true),
-			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
-		)
+		It("should hold back the unloader Pod until a window opens", func() {
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
 
-		Expect(
-			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
 
-})
+			Expect(status.Conditions).To(HaveLen(1))
+			Expect(status.Conditions[0].Type).To(Equal(kmmv1beta1.NodeModuleConditionMaintenanceWindowPending))
+			Expect(status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(status.Conditions[0].Reason).To(Equal(conditionReasonWaitingForMaintenanceWindow))
+		})
 
-var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
-	var (
-		ctx = context.TODO()
+		It("should not patch the status again while the change is held back", func() {
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Times(2)
+			client.EXPECT().Status().Return(sw)
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any())
 
-		ctrl                 *gomock.Controller
-		kubeClient           *testclient.MockClient
-		mockWorkerPodManager *pod.MockWorkerPodManager
-		sw                   *testclient.MockStatusWriter
-		wh                   nmcReconcilerHelper
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should hold back set-parameters Pods", func() {
+			spec.Config = *status.Config.DeepCopy()
+			spec.Config.Modprobe.Parameters = []string{"a=1"}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should use the default windows if the module has none", func() {
+			wh = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nm, nil, nil, []config.MaintenanceWindow{
+				{Schedule: "* * * * *", Duration: time.Minute},
+			})
+			spec.MaintenanceWindows = nil
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create the unloader Pod once a window is open", func() {
+			spec.MaintenanceWindows = []kmmv1beta1.MaintenanceWindow{
+				{Schedule: "* * * * *", Duration: metav1.Duration{Duration: time.Minute}},
+			}
+			status.Conditions = []kmmv1beta1.NodeModuleCondition{
+				{Type: kmmv1beta1.NodeModuleConditionMaintenanceWindowPending, Status: metav1.ConditionTrue},
+			}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(maintenanceWindowPending(status)).To(BeFalse())
+		})
+
+		It("should not hold back loads after a kernel upgrade", func() {
+			spec.Config.KernelVersion = "new kernel"
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should clear the pending condition if the change was reverted", func() {
+			spec.Config = status.Config
+			status.Conditions = []kmmv1beta1.NodeModuleCondition{
+				{Type: kmmv1beta1.NodeModuleConditionMaintenanceWindowPending, Status: metav1.ConditionTrue},
+			}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(nil, status.BootId).Return(false),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(maintenanceWindowPending(status)).To(BeFalse())
+		})
+
+		It("should return an error if a window is invalid", func() {
+			spec.MaintenanceWindows[0].Schedule = "invalid"
+
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).To(
+				HaveOccurred(),
+			)
+		})
+	})
+
+	Context("with dependencies", func() {
+		const bootID = "boot-id"
+
+		var (
+			nmc  *kmmv1beta1.NodeModulesConfig
+			spec *kmmv1beta1.NodeModuleSpec
+		)
+
+		coreStatus := func() kmmv1beta1.NodeModuleStatus {
+			return kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: "core", Namespace: namespace},
+				Config:     kmmv1beta1.ModuleConfig{KernelVersion: "same kernel"},
+				BootId:     bootID,
+				Conditions: []kmmv1beta1.NodeModuleCondition{
+					{Type: kmmv1beta1.NodeModuleConditionLoaded, Status: metav1.ConditionTrue},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			nmc = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			}
+
+			spec = &kmmv1beta1.NodeModuleSpec{
+				ModuleItem: kmmv1beta1.ModuleItem{
+					Name:      name,
+					Namespace: namespace,
+					DependsOn: []kmmv1beta1.ModuleReference{{Name: "core", Namespace: namespace}},
+				},
+				Config: kmmv1beta1.ModuleConfig{KernelVersion: "same kernel"},
+			}
+		})
+
+		It("should hold back the loader Pod until the dependencies are loaded", func() {
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, nil, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules).To(HaveLen(1))
+
+			status := nmc.Status.Modules[0]
+			Expect(status.Name).To(Equal(name))
+			Expect(status.Config).To(BeZero())
+			Expect(status.Conditions).To(HaveLen(1))
+			Expect(status.Conditions[0].Type).To(Equal(kmmv1beta1.NodeModuleConditionDependenciesPending))
+			Expect(status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(status.Conditions[0].Reason).To(Equal(conditionReasonWaitingForDependencies))
+			Expect(status.Conditions[0].Message).To(Equal("waiting for namespace/core to be loaded"))
+		})
+
+		It("should not patch the status again while the load is held back", func() {
+			status := kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+				Conditions: []kmmv1beta1.NodeModuleCondition{
+					{
+						Type:    kmmv1beta1.NodeModuleConditionDependenciesPending,
+						Status:  metav1.ConditionTrue,
+						Reason:  conditionReasonWaitingForDependencies,
+						Message: "waiting for namespace/core to be loaded",
+					},
+				},
+			}
+
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{status}
+
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, &nmc.Status.Modules[0], nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should hold back the loader Pod while a dependency is being reloaded", func() {
+			core := coreStatus()
+			core.Conditions = append(
+				core.Conditions,
+				kmmv1beta1.NodeModuleCondition{Type: kmmv1beta1.NodeModuleConditionProgressing, Status: metav1.ConditionTrue},
+			)
+
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{core}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(nil, bootID).Return(false),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, nil, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should hold back the loader Pod if a dependency is loaded with an outdated config", func() {
+			nmc.Spec.Modules = []kmmv1beta1.NodeModuleSpec{
+				{
+					ModuleItem: kmmv1beta1.ModuleItem{Name: "core", Namespace: namespace},
+					Config:     kmmv1beta1.ModuleConfig{KernelVersion: "new kernel"},
+				},
+			}
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{coreStatus()}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(nil, bootID).Return(false),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, nil, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should hold back the loader Pod after a reboot until the dependencies are loaded again", func() {
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{coreStatus()}
+
+			status := &kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+				Config:     spec.Config,
+				BootId:     bootID,
+			}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(nil, bootID).Return(true),
+				nm.EXPECT().IsNodeRebooted(nil, bootID).Return(true),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create the loader Pod once the dependencies are loaded", func() {
+			status := kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+				Conditions: []kmmv1beta1.NodeModuleCondition{
+					{Type: kmmv1beta1.NodeModuleConditionDependenciesPending, Status: metav1.ConditionTrue},
+				},
+			}
+
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{coreStatus(), status}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(nil, bootID).Return(false),
+				mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, &nmc.Status.Modules[1], nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules[1].Conditions).To(
+				ContainElement(
+					And(
+						HaveField("Type", kmmv1beta1.NodeModuleConditionDependenciesPending),
+						HaveField("Status", metav1.ConditionFalse),
+					),
+				),
+			)
+		})
+
+		It("should create the loader Pod if a dependency loaded by an older KMM has the config of its spec", func() {
+			core := coreStatus()
+			core.Conditions = nil
+
+			nmc.Spec.Modules = []kmmv1beta1.NodeModuleSpec{
+				{
+					ModuleItem: kmmv1beta1.ModuleItem{Name: "core", Namespace: namespace},
+					Config:     core.Config,
+				},
+			}
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{core}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(nil, bootID).Return(false),
+				mockWorkerPodManager.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, nil, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should hold back the loader Pod if a dependency without the Loaded condition has no spec", func() {
+			core := coreStatus()
+			core.Conditions = nil
+
+			nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{core}
+
+			gomock.InOrder(
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(nil, bootID).Return(false),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, nil, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		Context("unloading a module that others depend on", func() {
+			var (
+				coreSpec *kmmv1beta1.NodeModuleSpec
+				addon    kmmv1beta1.NodeModuleStatus
+			)
+
+			BeforeEach(func() {
+				coreSpec = &kmmv1beta1.NodeModuleSpec{
+					ModuleItem: kmmv1beta1.ModuleItem{Name: "core", Namespace: namespace},
+					Config:     kmmv1beta1.ModuleConfig{ContainerImage: "new-container-image", KernelVersion: "same kernel"},
+				}
+
+				addon = kmmv1beta1.NodeModuleStatus{
+					ModuleItem: kmmv1beta1.ModuleItem{
+						Name:      name,
+						Namespace: namespace,
+						DependsOn: []kmmv1beta1.ModuleReference{{Name: "core", Namespace: namespace}},
+					},
+					Config: kmmv1beta1.ModuleConfig{KernelVersion: "same kernel"},
+					BootId: bootID,
+				}
+
+				nmc.Spec.Modules = []kmmv1beta1.NodeModuleSpec{*coreSpec}
+				nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{coreStatus(), addon}
+			})
+
+			corePodName := pod.WorkerPodName(nmcName, "core")
+
+			It("should hold back the unloader Pod until the dependent modules are unloaded", func() {
+				gomock.InOrder(
+					mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, corePodName, namespace),
+					nm.EXPECT().IsNodeRebooted(nil, bootID).Return(false),
+					client.EXPECT().Status().Return(sw),
+					sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				)
+
+				Expect(
+					wh.ProcessModuleSpec(ctx, nmc, coreSpec, &nmc.Status.Modules[0], nil),
+				).NotTo(
+					HaveOccurred(),
+				)
+
+				Expect(nmc.Status.Modules[0].Conditions).To(
+					ContainElement(
+						And(
+							HaveField("Type", kmmv1beta1.NodeModuleConditionDependenciesPending),
+							HaveField("Reason", conditionReasonWaitingForDependents),
+							HaveField("Message", "waiting for namespace/name to be unloaded"),
+						),
+					),
+				)
+			})
+
+			It("should not wait for dependent modules that stay loaded", func() {
+				nmc.Spec.Modules = append(nmc.Spec.Modules, kmmv1beta1.NodeModuleSpec{
+					ModuleItem: addon.ModuleItem,
+					Config:     addon.Config,
+				})
+
+				gomock.InOrder(
+					mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, corePodName, namespace),
+					mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, &nmc.Status.Modules[0]),
+					client.EXPECT().Status().Return(sw),
+					sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				)
+
+				Expect(
+					wh.ProcessModuleSpec(ctx, nmc, coreSpec, &nmc.Status.Modules[0], nil),
+				).NotTo(
+					HaveOccurred(),
+				)
+			})
+		})
+	})
+})
+
+var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func() {
+	const name = "name"
+
+	var (
+		ctx     = context.TODO()
+		podName = pod.WorkerPodName(nmcName, name)
+
+		client *testclient.MockClient
+		sw     *testclient.MockStatusWriter
+
+		mockWorkerPodManager *pod.MockWorkerPodManager
+		nm                   *node.MockNode
+		helper               nmcReconcilerHelper
 	)
 
 	BeforeEach(func() {
-		ctrl = gomock.NewController(GinkgoT())
-		kubeClient = testclient.NewMockClient(ctrl)
-		mockWorkerPodManager = pod.NewMockWorkerPodManager(ctrl)
-		wh = newNMCReconcilerHelper(kubeClient, mockWorkerPodManager, nil, nil)
+		ctrl := gomock.NewController(GinkgoT())
+		client = testclient.NewMockClient(ctrl)
 		sw = testclient.NewMockStatusWriter(ctrl)
+		mockWorkerPodManager = pod.NewMockWorkerPodManager(ctrl)
+		nm = node.NewMockNode(ctrl)
+		helper = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nm, nil, nil, nil)
 	})
 
-	const (
-		podName      = "pod-name"
-		podNamespace = "pod-namespace"
-	)
+	nmc := &kmmv1beta1.NodeModulesConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+	}
+
+	status := &kmmv1beta1.NodeModuleStatus{
+		ModuleItem: kmmv1beta1.ModuleItem{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Config: kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+	}
+
+	node := v1.Node{}
+
+	It("should do nothing , if the node has been rebooted/ready lately", func() {
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(true),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should delete the status if the module was never loaded", func() {
+		failedStatus := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem:  status.ModuleItem,
+			LastFailure: &kmmv1beta1.WorkerFailure{Action: kmmv1beta1.WorkerActionLoad, ExitCode: 1},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{*failedStatus},
+			},
+		}
+
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, failedStatus.BootId).Return(false),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, failedStatus, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(BeEmpty())
+	})
+
+	It("should not create an unloader Pod if the module was recently in use", func() {
+		blockedStatus := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: status.ModuleItem,
+			Config:     kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+			LastFailure: &kmmv1beta1.WorkerFailure{
+				Action:   kmmv1beta1.WorkerActionUnload,
+				Reason:   kmmv1beta1.WorkerFailureReasonModuleInUse,
+				ExitCode: worker.ExitCodeModuleBusy,
+				Time:     metav1.Now(),
+			},
+		}
+
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, blockedStatus.BootId).Return(false),
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, blockedStatus, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should retry the unload once the retry period has elapsed", func() {
+		blockedStatus := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: status.ModuleItem,
+			Config:     kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+			LastFailure: &kmmv1beta1.WorkerFailure{
+				Action:   kmmv1beta1.WorkerActionUnload,
+				Reason:   kmmv1beta1.WorkerFailureReasonModuleInUse,
+				ExitCode: worker.ExitCodeModuleBusy,
+				Time:     metav1.NewTime(time.Now().Add(-unloadBlockedRetryPeriod)),
+			},
+		}
+
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, blockedStatus.BootId).Return(false),
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, blockedStatus),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, blockedStatus, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	Context("with a retry policy", func() {
+		failedStatus := func(failedAgo time.Duration, count int32) *kmmv1beta1.NodeModuleStatus {
+			return &kmmv1beta1.NodeModuleStatus{
+				ModuleItem: kmmv1beta1.ModuleItem{
+					Name:        name,
+					Namespace:   namespace,
+					RetryPolicy: &kmmv1beta1.RetryPolicy{MaxAttempts: 3, InitialBackoff: &metav1.Duration{Duration: time.Minute}},
+				},
+				Config: kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+				LastFailure: &kmmv1beta1.WorkerFailure{
+					Action:   kmmv1beta1.WorkerActionUnload,
+					Reason:   kmmv1beta1.WorkerFailureReasonError,
+					ExitCode: 1,
+					Time:     metav1.NewTime(time.Now().Add(-failedAgo)),
+				},
+				Conditions: []kmmv1beta1.NodeModuleCondition{
+					{Type: kmmv1beta1.NodeModuleConditionUnloadFailed, Status: metav1.ConditionTrue, FailureCount: count},
+				},
+			}
+		}
+
+		It("should not create an unloader Pod during the backoff of a failed unload", func() {
+			failed := failedStatus(0, 1)
+
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, failed.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmc, failed, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create an unloader Pod once the backoff has elapsed", func() {
+			failed := failedStatus(time.Hour, 1)
+
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, failed.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, failed),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmc, failed, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should stop retrying after the maximum number of attempts", func() {
+			failed := failedStatus(time.Hour, 3)
+
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, failed.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmc, failed, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+
+	It("should create an unloader Pod if no worker Pod exists", func() {
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmc, status),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should delete the current worker if it is loading a module", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: namespace,
+			},
+		}
+
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&pod).Return(true),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &pod),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should do nothing if the pod has not restarted yet", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: namespace,
+			},
+		}
+
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&pod).Return(false),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&pod).Return(false),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if there was an error while making the unloader Pod", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: namespace,
+			},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         pod.WorkerContainerName,
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&pod).Return(false),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&pod).Return(false),
+			mockWorkerPodManager.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(nil, errors.New("random error")),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should delete the existing pod if its hash annotation is outdated", func() {
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: namespace,
+			},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         pod.WorkerContainerName,
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		podTemplate := p.DeepCopy()
+
+		gomock.InOrder(
+			nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&p, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(false),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&p).Return(false),
+			mockWorkerPodManager.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(podTemplate, nil),
+			mockWorkerPodManager.EXPECT().HashAnnotationDiffer(gomock.Any(), gomock.Any()).Return(true),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	Context("with a drain policy", func() {
+		var (
+			drainer     *drain.MockDrainer
+			drainStatus *kmmv1beta1.NodeModuleStatus
+			nmcObj      *kmmv1beta1.NodeModulesConfig
+		)
+
+		BeforeEach(func() {
+			drainer = drain.NewMockDrainer(gomock.NewController(GinkgoT()))
+			helper = newNMCReconcilerHelper(client, mockWorkerPodManager, nil, nm, drainer, nil, nil)
+
+			drainStatus = status.DeepCopy()
+			drainStatus.DrainPolicy = &kmmv1beta1.DrainPolicy{Resources: []v1.ResourceName{"example.com/device"}}
+
+			nmcObj = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{*drainStatus},
+				},
+			}
+		})
+
+		It("should cordon the node and wait for Pods to be evicted", func() {
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, drainStatus.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				drainer.EXPECT().Cordon(ctx, &node).Return(true, nil),
+				drainer.EXPECT().EvictPods(ctx, node.Name, drainStatus.DrainPolicy).Return(2, nil),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmcObj, drainStatus, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			d := nmcObj.Status.Drain
+			Expect(d).NotTo(BeNil())
+			Expect(d.Phase).To(Equal(kmmv1beta1.NodeDrainEvicting))
+			Expect(d.Cordoned).To(BeTrue())
+			Expect(d.RemainingPods).To(Equal(int32(2)))
+			Expect(d.Modules).To(Equal([]kmmv1beta1.NodeDrainModule{{Name: name, Namespace: namespace}}))
+		})
+
+		It("should create the unloader Pod once the node is drained", func() {
+			nmcObj.Status.Drain = &kmmv1beta1.NodeDrainStatus{
+				Phase:    kmmv1beta1.NodeDrainEvicting,
+				Modules:  []kmmv1beta1.NodeDrainModule{{Name: name, Namespace: namespace}},
+				Cordoned: true,
+			}
+
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, drainStatus.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				drainer.EXPECT().EvictPods(ctx, node.Name, drainStatus.DrainPolicy).Return(0, nil),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmcObj, drainStatus),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmcObj, drainStatus, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Drain.Phase).To(Equal(kmmv1beta1.NodeDrainDrained))
+			Expect(nmcObj.Status.Drain.Modules).To(HaveLen(1))
+		})
+
+		It("should report eviction errors", func() {
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, drainStatus.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				drainer.EXPECT().Cordon(ctx, &node).Return(false, nil),
+				drainer.EXPECT().EvictPods(ctx, node.Name, drainStatus.DrainPolicy).Return(1, errors.New("random error")),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmcObj, drainStatus, &node),
+			).To(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Drain.Phase).To(Equal(kmmv1beta1.NodeDrainEvicting))
+			Expect(nmcObj.Status.Drain.Message).To(Equal("random error"))
+		})
+	})
+
+	Context("with maintenance windows", func() {
+		var (
+			windowStatus *kmmv1beta1.NodeModuleStatus
+			nmcObj       *kmmv1beta1.NodeModulesConfig
+		)
+
+		BeforeEach(func() {
+			windowStatus = status.DeepCopy()
+			windowStatus.MaintenanceWindows = closedMaintenanceWindows()
+
+			nmcObj = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{*windowStatus},
+				},
+			}
+		})
+
+		It("should hold back the unloader Pod until a window opens", func() {
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, windowStatus.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmcObj, windowStatus, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(maintenanceWindowPending(windowStatus)).To(BeTrue())
+		})
+
+		It("should not hold back the unload while the node is drained for the module", func() {
+			nmcObj.Status.Drain = &kmmv1beta1.NodeDrainStatus{
+				Phase:   kmmv1beta1.NodeDrainEvicting,
+				Modules: []kmmv1beta1.NodeDrainModule{{Name: name, Namespace: namespace}},
+			}
+
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, windowStatus.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmcObj, windowStatus),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmcObj, windowStatus, &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+
+	Context("with dependent modules", func() {
+		const addonBootID = "addon-boot-id"
+
+		var nmcObj *kmmv1beta1.NodeModulesConfig
+
+		BeforeEach(func() {
+			nmcObj = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{
+						*status.DeepCopy(),
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{
+								Name:      "addon",
+								Namespace: namespace,
+								DependsOn: []kmmv1beta1.ModuleReference{{Name: name, Namespace: namespace}},
+							},
+							Config: kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+							BootId: addonBootID,
+						},
+					},
+				},
+			}
+		})
+
+		It("should hold back the unloader Pod until the dependent modules are unloaded", func() {
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(&node, addonBootID).Return(false),
+				client.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmcObj, &nmcObj.Status.Modules[0], &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Modules[0].Conditions).To(
+				ContainElement(
+					And(
+						HaveField("Type", kmmv1beta1.NodeModuleConditionDependenciesPending),
+						HaveField("Status", metav1.ConditionTrue),
+						HaveField("Message", "waiting for namespace/addon to be unloaded"),
+					),
+				),
+			)
+		})
+
+		It("should not wait for dependent modules that were lost in a reboot", func() {
+			gomock.InOrder(
+				nm.EXPECT().IsNodeRebooted(&node, status.BootId).Return(false),
+				mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().IsNodeRebooted(&node, addonBootID).Return(true),
+				mockWorkerPodManager.EXPECT().CreateUnloaderPod(ctx, nmcObj, &nmcObj.Status.Modules[0]),
+			)
+
+			Expect(
+				helper.ProcessUnconfiguredModuleStatus(ctx, nmcObj, &nmcObj.Status.Modules[0], &node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+})
+
+var _ = Describe("nmcReconcilerHelperImpl_CompleteDrain", func() {
+	const (
+		modName      = "mod"
+		modNamespace = "ns"
+	)
+
+	var (
+		ctx = context.TODO()
+
+		client  *testclient.MockClient
+		sw      *testclient.MockStatusWriter
+		drainer *drain.MockDrainer
+		helper  nmcReconcilerHelper
+		nmcObj  *kmmv1beta1.NodeModulesConfig
+		node    v1.Node
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client = testclient.NewMockClient(ctrl)
+		sw = testclient.NewMockStatusWriter(ctrl)
+		drainer = drain.NewMockDrainer(ctrl)
+		helper = newNMCReconcilerHelper(client, nil, nil, nil, drainer, nil, nil)
+
+		nmcObj = &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Drain: &kmmv1beta1.NodeDrainStatus{
+					Phase:    kmmv1beta1.NodeDrainDrained,
+					Modules:  []kmmv1beta1.NodeDrainModule{{Name: modName, Namespace: modNamespace}},
+					Cordoned: true,
+				},
+			},
+		}
+	})
+
+	item := kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace}
+
+	It("should do nothing if the node was not drained", func() {
+		nmcObj.Status.Drain = nil
+
+		Expect(
+			helper.CompleteDrain(ctx, nmcObj, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should do nothing while the module is not unloaded", func() {
+		nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{{ModuleItem: item}}
+
+		Expect(
+			helper.CompleteDrain(ctx, nmcObj, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should do nothing while the module is not loaded with the new config", func() {
+		nmcObj.Spec.Modules = []kmmv1beta1.NodeModuleSpec{
+			{ModuleItem: item, Config: kmmv1beta1.ModuleConfig{ContainerImage: "new-image"}},
+		}
+		nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+			{ModuleItem: item, Config: kmmv1beta1.ModuleConfig{ContainerImage: "old-image"}},
+		}
+
+		Expect(
+			helper.CompleteDrain(ctx, nmcObj, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should uncordon the node once the module was loaded with the new config", func() {
+		nmcObj.Spec.Modules = []kmmv1beta1.NodeModuleSpec{
+			{ModuleItem: item, Config: kmmv1beta1.ModuleConfig{ContainerImage: "new-image"}},
+		}
+		nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+			{ModuleItem: item, Config: kmmv1beta1.ModuleConfig{ContainerImage: "new-image"}},
+		}
+
+		gomock.InOrder(
+			drainer.EXPECT().Uncordon(ctx, &node),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+		)
+
+		Expect(
+			helper.CompleteDrain(ctx, nmcObj, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmcObj.Status.Drain.Phase).To(Equal(kmmv1beta1.NodeDrainCompleted))
+		Expect(nmcObj.Status.Drain.CompletionTime).NotTo(BeNil())
+	})
+
+	It("should not uncordon a node that KMM did not cordon", func() {
+		nmcObj.Status.Drain.Cordoned = false
+
+		gomock.InOrder(
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+		)
+
+		Expect(
+			helper.CompleteDrain(ctx, nmcObj, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmcObj.Status.Drain.Phase).To(Equal(kmmv1beta1.NodeDrainCompleted))
+	})
+})
+
+var _ = Describe("loadRetryDelay", func() {
+	spec := &kmmv1beta1.NodeModuleSpec{
+		Config: kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+	}
+
+	failedStatus := func(failedAgo time.Duration, count int32) *kmmv1beta1.NodeModuleStatus {
+		return &kmmv1beta1.NodeModuleStatus{
+			LastFailure: &kmmv1beta1.WorkerFailure{
+				Action:   kmmv1beta1.WorkerActionLoad,
+				Time:     metav1.NewTime(time.Now().Add(-failedAgo)),
+				SpecHash: specHash(spec),
+			},
+			Conditions: []kmmv1beta1.NodeModuleCondition{
+				{Type: kmmv1beta1.NodeModuleConditionLoadFailed, Status: metav1.ConditionTrue, FailureCount: count},
+			},
+		}
+	}
+
+	It("should not wait without a policy", func() {
+		d, exhausted := loadRetryDelay(nil, spec, failedStatus(0, 5))
+		Expect(d).To(BeZero())
+		Expect(exhausted).To(BeFalse())
+	})
+
+	It("should double the backoff after each failure", func() {
+		policy := &config.RetryPolicy{InitialBackoff: time.Minute, MaxBackoff: time.Hour}
+
+		d, exhausted := loadRetryDelay(policy, spec, failedStatus(0, 3))
+		Expect(d).To(BeNumerically("~", 4*time.Minute, time.Second))
+		Expect(exhausted).To(BeFalse())
+	})
+
+	It("should not exceed the maximum backoff", func() {
+		policy := &config.RetryPolicy{InitialBackoff: time.Minute, MaxBackoff: 5 * time.Minute}
+
+		d, _ := loadRetryDelay(policy, spec, failedStatus(time.Minute, 10))
+		Expect(d).To(BeNumerically("~", 4*time.Minute, time.Second))
+	})
+
+	It("should report that the maximum number of attempts was reached", func() {
+		_, exhausted := loadRetryDelay(&config.RetryPolicy{MaxAttempts: 2}, spec, failedStatus(0, 2))
+		Expect(exhausted).To(BeTrue())
+	})
+
+	It("should ignore failures of another spec", func() {
+		other := spec.DeepCopy()
+		other.RetryToken = "1"
+
+		d, exhausted := loadRetryDelay(&config.RetryPolicy{MaxAttempts: 2}, other, failedStatus(0, 2))
+		Expect(d).To(BeZero())
+		Expect(exhausted).To(BeFalse())
+	})
+
+	It("should use the policy of the Module", func() {
+		other := spec.DeepCopy()
+		other.RetryPolicy = &kmmv1beta1.RetryPolicy{MaxAttempts: 5, InitialBackoff: &metav1.Duration{Duration: time.Second}}
+
+		Expect(
+			effectiveRetryPolicy(&config.RetryPolicy{MaxAttempts: 2}, &other.ModuleItem),
+		).To(
+			Equal(&config.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second}),
+		)
+	})
+})
+
+var _ = Describe("unloadRetryDelay", func() {
+	spec := &kmmv1beta1.NodeModuleSpec{
+		ModuleItem: kmmv1beta1.ModuleItem{Name: "name", Namespace: "namespace"},
+		Config:     kmmv1beta1.ModuleConfig{ContainerImage: "new-image"},
+	}
+
+	failedStatus := func(reason kmmv1beta1.WorkerFailureReason, failedAgo time.Duration, count int32, hash string) *kmmv1beta1.NodeModuleStatus {
+		return &kmmv1beta1.NodeModuleStatus{
+			LastFailure: &kmmv1beta1.WorkerFailure{
+				Action:   kmmv1beta1.WorkerActionUnload,
+				Reason:   reason,
+				Time:     metav1.NewTime(time.Now().Add(-failedAgo)),
+				SpecHash: hash,
+			},
+			Conditions: []kmmv1beta1.NodeModuleCondition{
+				{Type: kmmv1beta1.NodeModuleConditionUnloadFailed, Status: metav1.ConditionTrue, FailureCount: count},
+			},
+		}
+	}
+
+	It("should not wait for errors without a policy", func() {
+		d, exhausted := unloadRetryDelay(nil, nil, failedStatus(kmmv1beta1.WorkerFailureReasonError, 0, 5, ""))
+		Expect(d).To(BeZero())
+		Expect(exhausted).To(BeFalse())
+	})
+
+	It("should wait for the retry period without limit if the module was in use", func() {
+		d, exhausted := unloadRetryDelay(
+			&config.RetryPolicy{MaxAttempts: 2},
+			nil,
+			failedStatus(kmmv1beta1.WorkerFailureReasonModuleInUse, 0, 5, ""),
+		)
+		Expect(d).To(BeNumerically("~", unloadBlockedRetryPeriod, time.Second))
+		Expect(exhausted).To(BeFalse())
+	})
+
+	It("should apply the backoff of the policy to other errors", func() {
+		policy := &config.RetryPolicy{InitialBackoff: time.Minute, MaxBackoff: time.Hour}
+
+		d, exhausted := unloadRetryDelay(policy, nil, failedStatus(kmmv1beta1.WorkerFailureReasonError, 0, 3, ""))
+		Expect(d).To(BeNumerically("~", 4*time.Minute, time.Second))
+		Expect(exhausted).To(BeFalse())
+	})
+
+	It("should report that the maximum number of attempts was reached", func() {
+		_, exhausted := unloadRetryDelay(
+			&config.RetryPolicy{MaxAttempts: 2},
+			spec,
+			failedStatus(kmmv1beta1.WorkerFailureReasonError, 0, 2, specHash(spec)),
+		)
+		Expect(exhausted).To(BeTrue())
+	})
+
+	It("should ignore failures of another spec", func() {
+		other := spec.DeepCopy()
+		other.RetryToken = "1"
+
+		d, exhausted := unloadRetryDelay(
+			&config.RetryPolicy{MaxAttempts: 2},
+			other,
+			failedStatus(kmmv1beta1.WorkerFailureReasonError, 0, 2, specHash(spec)),
+		)
+		Expect(d).To(BeZero())
+		Expect(exhausted).To(BeFalse())
+	})
+
+	It("should ignore load failures", func() {
+		status := failedStatus(kmmv1beta1.WorkerFailureReasonError, 0, 2, "")
+		status.LastFailure.Action = kmmv1beta1.WorkerActionLoad
+
+		d, exhausted := unloadRetryDelay(&config.RetryPolicy{MaxAttempts: 2}, nil, status)
+		Expect(d).To(BeZero())
+		Expect(exhausted).To(BeFalse())
+	})
+})
+
+var ignoreConditionTimes = cmpopts.IgnoreFields(kmmv1beta1.NodeModuleCondition{}, "LastTransitionTime")
+
+var _ = Describe("maintenanceDelay", func() {
+	// Saturday
+	now := time.Date(2024, time.June, 15, 10, 30, 0, 0, time.UTC)
+
+	defaultWindows := []config.MaintenanceWindow{
+		{Schedule: "0 22 * * *", Duration: time.Hour},
+	}
+
+	DescribeTable("should return the delay until a window opens",
+		func(defaults []config.MaintenanceWindow, windows []kmmv1beta1.MaintenanceWindow, expected time.Duration) {
+			item := &kmmv1beta1.ModuleItem{MaintenanceWindows: windows}
+
+			Expect(maintenanceDelay(defaults, item, now)).To(Equal(expected))
+		},
+		Entry("no windows", nil, nil, time.Duration(0)),
+		Entry("default windows", defaultWindows, nil, 11*time.Hour+30*time.Minute),
+		Entry(
+			"module windows override the default ones",
+			defaultWindows,
+			[]kmmv1beta1.MaintenanceWindow{{Schedule: "0 12 * * 6", Duration: metav1.Duration{Duration: time.Hour}}},
+			90*time.Minute,
+		),
+		Entry(
+			"open window",
+			defaultWindows,
+			[]kmmv1beta1.MaintenanceWindow{{Schedule: "0 10 * * *", Duration: metav1.Duration{Duration: time.Hour}}},
+			time.Duration(0),
+		),
+	)
+
+	It("should return an error if a window is invalid", func() {
+		_, err := maintenanceDelay([]config.MaintenanceWindow{{Schedule: "@daily"}}, &kmmv1beta1.ModuleItem{}, now)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// closedMaintenanceWindows returns a maintenance window that opens for one minute, 12 hours from now.
+func closedMaintenanceWindows() []kmmv1beta1.MaintenanceWindow {
+	return []kmmv1beta1.MaintenanceWindow{
+		{
+			Schedule: fmt.Sprintf("0 %d * * *", (time.Now().UTC().Hour()+12)%24),
+			Duration: metav1.Duration{Duration: time.Minute},
+		},
+	}
+}
+
+var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
+	var (
+		ctx = context.TODO()
+
+		ctrl                 *gomock.Controller
+		kubeClient           *testclient.MockClient
+		mockWorkerPodManager *pod.MockWorkerPodManager
+		sw                   *testclient.MockStatusWriter
+		wh                   nmcReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = testclient.NewMockClient(ctrl)
+		mockWorkerPodManager = pod.NewMockWorkerPodManager(ctrl)
+		wh = newNMCReconcilerHelper(kubeClient, mockWorkerPodManager, nil, nil, nil, nil, nil)
+		sw = testclient.NewMockStatusWriter(ctrl)
+	})
+
+	const (
+		podName      = "pod-name"
+		podNamespace = "pod-namespace"
+	)
+
+	It("should do nothing if there are no running pods for this NMC", func() {
+		mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName)
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+		node := v1.Node{}
+		Expect(
+			wh.SyncStatus(ctx, nmc, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("failed pods", func() {
+		podWithStatus := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: podNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: "mod name 1",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodFailed},
+		}
+
+		podWithoutStatus := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: podNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: "mod name 2",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodFailed},
+		}
+
+		pods := []v1.Pod{podWithStatus, podWithoutStatus}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:      "mod name 1",
+							Namespace: podNamespace,
+						},
+						Config: kmmv1beta1.ModuleConfig{ContainerImage: "some image"},
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return(pods, nil),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &podWithStatus),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &podWithoutStatus),
+		)
+		node := v1.Node{}
+		Expect(
+			wh.SyncStatus(ctx, nmc, &node),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(HaveLen(1))
+	})
+
+	It("should record the failure of a restarting worker Pod", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		finishedAt := metav1.Now()
+
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: modNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Spec: v1.PodSpec{NodeName: nmcName},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: pod.WorkerContainerName,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode:   1,
+								Message:    "error while running the preLoad hook",
+								FinishedAt: finishedAt,
+							},
+						},
+						RestartCount: 2,
+					},
+				},
+			},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:      modName,
+							Namespace: modNamespace,
+						},
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(true),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(
+			BeComparableTo(
+				[]kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:      modName,
+							Namespace: modNamespace,
+						},
+						LastFailure: &kmmv1beta1.WorkerFailure{
+							Action:       kmmv1beta1.WorkerActionLoad,
+							Reason:       kmmv1beta1.WorkerFailureReasonError,
+							ExitCode:     1,
+							Message:      "error while running the preLoad hook",
+							RestartCount: 2,
+							Time:         finishedAt,
+							SpecHash:     specHash(&nmc.Spec.Modules[0]),
+						},
+						Conditions: []kmmv1beta1.NodeModuleCondition{
+							{
+								Type:   kmmv1beta1.NodeModuleConditionProgressing,
+								Status: metav1.ConditionTrue,
+								Reason: conditionReasonLoading,
+							},
+							{
+								Type:         kmmv1beta1.NodeModuleConditionLoadFailed,
+								Status:       metav1.ConditionTrue,
+								Reason:       string(kmmv1beta1.WorkerFailureReasonError),
+								Message:      "error while running the preLoad hook",
+								FailureCount: 1,
+							},
+						},
+					},
+				},
+				ignoreConditionTimes,
+			),
+		)
+	})
+
+	It("should record the classified error reported by the worker", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		finishedAt := metav1.Now()
+
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: modNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Spec: v1.PodSpec{NodeName: nmcName},
+			Status: v1.PodStatus{
+				Phase: v1.PodFailed,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: pod.WorkerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode:   1,
+								Message:    `{"duration":"1s","error":{"reason":"KernelMismatch","message":"module was not built for the running kernel"}}`,
+								FinishedAt: finishedAt,
+							},
+						},
+					},
+				},
+			},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(true),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(HaveLen(1))
+		Expect(nmc.Status.Modules[0].LastFailure).To(Equal(&kmmv1beta1.WorkerFailure{
+			Action:   kmmv1beta1.WorkerActionLoad,
+			Reason:   kmmv1beta1.WorkerFailureReasonKernelMismatch,
+			ExitCode: 1,
+			Message:  "module was not built for the running kernel",
+			Time:     finishedAt,
+		}))
+	})
+
+	It("should delete an unloader Pod that cannot unload a busy module", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		finishedAt := metav1.Now()
+
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: modNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Spec: v1.PodSpec{NodeName: nmcName},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: pod.WorkerContainerName,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode:   worker.ExitCodeModuleBusy,
+								Message:    "module is in use",
+								FinishedAt: finishedAt,
+							},
+						},
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		status := kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      modName,
+				Namespace: modNamespace,
+			},
+			Config: kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{status},
+			},
+		}
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(false),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&p).Return(false),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		status.LastFailure = &kmmv1beta1.WorkerFailure{
+			Action:       kmmv1beta1.WorkerActionUnload,
+			Reason:       kmmv1beta1.WorkerFailureReasonModuleInUse,
+			ExitCode:     worker.ExitCodeModuleBusy,
+			Message:      "module is in use",
+			RestartCount: 1,
+			Time:         finishedAt,
+		}
+
+		status.Conditions = []kmmv1beta1.NodeModuleCondition{
+			{
+				Type:   kmmv1beta1.NodeModuleConditionProgressing,
+				Status: metav1.ConditionTrue,
+				Reason: conditionReasonUnloading,
+			},
+			{
+				Type:         kmmv1beta1.NodeModuleConditionUnloadFailed,
+				Status:       metav1.ConditionTrue,
+				Reason:       string(kmmv1beta1.WorkerFailureReasonModuleInUse),
+				Message:      "module is in use",
+				FailureCount: 1,
+			},
+		}
+
+		Expect(nmc.Status.Modules).To(BeComparableTo([]kmmv1beta1.NodeModuleStatus{status}, ignoreConditionTimes))
+	})
+
+	It("should increment the failure count and require a reboot if a changed module is in use", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: modNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: pod.WorkerContainerName,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode:   worker.ExitCodeModuleBusy,
+								Message:    "module is in use",
+								FinishedAt: metav1.Now(),
+							},
+						},
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+						Config:     kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel", ContainerImage: "new"},
+					},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+						Config:     kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel", ContainerImage: "old"},
+						LastFailure: &kmmv1beta1.WorkerFailure{
+							Action:   kmmv1beta1.WorkerActionUnload,
+							Reason:   kmmv1beta1.WorkerFailureReasonModuleInUse,
+							ExitCode: worker.ExitCodeModuleBusy,
+							Time:     metav1.NewTime(time.Now().Add(-time.Hour)),
+						},
+						Conditions: []kmmv1beta1.NodeModuleCondition{
+							{
+								Type:         kmmv1beta1.NodeModuleConditionUnloadFailed,
+								Status:       metav1.ConditionTrue,
+								Reason:       string(kmmv1beta1.WorkerFailureReasonModuleInUse),
+								FailureCount: 2,
+							},
+						},
+					},
+				},
+			},
+		}
+
+		// the previous attempts unloaded the module for the same spec
+		nmcObj.Status.Modules[0].LastFailure.SpecHash = specHash(&nmcObj.Spec.Modules[0])
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(false),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&p).Return(false),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		conditions := nmcObj.Status.Modules[0].Conditions
+
+		unloadFailed := nmc.FindModuleCondition(conditions, kmmv1beta1.NodeModuleConditionUnloadFailed)
+		Expect(unloadFailed.FailureCount).To(Equal(int32(3)))
+
+		rebootRequired := nmc.FindModuleCondition(conditions, kmmv1beta1.NodeModuleConditionRebootRequired)
+		Expect(rebootRequired).NotTo(BeNil())
+		Expect(rebootRequired.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should delete a failed loader Pod and stop retrying after the maximum number of attempts", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
 
-	It("should do nothing if there are no running pods for this NMC", func() {
-		mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName)
+		wh = newNMCReconcilerHelper(kubeClient, mockWorkerPodManager, nil, nil, nil, &config.RetryPolicy{MaxAttempts: 2}, nil)
 
-		nmc := &kmmv1beta1.NodeModulesConfig{
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: modNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: pod.WorkerContainerName,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 1, FinishedAt: metav1.Now()},
+						},
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace}},
+				},
+			},
 		}
-		node := v1.Node{}
+
+		nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+			{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+				LastFailure: &kmmv1beta1.WorkerFailure{
+					Action:   kmmv1beta1.WorkerActionLoad,
+					Time:     metav1.NewTime(time.Now().Add(-time.Hour)),
+					SpecHash: specHash(&nmcObj.Spec.Modules[0]),
+				},
+				Conditions: []kmmv1beta1.NodeModuleCondition{
+					{Type: kmmv1beta1.NodeModuleConditionLoadFailed, Status: metav1.ConditionTrue, FailureCount: 1},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(true),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
 		Expect(
-			wh.SyncStatus(ctx, nmc, &node),
+			wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
 		).NotTo(
 			HaveOccurred(),
 		)
+
+		conditions := nmcObj.Status.Modules[0].Conditions
+
+		Expect(nmc.FindModuleCondition(conditions, kmmv1beta1.NodeModuleConditionLoadFailed).FailureCount).To(Equal(int32(2)))
+		Expect(nmc.FindModuleCondition(conditions, kmmv1beta1.NodeModuleConditionProgressing).Reason).To(Equal(conditionReasonRetryLimitReached))
 	})
 
-	It("failed pods", func() {
-		podWithStatus := v1.Pod{
+	It("should delete a failed unloader Pod and stop retrying after the maximum number of attempts", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		wh = newNMCReconcilerHelper(kubeClient, mockWorkerPodManager, nil, nil, nil, &config.RetryPolicy{MaxAttempts: 2}, nil)
+
+		p := v1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: podNamespace,
+				Namespace: modNamespace,
 				Name:      podName,
 				Labels: map[string]string{
-					constants.ModuleNameLabel: "mod name 1",
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: pod.WorkerContainerName,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 1, FinishedAt: metav1.Now()},
+						},
+						RestartCount: 1,
+					},
 				},
 			},
-			Status: v1.PodStatus{Phase: v1.PodFailed},
 		}
 
-		podWithoutStatus := v1.Pod{
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+						Config:     kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+						LastFailure: &kmmv1beta1.WorkerFailure{
+							Action: kmmv1beta1.WorkerActionUnload,
+							Reason: kmmv1beta1.WorkerFailureReasonError,
+							Time:   metav1.NewTime(time.Now().Add(-time.Hour)),
+						},
+						Conditions: []kmmv1beta1.NodeModuleCondition{
+							{Type: kmmv1beta1.NodeModuleConditionUnloadFailed, Status: metav1.ConditionTrue, FailureCount: 1},
+						},
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(false),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&p).Return(false),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		conditions := nmcObj.Status.Modules[0].Conditions
+
+		Expect(nmc.FindModuleCondition(conditions, kmmv1beta1.NodeModuleConditionUnloadFailed).FailureCount).To(Equal(int32(2)))
+
+		progressing := nmc.FindModuleCondition(conditions, kmmv1beta1.NodeModuleConditionProgressing)
+		Expect(progressing.Reason).To(Equal(conditionReasonRetryLimitReached))
+		Expect(progressing.Message).To(ContainSubstring("reboot the node"))
+	})
+
+	It("should mark a pending worker Pod as progressing", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		p := v1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: podNamespace,
+				Namespace: modNamespace,
 				Name:      podName,
 				Labels: map[string]string{
-					constants.ModuleNameLabel: "mod name 2",
+					constants.ModuleNameLabel: modName,
 				},
 			},
-			Status: v1.PodStatus{Phase: v1.PodFailed},
+			Status: v1.PodStatus{Phase: v1.PodPending},
 		}
 
-		pods := []v1.Pod{podWithStatus, podWithoutStatus}
-
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
-			Status: kmmv1beta1.NodeModulesConfigStatus{
-				Modules: []kmmv1beta1.NodeModuleStatus{
-					{
-						ModuleItem: kmmv1beta1.ModuleItem{
-							Name:      "mod name 1",
-							Namespace: podNamespace,
-						},
-						Config: kmmv1beta1.ModuleConfig{ContainerImage: "some image"},
-					},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace}},
 				},
 			},
 		}
 
 		gomock.InOrder(
-			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return(pods, nil),
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(true),
 			kubeClient.EXPECT().Status().Return(sw),
 			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
-			mockWorkerPodManager.EXPECT().DeletePod(ctx, &podWithStatus),
-			mockWorkerPodManager.EXPECT().DeletePod(ctx, &podWithoutStatus),
 		)
-		node := v1.Node{}
+
 		Expect(
-			wh.SyncStatus(ctx, nmc, &node),
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
 		).NotTo(
 			HaveOccurred(),
 		)
 
-		Expect(nmc.Status.Modules).To(HaveLen(1))
+		Expect(nmc.Status.Modules).To(
+			BeComparableTo(
+				[]kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+						Conditions: []kmmv1beta1.NodeModuleCondition{
+							{
+								Type:   kmmv1beta1.NodeModuleConditionProgressing,
+								Status: metav1.ConditionTrue,
+								Reason: conditionReasonLoading,
+							},
+						},
+					},
+				},
+				ignoreConditionTimes,
+			),
+		)
 	})
 
 	It("should remove the status and label if an unloader pod was successful", func() {
@@ -1222,7 +3151,10 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 					{
 						Name: "worker",
 						State: v1.ContainerState{
-							Terminated: &v1.ContainerStateTerminated{FinishedAt: now},
+							Terminated: &v1.ContainerStateTerminated{
+								FinishedAt: now,
+								Message:    `{"modules":[{"name":"test","srcVersion":"ABC"}],"parameters":{"debug":"1"},"firmwareFiles":["fw.bin"],"blacklistedModules":["intree"],"duration":"1.5s"}`,
+							},
 						},
 					},
 				},
@@ -1241,6 +3173,7 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 			mockWorkerPodManager.EXPECT().GetConfigAnnotation(&p).Return(string(b)),
 			mockWorkerPodManager.EXPECT().GetTolerationsAnnotation(&p).Return(string(tolerations)),
 			mockWorkerPodManager.EXPECT().GetModuleVersionAnnotation(&p).Return("some version"),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&p).Return(false),
 			kubeClient.EXPECT().Status().Return(sw),
 			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
 			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
@@ -1263,10 +3196,192 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 				Tolerations:        []v1.Toleration{testToleration},
 				Version:            "some version",
 			},
-			Config: cfg,
+			Config:             cfg,
+			LoadedModules:      []kmmv1beta1.LoadedKernelModule{{Name: "test", SrcVersion: "ABC"}},
+			Parameters:         map[string]string{"debug": "1"},
+			FirmwareFiles:      []string{"fw.bin"},
+			BlacklistedModules: []string{"intree"},
+			LoadDuration:       &metav1.Duration{Duration: 1500 * time.Millisecond},
+			Conditions: []kmmv1beta1.NodeModuleCondition{
+				{
+					Type:   kmmv1beta1.NodeModuleConditionProgressing,
+					Status: metav1.ConditionFalse,
+					Reason: conditionReasonLoaded,
+				},
+				{
+					Type:    kmmv1beta1.NodeModuleConditionLoaded,
+					Status:  metav1.ConditionTrue,
+					Reason:  conditionReasonLoaded,
+					Message: "loaded on kernel some-kernel-version",
+				},
+				{
+					Type:   kmmv1beta1.NodeModuleConditionLoadFailed,
+					Status: metav1.ConditionFalse,
+					Reason: conditionReasonLoaded,
+				},
+			},
+		}
+
+		Expect(nmc.Status.Modules[0]).To(BeComparableTo(expectedStatus, ignoreConditionTimes))
+	})
+
+	It("should delete a set-parameters Pod that failed", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		finishedAt := metav1.Now()
+
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: modNamespace,
+				Name:      podName,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: pod.WorkerContainerName,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode:   1,
+								Message:    `{"duration":"1s","error":{"reason":"ParameterNotWritable","message":"parameter queues is read-only"}}`,
+								FinishedAt: finishedAt,
+							},
+						},
+						RestartCount: 1,
+					},
+				},
+			},
+		}
+
+		status := kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      modName,
+				Namespace: modNamespace,
+			},
+			Config: kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{status},
+			},
+		}
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsLoaderPod(&p).Return(false),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&p).Return(true),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(HaveLen(1))
+		Expect(nmc.Status.Modules[0].LastFailure).To(Equal(&kmmv1beta1.WorkerFailure{
+			Action:       kmmv1beta1.WorkerActionSetParameters,
+			Reason:       kmmv1beta1.WorkerFailureReasonParameterNotWritable,
+			ExitCode:     1,
+			Message:      "parameter queues is read-only",
+			RestartCount: 1,
+			Time:         finishedAt,
+		}))
+	})
+
+	It("should only update the parameters if a set-parameters Pod was successful", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		cfg := kmmv1beta1.ModuleConfig{
+			KernelVersion: "some-kernel-version",
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				ModuleName: "test",
+				Parameters: []string{"debug=1"},
+			},
+		}
+
+		loadedModules := []kmmv1beta1.LoadedKernelModule{{Name: "test", SrcVersion: "ABC"}}
+		loadDuration := &metav1.Duration{Duration: 2 * time.Second}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Name:      modName,
+							Namespace: modNamespace,
+						},
+						LoadedModules: loadedModules,
+						Parameters:    map[string]string{"debug": "0"},
+						LoadDuration:  loadDuration,
+					},
+				},
+			},
+		}
+
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: modNamespace,
+				Labels: map[string]string{
+					constants.ModuleNameLabel: modName,
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "worker",
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								Message: `{"modules":[{"name":"test","srcVersion":"ABC"}],"parameters":{"debug":"1"},"duration":"10ms"}`,
+							},
+						},
+					},
+				},
+			},
 		}
 
-		Expect(nmc.Status.Modules[0]).To(BeComparableTo(expectedStatus))
+		b, err := yaml.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{p}, nil),
+			mockWorkerPodManager.EXPECT().IsUnloaderPod(&p).Return(false),
+			mockWorkerPodManager.EXPECT().GetConfigAnnotation(&p).Return(string(b)),
+			mockWorkerPodManager.EXPECT().GetTolerationsAnnotation(&p).Return(""),
+			mockWorkerPodManager.EXPECT().GetModuleVersionAnnotation(&p),
+			mockWorkerPodManager.EXPECT().IsSetParametersPod(&p).Return(true),
+			kubeClient.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+			mockWorkerPodManager.EXPECT().DeletePod(ctx, &p),
+		)
+
+		Expect(
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(nmc.Status.Modules).To(HaveLen(1))
+		Expect(nmc.Status.Modules[0].Config).To(Equal(cfg))
+		Expect(nmc.Status.Modules[0].Parameters).To(Equal(map[string]string{"debug": "1"}))
+		Expect(nmc.Status.Modules[0].LoadedModules).To(Equal(loadedModules))
+		Expect(nmc.Status.Modules[0].LoadDuration).To(Equal(loadDuration))
 	})
 
 	It("pod should not be deleted if NMC patch failed", func() {
@@ -1329,7 +3444,7 @@ var _ = Describe("nmcReconcilerHelperImpl_RemovePodFinalizers", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		kubeClient = testclient.NewMockClient(ctrl)
 		mockWorkerPodManager = pod.NewMockWorkerPodManager(ctrl)
-		wh = newNMCReconcilerHelper(kubeClient, mockWorkerPodManager, nil, nil)
+		wh = newNMCReconcilerHelper(kubeClient, mockWorkerPodManager, nil, nil, nil, nil, nil)
 	})
 
 	It("should do nothing if no pods are present", func() {
@@ -1422,7 +3537,7 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabels", func() {
 		}
 		fakeRecorder = record.NewFakeRecorder(10)
 		n = node.NewMockNode(ctrl)
-		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, n)
+		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, n, nil, nil, nil)
 		mlph = NewMocklabelPreparationHelper(ctrl)
 		wh = &nmcReconcilerHelperImpl{
 			client:     client,
@@ -1523,7 +3638,7 @@ var _ = Describe("nmcReconcilerHelperImpl_RecordEvents", func() {
 		client = testclient.NewMockClient(ctrl)
 		//nm = node.NewMockNode(ctrl)
 		fakeRecorder = record.NewFakeRecorder(10)
-		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, nil)
+		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, nil, nil, nil, nil)
 	})
 
 	closeAndGetAllEvents := func(events chan string) []string {
@@ -0,0 +1,165 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/meta"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//go:generate mockgen -source=drain.go -package=drain -destination=mock_drain.go
+
+type Drainer interface {
+	// Cordon marks node as unschedulable.
+	// It returns false if the node was already unschedulable, in which case it does not change it.
+	Cordon(ctx context.Context, node *v1.Node) (bool, error)
+	// Uncordon marks node as schedulable again.
+	Uncordon(ctx context.Context, node *v1.Node) error
+	// EvictPods evicts the Pods running on nodeName that policy selects.
+	// It returns the number of those Pods that are still running on the node, including those that are being
+	// evicted and those that a PodDisruptionBudget does not allow to evict yet.
+	EvictPods(ctx context.Context, nodeName string, policy *kmmv1beta1.DrainPolicy) (int, error)
+}
+
+type drainer struct {
+	client client.Client
+}
+
+func NewDrainer(client client.Client) Drainer {
+	return &drainer{client: client}
+}
+
+func (d *drainer) Cordon(ctx context.Context, node *v1.Node) (bool, error) {
+	if node.Spec.Unschedulable {
+		return false, nil
+	}
+
+	patchFrom := client.MergeFrom(node.DeepCopy())
+
+	node.Spec.Unschedulable = true
+	meta.SetAnnotation(node, constants.NodeCordonedAnnotation, "")
+
+	if err := d.client.Patch(ctx, node, patchFrom); err != nil {
+		return false, fmt.Errorf("could not cordon node %s: %v", node.Name, err)
+	}
+
+	return true, nil
+}
+
+func (d *drainer) Uncordon(ctx context.Context, node *v1.Node) error {
+	patchFrom := client.MergeFrom(node.DeepCopy())
+
+	node.Spec.Unschedulable = false
+	meta.RemoveAnnotation(node, constants.NodeCordonedAnnotation)
+
+	if err := d.client.Patch(ctx, node, patchFrom); err != nil {
+		return fmt.Errorf("could not uncordon node %s: %v", node.Name, err)
+	}
+
+	return nil
+}
+
+func (d *drainer) EvictPods(ctx context.Context, nodeName string, policy *kmmv1beta1.DrainPolicy) (int, error) {
+	logger := log.FromContext(ctx)
+
+	selector := labels.Nothing()
+
+	if policy.PodSelector != nil {
+		var err error
+
+		if selector, err = metav1.LabelSelectorAsSelector(policy.PodSelector); err != nil {
+			return 0, fmt.Errorf("invalid Pod selector: %v", err)
+		}
+	}
+
+	podList := v1.PodList{}
+
+	if err := d.client.List(ctx, &podList, client.MatchingFields{".spec.nodeName": nodeName}); err != nil {
+		return 0, fmt.Errorf("could not list Pods on node %s: %v", nodeName, err)
+	}
+
+	var (
+		errs      []error
+		remaining int
+	)
+
+	for i := range podList.Items {
+		p := &podList.Items[i]
+
+		if !mustEvict(p, selector, policy.Resources) {
+			continue
+		}
+
+		remaining++
+
+		if p.DeletionTimestamp != nil {
+			continue
+		}
+
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: p.Name, Namespace: p.Namespace},
+		}
+
+		err := d.client.SubResource("eviction").Create(ctx, p, eviction)
+		switch {
+		case err == nil:
+			logger.Info("Evicted Pod", "pod", client.ObjectKeyFromObject(p))
+		case k8serrors.IsNotFound(err):
+			remaining--
+		case k8serrors.IsTooManyRequests(err):
+			logger.Info("Pod cannot be evicted yet because of its disruption budget", "pod", client.ObjectKeyFromObject(p))
+		default:
+			errs = append(errs, fmt.Errorf("could not evict Pod %s/%s: %v", p.Namespace, p.Name, err))
+		}
+	}
+
+	return remaining, errors.Join(errs...)
+}
+
+// mustEvict returns true if p is running, if it is neither a static Pod nor a Pod managed by a DaemonSet, and if it
+// matches selector or requests any of resources.
+func mustEvict(p *v1.Pod, selector labels.Selector, resources []v1.ResourceName) bool {
+	if p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+		return false
+	}
+
+	if _, ok := p.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+
+	if owner := metav1.GetControllerOf(p); owner != nil && owner.Kind == "DaemonSet" {
+		return false
+	}
+
+	if selector.Matches(labels.Set(p.Labels)) {
+		return true
+	}
+
+	containers := make([]v1.Container, 0, len(p.Spec.InitContainers)+len(p.Spec.Containers))
+	containers = append(containers, p.Spec.InitContainers...)
+	containers = append(containers, p.Spec.Containers...)
+
+	for _, c := range containers {
+		for _, r := range resources {
+			if _, ok := c.Resources.Requests[r]; ok {
+				return true
+			}
+
+			if _, ok := c.Resources.Limits[r]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
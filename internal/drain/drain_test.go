@@ -0,0 +1,228 @@
+package drain
+
+import (
+	"context"
+	"errors"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const nodeName = "node-name"
+
+var _ = Describe("Cordon", func() {
+	var (
+		ctx  context.Context
+		clnt *client.MockClient
+		d    Drainer
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		d = NewDrainer(clnt)
+	})
+
+	It("should not do anything if the node is already unschedulable", func() {
+		node := &v1.Node{Spec: v1.NodeSpec{Unschedulable: true}}
+
+		cordoned, err := d.Cordon(ctx, node)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cordoned).To(BeFalse())
+	})
+
+	It("should mark the node as unschedulable and annotate it", func() {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+
+		clnt.EXPECT().Patch(ctx, node, gomock.Any())
+
+		cordoned, err := d.Cordon(ctx, node)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cordoned).To(BeTrue())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+		Expect(node.Annotations).To(HaveKey(constants.NodeCordonedAnnotation))
+	})
+
+	It("should return an error if the node cannot be patched", func() {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+
+		clnt.EXPECT().Patch(ctx, node, gomock.Any()).Return(errors.New("random error"))
+
+		_, err := d.Cordon(ctx, node)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Uncordon", func() {
+	It("should mark the node as schedulable and remove the annotation", func() {
+		ctx := context.Background()
+		clnt := client.NewMockClient(gomock.NewController(GinkgoT()))
+
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        nodeName,
+				Annotations: map[string]string{constants.NodeCordonedAnnotation: ""},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		}
+
+		clnt.EXPECT().Patch(ctx, node, gomock.Any())
+
+		Expect(
+			NewDrainer(clnt).Uncordon(ctx, node),
+		).NotTo(
+			HaveOccurred(),
+		)
+		Expect(node.Spec.Unschedulable).To(BeFalse())
+		Expect(node.Annotations).NotTo(HaveKey(constants.NodeCordonedAnnotation))
+	})
+})
+
+var _ = Describe("EvictPods", func() {
+	const resourceName v1.ResourceName = "example.com/device"
+
+	var (
+		ctx  context.Context
+		clnt *client.MockClient
+		src  *client.MockSubResourceClient
+		d    Drainer
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		src = client.NewMockSubResourceClient(ctrl)
+		d = NewDrainer(clnt)
+	})
+
+	policy := &kmmv1beta1.DrainPolicy{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "uses-device"}},
+		Resources:   []v1.ResourceName{resourceName},
+	}
+
+	expectPods := func(pods ...v1.Pod) {
+		clnt.
+			EXPECT().
+			List(ctx, &v1.PodList{}, ctrlclient.MatchingFields{".spec.nodeName": nodeName}).
+			DoAndReturn(func(_ context.Context, pl *v1.PodList, _ ...ctrlclient.ListOption) error {
+				pl.Items = pods
+				return nil
+			})
+	}
+
+	It("should return an error if the Pods cannot be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+
+		_, err := d.EvictPods(ctx, nodeName, policy)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should evict the Pods that match the selector or request the resources", func() {
+		selected := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "ns", Labels: map[string]string{"app": "uses-device"}},
+		}
+
+		requesting := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "requesting", Namespace: "ns"},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Resources: v1.ResourceRequirements{
+							Limits: v1.ResourceList{resourceName: resource.MustParse("1")},
+						},
+					},
+				},
+			},
+		}
+
+		terminating := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "terminating",
+				Namespace:         "ns",
+				Labels:            map[string]string{"app": "uses-device"},
+				DeletionTimestamp: &metav1.Time{},
+			},
+		}
+
+		unrelated := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "ns"}}
+
+		completed := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "completed", Namespace: "ns", Labels: map[string]string{"app": "uses-device"}},
+			Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+		}
+
+		daemonSetPod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "daemonset",
+				Namespace: "ns",
+				Labels:    map[string]string{"app": "uses-device"},
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "DaemonSet", Name: "ds", Controller: ptr.To(true)},
+				},
+			},
+		}
+
+		expectPods(selected, requesting, terminating, unrelated, completed, daemonSetPod)
+
+		gomock.InOrder(
+			clnt.EXPECT().SubResource("eviction").Return(src),
+			src.EXPECT().Create(ctx, &selected, &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "ns"}}),
+			clnt.EXPECT().SubResource("eviction").Return(src),
+			src.
+				EXPECT().
+				Create(ctx, &requesting, &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: "requesting", Namespace: "ns"}}).
+				Return(k8serrors.NewTooManyRequests("disruption budget", 0)),
+		)
+
+		remaining, err := d.EvictPods(ctx, nodeName, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(Equal(3))
+	})
+
+	It("should not count Pods that are already gone", func() {
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "ns", Labels: map[string]string{"app": "uses-device"}},
+		}
+
+		expectPods(p)
+
+		gomock.InOrder(
+			clnt.EXPECT().SubResource("eviction").Return(src),
+			src.EXPECT().Create(ctx, &p, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "pods"}, p.Name)),
+		)
+
+		remaining, err := d.EvictPods(ctx, nodeName, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeZero())
+	})
+
+	It("should return eviction errors", func() {
+		p := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "ns", Labels: map[string]string{"app": "uses-device"}},
+		}
+
+		expectPods(p)
+
+		gomock.InOrder(
+			clnt.EXPECT().SubResource("eviction").Return(src),
+			src.EXPECT().Create(ctx, &p, gomock.Any()).Return(errors.New("random error")),
+		)
+
+		remaining, err := d.EvictPods(ctx, nodeName, policy)
+		Expect(err).To(HaveOccurred())
+		Expect(remaining).To(Equal(1))
+	})
+})
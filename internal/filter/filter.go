@@ -6,18 +6,21 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/kubectl/pkg/util/podutils"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hubv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/module"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/nmc"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 )
@@ -147,6 +150,50 @@ func ListModulesForNMC(_ context.Context, obj client.Object) []reconcile.Request
 	return modules.UnsortedList()
 }
 
+// ModuleForNodeOverride returns a request for the Module that a ModuleNodeOverride applies to.
+func ModuleForNodeOverride(_ context.Context, obj client.Object) []reconcile.Request {
+	o, ok := obj.(*kmmv1beta1.ModuleNodeOverride)
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: o.Namespace, Name: o.Spec.ModuleName}},
+	}
+}
+
+// ModuleNodeOverrideHandler enqueues the Module that a ModuleNodeOverride applies to.
+// On updates, it also enqueues the Module that the override applied to before, so that a Module whose override moved to
+// another Module stops using it.
+func ModuleNodeOverrideHandler() handler.EventHandler {
+	enqueue := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request], objs ...client.Object) {
+		reqs := sets.New[reconcile.Request]()
+
+		for _, obj := range objs {
+			reqs.Insert(ModuleForNodeOverride(ctx, obj)...)
+		}
+
+		for req := range reqs {
+			q.Add(req)
+		}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+	}
+}
+
 func filterRelevantNodeUpdates() predicate.Predicate {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -415,6 +462,39 @@ func (f *Filter) EnqueueAllPreflightValidations(ctx context.Context, mod client.
 	return reqs
 }
 
+// FindConflictingModules returns requests for all Modules, other than mod, that use the same kernel module as mod.
+func (f *Filter) FindConflictingModules(ctx context.Context, mod client.Object) []reconcile.Request {
+	reqs := make([]reconcile.Request, 0)
+
+	m, ok := mod.(*kmmv1beta1.Module)
+	if !ok {
+		return reqs
+	}
+
+	logger := ctrl.LoggerFrom(ctx).WithValues("module", mod.GetName())
+
+	mods := kmmv1beta1.ModuleList{}
+	if err := f.client.List(ctx, &mods); err != nil {
+		logger.Error(err, "could not list modules")
+		return reqs
+	}
+
+	for _, other := range mods.Items {
+		if other.Namespace == m.Namespace && other.Name == m.Name {
+			continue
+		}
+
+		if module.ConflictingKernelModule(m, &other) == "" {
+			continue
+		}
+
+		nsn := types.NamespacedName{Name: other.Name, Namespace: other.Namespace}
+		reqs = append(reqs, reconcile.Request{NamespacedName: nsn})
+	}
+
+	return reqs
+}
+
 // DeletingPredicate returns a predicate that returns true if the object is being deleted.
 func DeletingPredicate() predicate.Predicate {
 	return predicate.NewPredicateFuncs(func(object client.Object) bool {
@@ -2,6 +2,7 @@ package filter
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -14,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -819,6 +821,133 @@ var _ = Describe("FindPreflightsForModule", func() {
 
 })
 
+var _ = Describe("FindConflictingModules", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		clnt = mockClient.NewMockClient(mockCtrl)
+		f = New(clnt, nil)
+		ctx = context.Background()
+	})
+
+	newModule := func(name, moduleName string) kmmv1beta1.Module {
+		return kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: kmmv1beta1.ModuleSpec{
+				ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{
+					Container: kmmv1beta1.ModuleLoaderContainerSpec{
+						Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: moduleName},
+					},
+				},
+			},
+		}
+	}
+
+	It("should return nothing if the modules cannot be listed", func() {
+		mod := newModule("a", "kmod")
+
+		clnt.EXPECT().List(ctx, gomock.Any()).Return(errors.New("random error"))
+
+		Expect(
+			f.FindConflictingModules(ctx, &mod),
+		).To(
+			BeEmpty(),
+		)
+	})
+
+	It("should only return the other modules that use the same kernel module", func() {
+		mod := newModule("a", "kmod")
+
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.ModuleList, _ ...interface{}) error {
+				list.Items = []kmmv1beta1.Module{mod, newModule("b", "kmod"), newModule("c", "other-kmod")}
+				return nil
+			},
+		)
+
+		Expect(
+			f.FindConflictingModules(ctx, &mod),
+		).To(
+			Equal([]reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "b"}},
+			}),
+		)
+	})
+})
+
+var _ = Describe("ModuleForNodeOverride", func() {
+	It("should return the Module of the override", func() {
+		o := kmmv1beta1.ModuleNodeOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "override", Namespace: "ns"},
+			Spec:       kmmv1beta1.ModuleNodeOverrideSpec{ModuleName: "mod"},
+		}
+
+		Expect(
+			ModuleForNodeOverride(context.Background(), &o),
+		).To(
+			Equal([]reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "mod"}},
+			}),
+		)
+	})
+})
+
+var _ = Describe("ModuleNodeOverrideHandler", func() {
+	var q workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	BeforeEach(func() {
+		q = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		DeferCleanup(q.ShutDown)
+	})
+
+	newOverride := func(moduleName string) *kmmv1beta1.ModuleNodeOverride {
+		return &kmmv1beta1.ModuleNodeOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "override", Namespace: "ns"},
+			Spec:       kmmv1beta1.ModuleNodeOverrideSpec{ModuleName: moduleName},
+		}
+	}
+
+	queued := func() []reconcile.Request {
+		reqs := make([]reconcile.Request, 0, q.Len())
+
+		for q.Len() > 0 {
+			req, _ := q.Get()
+			reqs = append(reqs, req)
+			q.Done(req)
+		}
+
+		return reqs
+	}
+
+	It("should enqueue the Module of a new override", func() {
+		ModuleNodeOverrideHandler().Create(context.Background(), event.CreateEvent{Object: newOverride("mod")}, q)
+
+		Expect(queued()).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "mod"}},
+		))
+	})
+
+	It("should enqueue the old and the new Module when the moduleName of an override changes", func() {
+		ev := event.UpdateEvent{ObjectOld: newOverride("old-mod"), ObjectNew: newOverride("new-mod")}
+
+		ModuleNodeOverrideHandler().Update(context.Background(), ev, q)
+
+		Expect(queued()).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "old-mod"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "new-mod"}},
+		))
+	})
+
+	It("should enqueue the Module of a deleted override", func() {
+		ModuleNodeOverrideHandler().Delete(context.Background(), event.DeleteEvent{Object: newOverride("mod")}, q)
+
+		Expect(queued()).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "mod"}},
+		))
+	})
+})
+
 var _ = Describe("nodeTaintsChanged", func() {
 	var (
 		oldNode v1.Node
@@ -0,0 +1,71 @@
+package maintenance
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseSchedule", func() {
+	DescribeTable("should reject invalid expressions",
+		func(expr string) {
+			_, err := ParseSchedule(expr)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("too few fields", "0 2 * *"),
+		Entry("too many fields", "0 2 * * * *"),
+		Entry("unknown descriptor", "@sometimes"),
+		Entry("minute out of range", "60 2 * * *"),
+		Entry("day of month out of range", "0 2 0 * *"),
+		Entry("invalid value", "0 two * * *"),
+		Entry("invalid step", "*/0 * * * *"),
+		Entry("reversed range", "0 5-2 * * *"),
+	)
+})
+
+var _ = Describe("Schedule_Next", func() {
+	// Saturday
+	now := time.Date(2024, time.June, 15, 10, 30, 45, 0, time.UTC)
+
+	DescribeTable("should return the next matching time",
+		func(expr string, expected time.Time) {
+			s, err := ParseSchedule(expr)
+			Expect(err).NotTo(HaveOccurred())
+
+			next, err := s.Next(now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2024, time.June, 15, 10, 31, 0, 0, time.UTC)),
+		Entry("later today", "0 22 * * *", time.Date(2024, time.June, 15, 22, 0, 0, 0, time.UTC)),
+		Entry("tomorrow", "0 2 * * *", time.Date(2024, time.June, 16, 2, 0, 0, 0, time.UTC)),
+		Entry("steps", "*/20 * * * *", time.Date(2024, time.June, 15, 10, 40, 0, 0, time.UTC)),
+		Entry("step from a value", "5/20 * * * *", time.Date(2024, time.June, 15, 10, 45, 0, 0, time.UTC)),
+		Entry("list and range", "0 1,3-4 * * *", time.Date(2024, time.June, 16, 1, 0, 0, 0, time.UTC)),
+		Entry("day of week name", "0 0 * * mon", time.Date(2024, time.June, 17, 0, 0, 0, 0, time.UTC)),
+		Entry("7 is Sunday", "0 0 * * 7", time.Date(2024, time.June, 16, 0, 0, 0, 0, time.UTC)),
+		Entry("month name", "0 0 1 jan *", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("descriptor", "@monthly", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("either day field", "0 0 20 * mon", time.Date(2024, time.June, 17, 0, 0, 0, 0, time.UTC)),
+		Entry("leap day", "0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)),
+	)
+
+	It("should evaluate the schedule in UTC", func() {
+		s, err := ParseSchedule("0 12 * * *")
+		Expect(err).NotTo(HaveOccurred())
+
+		next, err := s.Next(now.In(time.FixedZone("UTC+2", 2*60*60)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next).To(Equal(time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)))
+	})
+
+	It("should return an error if the schedule never matches", func() {
+		s, err := ParseSchedule("0 0 31 2 *")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = s.Next(now)
+		Expect(err).To(HaveOccurred())
+	})
+})
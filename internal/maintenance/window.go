@@ -0,0 +1,82 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Window is a recurring period of time that opens on a schedule and stays open for a fixed duration.
+type Window struct {
+	schedule *Schedule
+	duration time.Duration
+}
+
+func NewWindow(schedule string, duration time.Duration) (*Window, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive, got %v", duration)
+	}
+
+	s, err := ParseSchedule(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse schedule %q: %v", schedule, err)
+	}
+
+	return &Window{schedule: s, duration: duration}, nil
+}
+
+// IsOpen returns true if the window opened less than its duration before t.
+func (w *Window) IsOpen(t time.Time) (bool, error) {
+	opening, err := w.schedule.Next(t.Add(-w.duration))
+	if err != nil {
+		return false, err
+	}
+
+	return !opening.After(t), nil
+}
+
+// NextOpening returns the first time strictly after t at which the window opens.
+func (w *Window) NextOpening(t time.Time) (time.Time, error) {
+	return w.schedule.Next(t)
+}
+
+// Delay returns 0 if one of windows is open at now, and how long it takes until one of them opens otherwise.
+// It returns 0 if windows is empty.
+func Delay(windows []*Window, now time.Time) (time.Duration, error) {
+	if len(windows) == 0 {
+		return 0, nil
+	}
+
+	var (
+		delay time.Duration
+		errs  []error
+	)
+
+	for _, w := range windows {
+		open, err := w.IsOpen(now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if open {
+			return 0, nil
+		}
+
+		next, err := w.NextOpening(now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if d := next.Sub(now); delay == 0 || d < delay {
+			delay = d
+		}
+	}
+
+	if delay == 0 {
+		return 0, errors.Join(errs...)
+	}
+
+	return delay, nil
+}
@@ -0,0 +1,65 @@
+package maintenance
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewWindow", func() {
+	It("should return an error if the duration is not positive", func() {
+		_, err := NewWindow("@daily", 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the schedule is invalid", func() {
+		_, err := NewWindow("0 25 * * *", time.Hour)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Window_IsOpen", func() {
+	w, _ := NewWindow("0 2 * * *", 2*time.Hour)
+
+	DescribeTable("should tell if the window is open",
+		func(t time.Time, expected bool) {
+			open, err := w.IsOpen(t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(Equal(expected))
+		},
+		Entry("before", time.Date(2024, time.June, 15, 1, 59, 59, 0, time.UTC), false),
+		Entry("at the opening", time.Date(2024, time.June, 15, 2, 0, 0, 0, time.UTC), true),
+		Entry("inside", time.Date(2024, time.June, 15, 3, 30, 0, 0, time.UTC), true),
+		Entry("at the closing", time.Date(2024, time.June, 15, 4, 0, 0, 0, time.UTC), false),
+	)
+})
+
+var _ = Describe("Delay", func() {
+	now := time.Date(2024, time.June, 15, 10, 30, 0, 0, time.UTC)
+
+	It("should return 0 without windows", func() {
+		Expect(Delay(nil, now)).To(BeZero())
+	})
+
+	It("should return 0 if one window is open", func() {
+		closed, _ := NewWindow("0 2 * * *", time.Hour)
+		open, _ := NewWindow("0 10 * * *", time.Hour)
+
+		Expect(Delay([]*Window{closed, open}, now)).To(BeZero())
+	})
+
+	It("should return the time until the next opening", func() {
+		tomorrow, _ := NewWindow("0 2 * * *", time.Hour)
+		tonight, _ := NewWindow("0 22 * * *", time.Hour)
+
+		Expect(Delay([]*Window{tomorrow, tonight}, now)).To(Equal(11*time.Hour + 30*time.Minute))
+	})
+
+	It("should return an error if no window ever opens", func() {
+		never, _ := NewWindow("0 0 31 2 *", time.Hour)
+
+		_, err := Delay([]*Window{never}, now)
+		Expect(err).To(HaveOccurred())
+	})
+})
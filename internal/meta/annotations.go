@@ -2,6 +2,23 @@ package meta
 
 import "sigs.k8s.io/controller-runtime/pkg/client"
 
+func HasAnnotation(obj client.Object, key string) bool {
+	_, ok := obj.GetAnnotations()[key]
+	return ok
+}
+
+func RemoveAnnotation(obj client.Object, key string) {
+	ann := obj.GetAnnotations()
+
+	if ann == nil {
+		return
+	}
+
+	delete(ann, key)
+
+	obj.SetAnnotations(ann)
+}
+
 func SetAnnotation(obj client.Object, key, value string) {
 	ann := obj.GetAnnotations()
 
@@ -29,3 +29,49 @@ var _ = Describe("SetAnnotation", func() {
 		Entry("existing annotation", map[string]string{key: "some-other-value"}, key, "test value"),
 	)
 })
+
+var _ = Describe("HasAnnotation", func() {
+	const key = "test-key"
+
+	DescribeTable(
+		"should work as expected",
+		func(annotations map[string]string, expected bool) {
+			obj := &unstructured.Unstructured{}
+
+			obj.SetAnnotations(annotations)
+
+			Expect(
+				HasAnnotation(obj, key),
+			).To(
+				Equal(expected),
+			)
+		},
+		Entry("nil annotations", nil, false),
+		Entry("other annotation", map[string]string{"other-key": ""}, false),
+		Entry("existing annotation", map[string]string{key: ""}, true),
+	)
+})
+
+var _ = Describe("RemoveAnnotation", func() {
+	const key = "test-key"
+
+	DescribeTable(
+		"should work as expected",
+		func(annotations map[string]string) {
+			obj := &unstructured.Unstructured{}
+
+			obj.SetAnnotations(annotations)
+
+			RemoveAnnotation(obj, key)
+
+			Expect(
+				obj.GetAnnotations(),
+			).NotTo(
+				HaveKey(key),
+			)
+		},
+		Entry("nil annotations", nil),
+		Entry("empty annotations", make(map[string]string)),
+		Entry("existing annotation", map[string]string{key: "some-value"}),
+	)
+})
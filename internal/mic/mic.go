@@ -81,7 +81,7 @@ func (mici *micImpl) Get(ctx context.Context, name, ns string) (*kmmv1beta1.Modu
 
 	var micObj kmmv1beta1.ModuleImagesConfig
 	if err := mici.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &micObj); err != nil {
-		return nil, fmt.Errorf("could not get ModuleImagesConfig %s: %v", name, err)
+		return nil, fmt.Errorf("could not get ModuleImagesConfig %s: %w", name, err)
 	}
 
 	return &micObj, nil
@@ -166,6 +166,15 @@ var _ = Describe("Get", func() {
 		Expect(err.Error()).To(ContainSubstring("could not get ModuleImagesConfig"))
 	})
 
+	It("should keep the NotFound error", func() {
+
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, micName))
+
+		_, err := micAPI.Get(ctx, micName, micNamespace)
+
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
 	It("should work as expected", func() {
 
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil)
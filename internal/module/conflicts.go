@@ -0,0 +1,50 @@
+package module
+
+import (
+	"slices"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+// kernelModules returns the kernel module that mod loads, and all the in-tree modules that it removes for any kernel.
+func kernelModules(mod *kmmv1beta1.Module) (string, []string) {
+	if mod.Spec.ModuleLoader == nil {
+		return "", nil
+	}
+
+	container := mod.Spec.ModuleLoader.Container
+
+	removed := slices.Clone(container.InTreeModulesToRemove)
+
+	if container.InTreeModuleToRemove != "" {
+		removed = append(removed, container.InTreeModuleToRemove)
+	}
+
+	for _, km := range container.KernelMappings {
+		removed = append(removed, km.InTreeModulesToRemove...)
+
+		if km.InTreeModuleToRemove != "" {
+			removed = append(removed, km.InTreeModuleToRemove)
+		}
+	}
+
+	return container.Modprobe.ModuleName, removed
+}
+
+// ConflictingKernelModule returns a kernel module that both a and b load, or that one of them loads while the other
+// removes it on some kernel; it returns an empty string if a and b do not conflict.
+func ConflictingKernelModule(a, b *kmmv1beta1.Module) string {
+	aName, aRemoved := kernelModules(a)
+	bName, bRemoved := kernelModules(b)
+
+	switch {
+	case aName != "" && aName == bName:
+		return aName
+	case aName != "" && slices.Contains(bRemoved, aName):
+		return aName
+	case bName != "" && slices.Contains(aRemoved, bName):
+		return bName
+	}
+
+	return ""
+}
@@ -0,0 +1,38 @@
+package module
+
+import (
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConflictingKernelModule", func() {
+	newModule := func(moduleName string, container []string, mapping []string) *kmmv1beta1.Module {
+		return &kmmv1beta1.Module{
+			Spec: kmmv1beta1.ModuleSpec{
+				ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{
+					Container: kmmv1beta1.ModuleLoaderContainerSpec{
+						Modprobe:              kmmv1beta1.ModprobeSpec{ModuleName: moduleName},
+						InTreeModulesToRemove: container,
+						KernelMappings: []kmmv1beta1.KernelMapping{
+							{Regexp: ".*", InTreeModulesToRemove: mapping},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	DescribeTable("should return the conflicting kernel module",
+		func(a, b *kmmv1beta1.Module, expected string) {
+			Expect(ConflictingKernelModule(a, b)).To(Equal(expected))
+			Expect(ConflictingKernelModule(b, a)).To(Equal(expected))
+		},
+		Entry("same kernel module", newModule("kmod", nil, nil), newModule("kmod", nil, nil), "kmod"),
+		Entry("different kernel modules", newModule("kmod-a", nil, nil), newModule("kmod-b", nil, nil), ""),
+		Entry("kernel module removed by the container", newModule("kmod-a", nil, nil), newModule("kmod-b", []string{"kmod-a"}, nil), "kmod-a"),
+		Entry("kernel module removed by a kernel mapping", newModule("kmod-a", nil, nil), newModule("kmod-b", nil, []string{"kmod-a"}), "kmod-a"),
+		Entry("both remove the same in-tree module", newModule("kmod-a", []string{"in-tree"}, nil), newModule("kmod-b", []string{"in-tree"}, nil), ""),
+		Entry("no module loader", &kmmv1beta1.Module{}, newModule("kmod", nil, nil), ""),
+	)
+})
@@ -7,6 +7,7 @@ import (
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/kernel"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 )
@@ -132,6 +133,8 @@ func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.Kernel
 		mld.InTreeModulesToRemove = []string{inTreeModuleToRemove}
 	}
 
+	mld.BlacklistInTreeModules = mod.Spec.ModuleLoader.Container.BlacklistInTreeModules
+
 	mld.KernelVersion = kernelVersion
 	mld.KernelNormalizedVersion = kernel.NormalizeVersion(kernelVersion)
 	mld.Name = mod.Name
@@ -143,6 +146,19 @@ func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.Kernel
 	mld.Modprobe = mod.Spec.ModuleLoader.Container.Modprobe
 	mld.ModuleVersion = mod.Spec.ModuleLoader.Container.Version
 	mld.ImagePullPolicy = mod.Spec.ModuleLoader.Container.ImagePullPolicy
+	mld.RetryPolicy = mod.Spec.ModuleLoader.RetryPolicy
+	mld.RetryToken = mod.Annotations[constants.RetryAnnotation]
+	mld.DrainPolicy = mod.Spec.ModuleLoader.DrainPolicy
+	mld.MaintenanceWindows = mod.Spec.ModuleLoader.MaintenanceWindows
+
+	for _, dep := range mod.Spec.DependsOn {
+		if dep.Namespace == "" {
+			dep.Namespace = mod.Namespace
+		}
+
+		mld.DependsOn = append(mld.DependsOn, dep)
+	}
+
 	mld.Owner = mod
 
 	return mld, nil
@@ -291,6 +291,22 @@ var _ = Describe("prepareModuleLoaderData", func() {
 		Entry("inTreeModule defined in mapping", false, true, []string{"inTreeModuleToRemoveInMapping"}),
 		Entry("inTreeModule defined in mapping and container", true, true, []string{"inTreeModuleToRemoveInMapping"}),
 	)
+
+	It("should default the namespace of dependencies to the Module's", func() {
+		mod.Namespace = "mod-namespace"
+		mod.Spec.DependsOn = []kmmv1beta1.ModuleReference{
+			{Name: "core"},
+			{Name: "other", Namespace: "other-namespace"},
+		}
+
+		res, err := kh.prepareModuleLoaderData(&mapping, &mod, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.DependsOn).To(Equal([]kmmv1beta1.ModuleReference{
+			{Name: "core", Namespace: "mod-namespace"},
+			{Name: "other", Namespace: "other-namespace"},
+		}))
+		Expect(mod.Spec.DependsOn[0].Namespace).To(BeEmpty())
+	})
 })
 
 var _ = Describe("replaceTemplates", func() {
@@ -0,0 +1,50 @@
+package nmc
+
+import (
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FindModuleCondition returns the condition of type conditionType in conditions, or nil if there is none.
+func FindModuleCondition(
+	conditions []kmmv1beta1.NodeModuleCondition,
+	conditionType kmmv1beta1.NodeModuleConditionType,
+) *kmmv1beta1.NodeModuleCondition {
+	for i := 0; i < len(conditions); i++ {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// SetModuleCondition adds condition to the conditions of status, or updates the existing condition of the same type.
+// LastTransitionTime is set to the current time if it is empty and the condition is new or its status changed;
+// otherwise the existing LastTransitionTime is kept.
+func SetModuleCondition(status *kmmv1beta1.NodeModuleStatus, condition kmmv1beta1.NodeModuleCondition) {
+	existing := FindModuleCondition(status.Conditions, condition.Type)
+
+	if existing == nil {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+
+		status.Conditions = append(status.Conditions, condition)
+		return
+	}
+
+	if existing.Status != condition.Status {
+		existing.Status = condition.Status
+
+		if condition.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = metav1.Now()
+		} else {
+			existing.LastTransitionTime = condition.LastTransitionTime
+		}
+	}
+
+	existing.Reason = condition.Reason
+	existing.Message = condition.Message
+	existing.FailureCount = condition.FailureCount
+}
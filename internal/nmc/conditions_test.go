@@ -0,0 +1,74 @@
+package nmc
+
+import (
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("SetModuleCondition", func() {
+	past := metav1.Unix(1000, 0)
+
+	It("should add a new condition and set its transition time", func() {
+		status := kmmv1beta1.NodeModuleStatus{}
+
+		SetModuleCondition(&status, kmmv1beta1.NodeModuleCondition{
+			Type:   kmmv1beta1.NodeModuleConditionLoaded,
+			Status: metav1.ConditionTrue,
+			Reason: "Loaded",
+		})
+
+		Expect(status.Conditions).To(HaveLen(1))
+		Expect(status.Conditions[0].Reason).To(Equal("Loaded"))
+		Expect(status.Conditions[0].LastTransitionTime.IsZero()).To(BeFalse())
+	})
+
+	It("should keep the transition time if the status did not change", func() {
+		status := kmmv1beta1.NodeModuleStatus{
+			Conditions: []kmmv1beta1.NodeModuleCondition{
+				{
+					Type:               kmmv1beta1.NodeModuleConditionLoadFailed,
+					Status:             metav1.ConditionTrue,
+					Reason:             "HookFailed",
+					FailureCount:       1,
+					LastTransitionTime: past,
+				},
+			},
+		}
+
+		SetModuleCondition(&status, kmmv1beta1.NodeModuleCondition{
+			Type:         kmmv1beta1.NodeModuleConditionLoadFailed,
+			Status:       metav1.ConditionTrue,
+			Reason:       "KernelMismatch",
+			FailureCount: 2,
+		})
+
+		Expect(status.Conditions).To(Equal([]kmmv1beta1.NodeModuleCondition{
+			{
+				Type:               kmmv1beta1.NodeModuleConditionLoadFailed,
+				Status:             metav1.ConditionTrue,
+				Reason:             "KernelMismatch",
+				FailureCount:       2,
+				LastTransitionTime: past,
+			},
+		}))
+	})
+
+	It("should update the transition time if the status changed", func() {
+		status := kmmv1beta1.NodeModuleStatus{
+			Conditions: []kmmv1beta1.NodeModuleCondition{
+				{Type: kmmv1beta1.NodeModuleConditionProgressing, Status: metav1.ConditionTrue, LastTransitionTime: past},
+			},
+		}
+
+		SetModuleCondition(&status, kmmv1beta1.NodeModuleCondition{
+			Type:   kmmv1beta1.NodeModuleConditionProgressing,
+			Status: metav1.ConditionFalse,
+		})
+
+		Expect(status.Conditions).To(HaveLen(1))
+		Expect(status.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+		Expect(status.Conditions[0].LastTransitionTime.After(past.Time)).To(BeTrue())
+	})
+})
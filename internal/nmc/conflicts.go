@@ -0,0 +1,70 @@
+package nmc
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+// Conflict is another module of a NodeModulesConfig that uses the same kernel module.
+type Conflict struct {
+	Namespace    string
+	Name         string
+	KernelModule string
+}
+
+// ConflictError is returned by SetModuleConfig when a module uses the same kernel module as other modules of a
+// NodeModulesConfig.
+type ConflictError struct {
+	Node      string
+	Conflicts []Conflict
+	// Rejected is true if the config of the module was not set because of the conflict policy.
+	Rejected bool
+}
+
+func (e *ConflictError) Error() string {
+	conflicts := make([]string, 0, len(e.Conflicts))
+
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, fmt.Sprintf("%s by Module %s/%s", c.KernelModule, c.Namespace, c.Name))
+	}
+
+	return fmt.Sprintf("kernel modules also used on node %s: %s", e.Node, strings.Join(conflicts, ", "))
+}
+
+// ConflictingKernelModule returns a kernel module that both a and b load, or that one of them loads while the other
+// removes it; it returns an empty string if a and b do not conflict.
+func ConflictingKernelModule(a, b *kmmv1beta1.ModuleConfig) string {
+	aName := a.Modprobe.ModuleName
+	bName := b.Modprobe.ModuleName
+
+	switch {
+	case aName != "" && aName == bName:
+		return aName
+	case aName != "" && slices.Contains(b.InTreeModulesToRemove, aName):
+		return aName
+	case bName != "" && slices.Contains(a.InTreeModulesToRemove, bName):
+		return bName
+	}
+
+	return ""
+}
+
+// FindConflicts returns the modules in the spec of nmc, other than namespace/name, that conflict with config.
+func FindConflicts(nmc *kmmv1beta1.NodeModulesConfig, namespace, name string, config *kmmv1beta1.ModuleConfig) []Conflict {
+	var conflicts []Conflict
+
+	for _, m := range nmc.Spec.Modules {
+		if m.Namespace == namespace && m.Name == name {
+			continue
+		}
+
+		if km := ConflictingKernelModule(config, &m.Config); km != "" {
+			conflicts = append(conflicts, Conflict{Namespace: m.Namespace, Name: m.Name, KernelModule: km})
+		}
+	}
+
+	return conflicts
+}
@@ -0,0 +1,65 @@
+package nmc
+
+import (
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConflictingKernelModule", func() {
+	DescribeTable("should return the conflicting kernel module",
+		func(a, b kmmv1beta1.ModuleConfig, expected string) {
+			Expect(ConflictingKernelModule(&a, &b)).To(Equal(expected))
+			Expect(ConflictingKernelModule(&b, &a)).To(Equal(expected))
+		},
+		Entry(
+			"same kernel module",
+			kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}},
+			kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}},
+			"kmod",
+		),
+		Entry(
+			"kernel module removed by the other config",
+			kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}},
+			kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "other"}, InTreeModulesToRemove: []string{"kmod"}},
+			"kmod",
+		),
+		Entry(
+			"different kernel modules",
+			kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}},
+			kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "other"}},
+			"",
+		),
+		Entry(
+			"no module name",
+			kmmv1beta1.ModuleConfig{},
+			kmmv1beta1.ModuleConfig{},
+			"",
+		),
+	)
+})
+
+var _ = Describe("FindConflicts", func() {
+	It("should ignore the module itself", func() {
+		nmc := kmmv1beta1.NodeModulesConfig{
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: "name", Namespace: "ns"},
+						Config:     kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}},
+					},
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{Name: "other", Namespace: "ns"},
+						Config:     kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}},
+					},
+				},
+			},
+		}
+
+		Expect(
+			FindConflicts(&nmc, "ns", "name", &kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}}),
+		).To(
+			Equal([]Conflict{{Namespace: "ns", Name: "other", KernelModule: "kmod"}}),
+		)
+	})
+})
@@ -6,6 +6,7 @@ import (
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,12 +23,14 @@ type Helper interface {
 }
 
 type helper struct {
-	client client.Client
+	client         client.Client
+	conflictPolicy config.ConflictPolicy
 }
 
-func NewHelper(client client.Client) Helper {
+func NewHelper(client client.Client, conflictPolicy config.ConflictPolicy) Helper {
 	return &helper{
-		client: client,
+		client:         client,
+		conflictPolicy: conflictPolicy,
 	}
 }
 
@@ -43,12 +46,28 @@ func (h *helper) Get(ctx context.Context, name string) (*kmmv1beta1.NodeModulesC
 	return &nmc, nil
 }
 
+// SetModuleConfig sets the config of the module that mld describes in the spec of nmc.
+// If other modules of nmc use the same kernel module, it returns a *ConflictError.
+// Unless the conflict policy is Warn, the config of a module that is not in nmc yet is then not set: the modules that
+// were configured first keep the node. With FirstWins, callers remove newer conflicting modules from nmc beforehand.
 func (h *helper) SetModuleConfig(
 	nmc *kmmv1beta1.NodeModulesConfig,
 	mld *api.ModuleLoaderData,
 	moduleConfig *kmmv1beta1.ModuleConfig) error {
 
 	foundEntry, _ := h.GetModuleSpecEntry(nmc, mld.Namespace, mld.Name)
+
+	var conflictErr *ConflictError
+
+	if conflicts := FindConflicts(nmc, mld.Namespace, mld.Name, moduleConfig); len(conflicts) > 0 {
+		conflictErr = &ConflictError{Node: nmc.Name, Conflicts: conflicts}
+
+		if foundEntry == nil && h.conflictPolicy != "" && h.conflictPolicy != config.ConflictPolicyWarn {
+			conflictErr.Rejected = true
+			return conflictErr
+		}
+	}
+
 	if foundEntry == nil {
 		nms := kmmv1beta1.NodeModuleSpec{
 			ModuleItem: kmmv1beta1.ModuleItem{
@@ -71,6 +90,15 @@ func (h *helper) SetModuleConfig(
 	foundEntry.ServiceAccountName = saName
 	foundEntry.Tolerations = mld.Tolerations
 	foundEntry.Version = mld.ModuleVersion
+	foundEntry.RetryPolicy = mld.RetryPolicy
+	foundEntry.RetryToken = mld.RetryToken
+	foundEntry.DrainPolicy = mld.DrainPolicy
+	foundEntry.MaintenanceWindows = mld.MaintenanceWindows
+	foundEntry.DependsOn = mld.DependsOn
+
+	if conflictErr != nil {
+		return conflictErr
+	}
 
 	return nil
 }
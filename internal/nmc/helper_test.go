@@ -2,11 +2,14 @@ package nmc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/api"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -31,7 +34,7 @@ var _ = Describe("Get", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		ctx = context.Background()
-		nmcHelper = NewHelper(clnt)
+		nmcHelper = NewHelper(clnt, "")
 		nmcName = "some name"
 	})
 
@@ -74,7 +77,7 @@ var _ = Describe("SetModuleConfig", func() {
 	var nmcHelper Helper
 
 	BeforeEach(func() {
-		nmcHelper = NewHelper(nil)
+		nmcHelper = NewHelper(nil, "")
 	})
 
 	const (
@@ -129,11 +132,22 @@ var _ = Describe("SetModuleConfig", func() {
 		}
 
 		moduleConfig := kmmv1beta1.ModuleConfig{InTreeModulesToRemove: []string{"in-tree-module1", "in-tree-module2"}}
+		retryPolicy := kmmv1beta1.RetryPolicy{MaxAttempts: 3}
+		drainPolicy := kmmv1beta1.DrainPolicy{Resources: []v1.ResourceName{"example.com/gpu"}}
+		windows := []kmmv1beta1.MaintenanceWindow{
+			{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		}
+		dependsOn := []kmmv1beta1.ModuleReference{{Name: "core", Namespace: namespace}}
 		mld := api.ModuleLoaderData{
 			Name:               name,
 			Namespace:          namespace,
 			ServiceAccountName: saName,
 			Tolerations:        []v1.Toleration{testToleration},
+			RetryPolicy:        &retryPolicy,
+			RetryToken:         "1",
+			DrainPolicy:        &drainPolicy,
+			MaintenanceWindows: windows,
+			DependsOn:          dependsOn,
 		}
 
 		err := nmcHelper.SetModuleConfig(&nmc, &mld, &moduleConfig)
@@ -143,6 +157,69 @@ var _ = Describe("SetModuleConfig", func() {
 		Expect(nmc.Spec.Modules[1].Config.InTreeModulesToRemove).To(Equal([]string{"in-tree-module1", "in-tree-module2"}))
 		Expect(nmc.Spec.Modules[1].ServiceAccountName).To(Equal(saName))
 		Expect(nmc.Spec.Modules[1].Tolerations).To(Equal([]v1.Toleration{testToleration}))
+		Expect(nmc.Spec.Modules[1].RetryPolicy).To(Equal(&retryPolicy))
+		Expect(nmc.Spec.Modules[1].RetryToken).To(Equal("1"))
+		Expect(nmc.Spec.Modules[1].DrainPolicy).To(Equal(&drainPolicy))
+		Expect(nmc.Spec.Modules[1].MaintenanceWindows).To(Equal(windows))
+		Expect(nmc.Spec.Modules[1].DependsOn).To(Equal(dependsOn))
+	})
+
+	Context("with another module using the same kernel module", func() {
+		var conflicting kmmv1beta1.NodeModulesConfig
+
+		moduleConfig := kmmv1beta1.ModuleConfig{Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "kmod"}}
+		mld := api.ModuleLoaderData{Name: name, Namespace: namespace}
+
+		BeforeEach(func() {
+			conflicting = kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "node"},
+				Spec: kmmv1beta1.NodeModulesConfigSpec{
+					Modules: []kmmv1beta1.NodeModuleSpec{
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{Name: "other", Namespace: "other-ns"},
+							Config:     kmmv1beta1.ModuleConfig{InTreeModulesToRemove: []string{"kmod"}},
+						},
+					},
+				},
+			}
+		})
+
+		It("should set the config and return a ConflictError with the Warn policy", func() {
+			err := NewHelper(nil, config.ConflictPolicyWarn).SetModuleConfig(&conflicting, &mld, &moduleConfig)
+
+			var conflictErr *ConflictError
+			Expect(errors.As(err, &conflictErr)).To(BeTrue())
+			Expect(conflictErr.Rejected).To(BeFalse())
+			Expect(conflictErr.Conflicts).To(Equal([]Conflict{{Namespace: "other-ns", Name: "other", KernelModule: "kmod"}}))
+			Expect(err.Error()).To(Equal("kernel modules also used on node node: kmod by Module other-ns/other"))
+			Expect(conflicting.Spec.Modules).To(HaveLen(2))
+		})
+
+		DescribeTable("should not set the config of a new module",
+			func(policy config.ConflictPolicy) {
+				err := NewHelper(nil, policy).SetModuleConfig(&conflicting, &mld, &moduleConfig)
+
+				var conflictErr *ConflictError
+				Expect(errors.As(err, &conflictErr)).To(BeTrue())
+				Expect(conflictErr.Rejected).To(BeTrue())
+				Expect(conflicting.Spec.Modules).To(HaveLen(1))
+			},
+			Entry("Reject policy", config.ConflictPolicyReject),
+			Entry("FirstWins policy", config.ConflictPolicyFirstWins),
+		)
+
+		It("should update the config of a module that is already configured with the FirstWins policy", func() {
+			conflicting.Spec.Modules = append(conflicting.Spec.Modules, kmmv1beta1.NodeModuleSpec{
+				ModuleItem: kmmv1beta1.ModuleItem{Name: name, Namespace: namespace},
+			})
+
+			err := NewHelper(nil, config.ConflictPolicyFirstWins).SetModuleConfig(&conflicting, &mld, &moduleConfig)
+
+			var conflictErr *ConflictError
+			Expect(errors.As(err, &conflictErr)).To(BeTrue())
+			Expect(conflictErr.Rejected).To(BeFalse())
+			Expect(conflicting.Spec.Modules[1].Config).To(Equal(moduleConfig))
+		})
 	})
 })
 
@@ -150,7 +227,7 @@ var _ = Describe("RemoveModuleConfig", func() {
 	var nmcHelper Helper
 
 	BeforeEach(func() {
-		nmcHelper = NewHelper(nil)
+		nmcHelper = NewHelper(nil, "")
 	})
 
 	namespace := "test_namespace"
@@ -217,7 +294,7 @@ var _ = Describe("GetModuleSpecEntry", func() {
 	)
 
 	BeforeEach(func() {
-		nmcHelper = NewHelper(nil)
+		nmcHelper = NewHelper(nil, "")
 	})
 
 	It("empty module spec list", func() {
@@ -289,7 +366,7 @@ var _ = Describe("GetModuleStatusEntry", func() {
 	)
 
 	BeforeEach(func() {
-		nmcHelper = NewHelper(nil)
+		nmcHelper = NewHelper(nil, "")
 	})
 
 	It("empty module status list", func() {
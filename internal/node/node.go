@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/meta"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -32,7 +33,14 @@ func NewNode(client client.Client) Node {
 }
 
 func (n *node) IsNodeSchedulable(node *v1.Node, tolerations []v1.Toleration) bool {
+	// KMM cordons nodes while it drains them before unloading modules; modules should still be managed on them.
+	cordonedByKMM := meta.HasAnnotation(node, constants.NodeCordonedAnnotation)
+
 	for _, taint := range node.Spec.Taints {
+		if cordonedByKMM && taint.Key == v1.TaintNodeUnschedulable {
+			continue
+		}
+
 		toleranceFound := false
 		for _, toleration := range tolerations {
 			if toleration.ToleratesTaint(klog.Background(), &taint, false) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -58,6 +59,25 @@ var _ = Describe("IsNodeSchedulable", func() {
 		Expect(isNodeSchedulable).To(BeTrue())
 
 	})
+	It("Returns true if the node was cordoned by KMM", func() {
+
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.NodeCordonedAnnotation: ""},
+			},
+			Spec: v1.NodeSpec{
+				Taints: []v1.Taint{
+					{
+						Key:    v1.TaintNodeUnschedulable,
+						Effect: v1.TaintEffectNoSchedule,
+					},
+				},
+			},
+		}
+		isNodeSchedulable = mn.IsNodeSchedulable(&node, nil)
+		Expect(isNodeSchedulable).To(BeTrue())
+
+	})
 })
 
 var _ = Describe("GetAllNodesBySelector", func() {
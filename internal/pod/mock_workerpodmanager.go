@@ -55,6 +55,20 @@ func (mr *MockWorkerPodManagerMockRecorder) CreateLoaderPod(ctx, nmc, nms any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoaderPod", reflect.TypeOf((*MockWorkerPodManager)(nil).CreateLoaderPod), ctx, nmc, nms)
 }
 
+// CreateSetParametersPod mocks base method.
+func (m *MockWorkerPodManager) CreateSetParametersPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSetParametersPod", ctx, nmc, nms)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSetParametersPod indicates an expected call of CreateSetParametersPod.
+func (mr *MockWorkerPodManagerMockRecorder) CreateSetParametersPod(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSetParametersPod", reflect.TypeOf((*MockWorkerPodManager)(nil).CreateSetParametersPod), ctx, nmc, nms)
+}
+
 // CreateUnloaderPod mocks base method.
 func (m *MockWorkerPodManager) CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleStatus) error {
 	m.ctrl.T.Helper()
@@ -168,6 +182,20 @@ func (mr *MockWorkerPodManagerMockRecorder) IsLoaderPod(p any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoaderPod", reflect.TypeOf((*MockWorkerPodManager)(nil).IsLoaderPod), p)
 }
 
+// IsSetParametersPod mocks base method.
+func (m *MockWorkerPodManager) IsSetParametersPod(p *v1.Pod) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSetParametersPod", p)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSetParametersPod indicates an expected call of IsSetParametersPod.
+func (mr *MockWorkerPodManagerMockRecorder) IsSetParametersPod(p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSetParametersPod", reflect.TypeOf((*MockWorkerPodManager)(nil).IsSetParametersPod), p)
+}
+
 // IsUnloaderPod mocks base method.
 func (m *MockWorkerPodManager) IsUnloaderPod(p *v1.Pod) bool {
 	m.ctrl.T.Helper()
@@ -212,6 +240,21 @@ func (mr *MockWorkerPodManagerMockRecorder) LoaderPodTemplate(ctx, nmc, nms any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoaderPodTemplate", reflect.TypeOf((*MockWorkerPodManager)(nil).LoaderPodTemplate), ctx, nmc, nms)
 }
 
+// SetParametersPodTemplate mocks base method.
+func (m *MockWorkerPodManager) SetParametersPodTemplate(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParametersPodTemplate", ctx, nmc, nms)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetParametersPodTemplate indicates an expected call of SetParametersPodTemplate.
+func (mr *MockWorkerPodManagerMockRecorder) SetParametersPodTemplate(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParametersPodTemplate", reflect.TypeOf((*MockWorkerPodManager)(nil).SetParametersPodTemplate), ctx, nmc, nms)
+}
+
 // UnloaderPodTemplate mocks base method.
 func (m *MockWorkerPodManager) UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleStatus) (*v1.Pod, error) {
 	m.ctrl.T.Helper()
@@ -31,13 +31,16 @@ import (
 
 type WorkerPodManager interface {
 	CreateLoaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error
+	CreateSetParametersPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error
 	CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) error
 	DeletePod(ctx context.Context, pod *v1.Pod) error
 	GetWorkerPod(ctx context.Context, podName, namespace string) (*v1.Pod, error)
 	ListWorkerPodsOnNode(ctx context.Context, nodeName string) ([]v1.Pod, error)
 	LoaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error)
+	SetParametersPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error)
 	UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error)
 	IsLoaderPod(p *v1.Pod) bool
+	IsSetParametersPod(p *v1.Pod) bool
 	IsUnloaderPod(p *v1.Pod) bool
 	GetConfigAnnotation(p *v1.Pod) string
 	HashAnnotationDiffer(p1, p2 *v1.Pod) bool
@@ -58,7 +61,9 @@ const (
 	volumeNameConfig           = "config"
 	initContainerName          = "image-extractor"
 	modulesOrderKey            = "kmm.node.kubernetes.io/modules-order"
+	modprobeConfigMountPath    = "/host/etc/modprobe.d"
 	workerActionLoad           = "Load"
+	workerActionSetParameters  = "SetParameters"
 	workerActionUnload         = "Unload"
 	actionLabelKey             = "kmm.node.kubernetes.io/worker-action"
 	configAnnotationKey        = "kmm.node.kubernetes.io/worker-config"
@@ -103,6 +108,15 @@ func (wpmi *workerPodManagerImpl) CreateLoaderPod(ctx context.Context, nmcObj cl
 	return wpmi.client.Create(ctx, pod)
 }
 
+func (wpmi *workerPodManagerImpl) CreateSetParametersPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error {
+	pod, err := wpmi.SetParametersPodTemplate(ctx, nmc, nms)
+	if err != nil {
+		return fmt.Errorf("could not create the Pod template: %v", err)
+	}
+
+	return wpmi.client.Create(ctx, pod)
+}
+
 func (wpmi *workerPodManagerImpl) CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) error {
 	pod, err := wpmi.UnloaderPodTemplate(ctx, nmc, nms)
 	if err != nil {
@@ -174,6 +188,10 @@ func (wpmi *workerPodManagerImpl) LoaderPodTemplate(ctx context.Context, nmc cli
 
 	args := []string{"kmod", "load", configFullPath}
 
+	if wpmi.workerCfg.NativeLoader {
+		args = append(args, "--"+worker.FlagNativeLoader)
+	}
+
 	privileged := false
 	if nms.Config.Modprobe.FirmwarePath != "" {
 
@@ -182,11 +200,17 @@ func (wpmi *workerPodManagerImpl) LoaderPodTemplate(ctx context.Context, nmc cli
 			return nil, fmt.Errorf("firmwareHostPath wasn't set, while the Module requires firmware loading")
 		}
 
-		args = append(args, "--"+worker.FlagFirmwarePath, *firmwareHostPath)
+		args = append(
+			args,
+			"--"+worker.FlagFirmwarePath,
+			*firmwareHostPath,
+			"--"+worker.FlagFirmwareOwner,
+			firmwareOwner(&nms.ModuleItem),
+		)
 
 		firmwarePathContainerImg := filepath.Join(nms.Config.Modprobe.FirmwarePath, "*")
 		firmwarePathWorkerImg := filepath.Join(sharedFilesDir, nms.Config.Modprobe.FirmwarePath)
-		if err = addCopyCommand(pod, firmwarePathContainerImg, firmwarePathWorkerImg); err != nil {
+		if err = wpmi.copyFromImage(pod, firmwarePathContainerImg, firmwarePathWorkerImg); err != nil {
 			return nil, fmt.Errorf("could not add the copy command to the init container: %v", err)
 		}
 
@@ -197,6 +221,26 @@ func (wpmi *workerPodManagerImpl) LoaderPodTemplate(ctx context.Context, nmc cli
 		privileged = true
 	}
 
+	if blacklistInTreeModules(&nms.Config) {
+		if err = setModprobeConfigVolume(pod); err != nil {
+			return nil, fmt.Errorf("could not mount the host modprobe configuration directory: %v", err)
+		}
+
+		args = append(args, "--"+worker.FlagBlacklistPath, blacklistPath(&nms.ModuleItem))
+	}
+
+	if hooks := nms.Config.Modprobe.Hooks; hooks != nil {
+		if err = wpmi.addHookCopyCommands(pod, hooks.PreLoad, hooks.PostLoad); err != nil {
+			return nil, fmt.Errorf("could not add the hooks to the init container: %v", err)
+		}
+	}
+
+	if vs := nms.Config.Modprobe.VerifySignature; vs != nil {
+		if err = setSignatureCertVolume(pod, vs.CertSecret.Name); err != nil {
+			return nil, fmt.Errorf("could not mount the signature verification certificate: %v", err)
+		}
+	}
+
 	if err = setWorkerConfigAnnotation(pod, nms.Config); err != nil {
 		return nil, fmt.Errorf("could not set worker config: %v", err)
 	}
@@ -219,6 +263,39 @@ func (wpmi *workerPodManagerImpl) LoaderPodTemplate(ctx context.Context, nmc cli
 	return pod, setHashAnnotation(pod)
 }
 
+// SetParametersPodTemplate returns a worker Pod that changes the parameters of an already loaded module to the ones in
+// nms, without reloading it.
+func (wpmi *workerPodManagerImpl) SetParametersPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error) {
+	pod, err := wpmi.baseWorkerPod(ctx, nmc, &nms.ModuleItem, &nms.Config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the base Pod: %v", err)
+	}
+
+	args := []string{"kmod", "set-params", configFullPath}
+
+	if err = setWorkerConfigAnnotation(pod, nms.Config); err != nil {
+		return nil, fmt.Errorf("could not set worker config: %v", err)
+	}
+	if err = setWorkerTolerationsAnnotation(pod, nms.Tolerations); err != nil {
+		return nil, fmt.Errorf("could not set worker tolerations: %v", err)
+	}
+
+	setWorkerModuleVersionAnnotation(pod, nms.Version)
+
+	// /sys is only mounted read-write in privileged containers.
+	if err = setWorkerSecurityContext(pod, wpmi.workerCfg, true); err != nil {
+		return nil, fmt.Errorf("could not set the worker Pod as privileged: %v", err)
+	}
+
+	if err = setWorkerContainerArgs(pod, args); err != nil {
+		return nil, fmt.Errorf("could not set worker container args: %v", err)
+	}
+
+	meta.SetLabel(pod, actionLabelKey, workerActionSetParameters)
+
+	return pod, setHashAnnotation(pod)
+}
+
 func (wpmi *workerPodManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error) {
 	pod, err := wpmi.baseWorkerPod(ctx, nmc, &nms.ModuleItem, &nms.Config)
 	if err != nil {
@@ -227,6 +304,10 @@ func (wpmi *workerPodManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc c
 
 	args := []string{"kmod", "unload", configFullPath}
 
+	if wpmi.workerCfg.NativeLoader {
+		args = append(args, "--"+worker.FlagNativeLoader)
+	}
+
 	if err = setWorkerConfigAnnotation(pod, nms.Config); err != nil {
 		return nil, fmt.Errorf("could not set worker config: %v", err)
 	}
@@ -234,7 +315,10 @@ func (wpmi *workerPodManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc c
 		return nil, fmt.Errorf("could not set worker tolerations: %v", err)
 	}
 
-	if err = setWorkerSecurityContext(pod, wpmi.workerCfg, false); err != nil {
+	// Livepatches are disabled through /sys, which is only mounted read-write in privileged containers.
+	privileged := nms.Config.Modprobe.Livepatch != nil
+
+	if err = setWorkerSecurityContext(pod, wpmi.workerCfg, privileged); err != nil {
 		return nil, fmt.Errorf("could not set the worker Pod's security context: %v", err)
 	}
 
@@ -244,16 +328,36 @@ func (wpmi *workerPodManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc c
 		}
 	}
 
+	if blacklistInTreeModules(&nms.Config) {
+		if err = setModprobeConfigVolume(pod); err != nil {
+			return nil, fmt.Errorf("could not mount the host modprobe configuration directory: %v", err)
+		}
+
+		args = append(args, "--"+worker.FlagBlacklistPath, blacklistPath(&nms.ModuleItem))
+	}
+
+	if hooks := nms.Config.Modprobe.Hooks; hooks != nil {
+		if err = wpmi.addHookCopyCommands(pod, hooks.PreUnload, hooks.PostUnload); err != nil {
+			return nil, fmt.Errorf("could not add the hooks to the init container: %v", err)
+		}
+	}
+
 	if nms.Config.Modprobe.FirmwarePath != "" {
 		firmwareHostPath := wpmi.workerCfg.FirmwareHostPath
 		if firmwareHostPath == nil {
 			return nil, fmt.Errorf("firmwareHostPath was not set while the Module requires firmware unloading")
 		}
-		args = append(args, "--"+worker.FlagFirmwarePath, *firmwareHostPath)
+		args = append(
+			args,
+			"--"+worker.FlagFirmwarePath,
+			*firmwareHostPath,
+			"--"+worker.FlagFirmwareOwner,
+			firmwareOwner(&nms.ModuleItem),
+		)
 
 		firmwarePathContainerImg := filepath.Join(nms.Config.Modprobe.FirmwarePath, "*")
 		firmwarePathWorkerImg := filepath.Join(sharedFilesDir, nms.Config.Modprobe.FirmwarePath)
-		if err = addCopyCommand(pod, firmwarePathContainerImg, firmwarePathWorkerImg); err != nil {
+		if err = wpmi.copyFromImage(pod, firmwarePathContainerImg, firmwarePathWorkerImg); err != nil {
 			return nil, fmt.Errorf("could not add the copy command to the init container: %v", err)
 		}
 
@@ -280,6 +384,15 @@ func (wpmi *workerPodManagerImpl) IsLoaderPod(p *v1.Pod) bool {
 	return p.Labels[actionLabelKey] == workerActionLoad
 }
 
+func (wpmi *workerPodManagerImpl) IsSetParametersPod(p *v1.Pod) bool {
+
+	if p == nil {
+		return false
+	}
+
+	return p.Labels[actionLabelKey] == workerActionSetParameters
+}
+
 func (wpmi *workerPodManagerImpl) IsUnloaderPod(p *v1.Pod) bool {
 
 	if p == nil {
@@ -438,6 +551,7 @@ func (wpmi *workerPodManagerImpl) baseWorkerPod(ctx context.Context, nmc client.
 						Requests: requests,
 						Limits:   limits,
 					},
+					TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
 				},
 			},
 			NodeName:           nodeName,
@@ -455,10 +569,16 @@ func (wpmi *workerPodManagerImpl) baseWorkerPod(ctx context.Context, nmc client.
 
 	kmodsPathContainerImg := filepath.Join(moduleConfig.Modprobe.DirName, "lib", "modules", moduleConfig.KernelVersion)
 	kmodsPathWorkerImg := filepath.Join(sharedFilesDir, moduleConfig.Modprobe.DirName, "lib", "modules")
-	if err := addCopyCommand(&pod, kmodsPathContainerImg, kmodsPathWorkerImg); err != nil {
+	if err := wpmi.copyFromImage(&pod, kmodsPathContainerImg, kmodsPathWorkerImg); err != nil {
 		return nil, fmt.Errorf("could not add the copy command to the init container: %v", err)
 	}
 
+	if wpmi.workerCfg.PullImages {
+		if err := wpmi.setImagePullerInitContainer(&pod, item.ImageRepoSecret); err != nil {
+			return nil, fmt.Errorf("could not set the image puller init container: %v", err)
+		}
+	}
+
 	controllerutil.AddFinalizer(&pod, NodeModulesConfigFinalizer)
 
 	return &pod, nil
@@ -523,6 +643,16 @@ func setWorkerSofdepConfig(pod *v1.Pod, modulesLoadingOrder []string) error {
 	return nil
 }
 
+// copyFromImage copies src from the module image to dst in the shared directory.
+// When the worker pulls images itself, it extracts all files it needs and this is a no-op.
+func (wpmi *workerPodManagerImpl) copyFromImage(pod *v1.Pod, src, dst string) error {
+	if wpmi.workerCfg.PullImages {
+		return nil
+	}
+
+	return addCopyCommand(pod, src, dst)
+}
+
 func addCopyCommand(pod *v1.Pod, src, dst string) error {
 
 	container, _ := podcmd.FindContainerByName(pod, initContainerName)
@@ -540,6 +670,146 @@ cp -R %s %s;
 	return nil
 }
 
+// addHookCopyCommands copies the executables of all non-nil hooks from the module image to the shared directory,
+// where the worker runs them from.
+func (wpmi *workerPodManagerImpl) addHookCopyCommands(pod *v1.Pod, hooks ...*kmmv1beta1.ModprobeHook) error {
+	for _, h := range hooks {
+		if h == nil || len(h.Command) == 0 {
+			continue
+		}
+
+		dst := filepath.Dir(filepath.Join(sharedFilesDir, h.Command[0]))
+
+		if err := wpmi.copyFromImage(pod, h.Command[0], dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setImagePullerInitContainer replaces the init container with one that runs the worker to pull the module image
+// and extract the files that the worker needs into the shared directory.
+// Extracted files are cached on the host by image digest.
+func (wpmi *workerPodManagerImpl) setImagePullerInitContainer(pod *v1.Pod, pullSecret *v1.LocalObjectReference) error {
+	const (
+		volNameImages      = "images"
+		volNamePullSecrets = "pull-secrets"
+	)
+
+	container, _ := podcmd.FindContainerByName(pod, initContainerName)
+	if container == nil {
+		return errors.New("could not find the init container")
+	}
+
+	container.Image = wpmi.workerImage
+	container.ImagePullPolicy = ""
+	container.Command = nil
+	container.Args = []string{"image", "pull", configFullPath}
+	container.SecurityContext = &v1.SecurityContext{
+		RunAsUser:      wpmi.workerCfg.RunAsUser,
+		SELinuxOptions: &v1.SELinuxOptions{Type: wpmi.workerCfg.SELinuxType},
+	}
+	container.VolumeMounts = append(
+		container.VolumeMounts,
+		v1.VolumeMount{
+			Name:      volNameConfig,
+			MountPath: volMountPointConfig,
+			ReadOnly:  true,
+		},
+		v1.VolumeMount{
+			Name:      volNameImages,
+			MountPath: worker.ImagesDir,
+		},
+	)
+
+	pod.Spec.Volumes = append(
+		pod.Spec.Volumes,
+		v1.Volume{
+			Name: volNameImages,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: worker.ImagesDir,
+					Type: ptr.To(v1.HostPathDirectoryOrCreate),
+				},
+			},
+		},
+	)
+
+	if pullSecret != nil {
+		pod.Spec.Volumes = append(
+			pod.Spec.Volumes,
+			v1.Volume{
+				Name: volNamePullSecrets,
+				VolumeSource: v1.VolumeSource{
+					Secret: &v1.SecretVolumeSource{SecretName: pullSecret.Name},
+				},
+			},
+		)
+
+		container.VolumeMounts = append(
+			container.VolumeMounts,
+			v1.VolumeMount{
+				Name:      volNamePullSecrets,
+				MountPath: filepath.Join(worker.PullSecretsDir, pullSecret.Name),
+				ReadOnly:  true,
+			},
+		)
+	}
+
+	return nil
+}
+
+// blacklistInTreeModules returns true if the worker should blacklist the in-tree modules to remove on the node.
+func blacklistInTreeModules(cfg *kmmv1beta1.ModuleConfig) bool {
+	return cfg.BlacklistInTreeModules && (len(cfg.InTreeModulesToRemove) > 0 || cfg.InTreeModuleToRemove != "")
+}
+
+// blacklistPath returns the path, in the worker container, of the modprobe configuration file that blacklists the
+// in-tree modules replaced by item.
+func blacklistPath(item *kmmv1beta1.ModuleItem) string {
+	return filepath.Join(modprobeConfigMountPath, fmt.Sprintf("kmm-%s-%s.conf", item.Namespace, item.Name))
+}
+
+// setModprobeConfigVolume mounts the host modprobe configuration directory in the worker container.
+// It is not mounted at /etc/modprobe.d, which may hold the soft dependencies of the module.
+func setModprobeConfigVolume(pod *v1.Pod) error {
+	const volNameModprobeConfig = "host-modprobe-d"
+
+	container, _ := podcmd.FindContainerByName(pod, WorkerContainerName)
+	if container == nil {
+		return errors.New("could not find the worker container")
+	}
+
+	pod.Spec.Volumes = append(
+		pod.Spec.Volumes,
+		v1.Volume{
+			Name: volNameModprobeConfig,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: "/etc/modprobe.d",
+					Type: ptr.To(v1.HostPathDirectoryOrCreate),
+				},
+			},
+		},
+	)
+
+	container.VolumeMounts = append(
+		container.VolumeMounts,
+		v1.VolumeMount{
+			Name:      volNameModprobeConfig,
+			MountPath: modprobeConfigMountPath,
+		},
+	)
+
+	return nil
+}
+
+// firmwareOwner identifies the Module on behalf of which the worker installs firmware files on the node.
+func firmwareOwner(item *kmmv1beta1.ModuleItem) string {
+	return item.Namespace + "/" + item.Name
+}
+
 func setFirmwareVolume(pod *v1.Pod, firmwareHostPath *string) error {
 
 	const volNameVarLibFirmware = "lib-firmware"
@@ -574,6 +844,42 @@ func setFirmwareVolume(pod *v1.Pod, firmwareHostPath *string) error {
 	return nil
 }
 
+func setSignatureCertVolume(pod *v1.Pod, secretName string) error {
+
+	const volNameSignatureCert = "signature-cert"
+
+	container, _ := podcmd.FindContainerByName(pod, WorkerContainerName)
+	if container == nil {
+		return errors.New("could not find the worker container")
+	}
+
+	volume := v1.Volume{
+		Name: volNameSignatureCert,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []v1.KeyToPath{
+					{
+						Key:  constants.PublicSignDataKey,
+						Path: filepath.Base(worker.SignatureCertPath),
+					},
+				},
+			},
+		},
+	}
+
+	volumeMount := v1.VolumeMount{
+		Name:      volNameSignatureCert,
+		MountPath: filepath.Dir(worker.SignatureCertPath),
+		ReadOnly:  true,
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+	container.VolumeMounts = append(container.VolumeMounts, volumeMount)
+
+	return nil
+}
+
 func setWorkerSecurityContext(pod *v1.Pod, workerCfg *config.Worker, privileged bool) error {
 	container, _ := podcmd.FindContainerByName(pod, WorkerContainerName)
 	if container == nil {
@@ -242,6 +242,209 @@ var _ = Describe("CreateLoaderPod", func() {
 		Entry("firmwareHostPath set, firmware loading not requested", ptr.To("some-path"), false),
 		Entry("firmwareHostPath set , firmware loading requested", ptr.To("some-path"), true),
 	)
+
+	It("should pass the native-loader flag to the worker if enabled", func() {
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		workerCfg := *workerCfg
+		workerCfg.NativeLoader = true
+
+		kli := &workerPodManagerImpl{
+			client:      client,
+			scheme:      scheme,
+			workerImage: workerImage,
+			workerCfg:   &workerCfg,
+		}
+
+		pod, err := kli.LoaderPodTemplate(ctx, nmc, nms)
+		Expect(err).NotTo(HaveOccurred())
+
+		container, _ := podcmd.FindContainerByName(pod, "worker")
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args).To(Equal([]string{"kmod", "load", "/etc/kmm-worker/config.yaml", "--native-loader"}))
+	})
+
+	It("should pass the blacklist path to the worker if configured", func() {
+		moduleConfigToUse.BlacklistInTreeModules = true
+
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		kli := &workerPodManagerImpl{
+			client:      client,
+			scheme:      scheme,
+			workerImage: workerImage,
+			workerCfg:   workerCfg,
+		}
+
+		pod, err := kli.LoaderPodTemplate(ctx, nmc, nms)
+		Expect(err).NotTo(HaveOccurred())
+
+		container, _ := podcmd.FindContainerByName(pod, "worker")
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args).To(
+			Equal([]string{
+				"kmod",
+				"load",
+				"/etc/kmm-worker/config.yaml",
+				"--blacklist-path",
+				"/host/etc/modprobe.d/kmm-namespace-my-module.conf",
+			}),
+		)
+		Expect(container.VolumeMounts).To(
+			ContainElement(v1.VolumeMount{Name: "host-modprobe-d", MountPath: "/host/etc/modprobe.d"}),
+		)
+		Expect(pod.Spec.Volumes).To(
+			ContainElement(v1.Volume{
+				Name: "host-modprobe-d",
+				VolumeSource: v1.VolumeSource{
+					HostPath: &v1.HostPathVolumeSource{
+						Path: "/etc/modprobe.d",
+						Type: ptr.To(v1.HostPathDirectoryOrCreate),
+					},
+				},
+			}),
+		)
+	})
+
+	It("should copy the load hooks from the module image", func() {
+		moduleConfigToUse.Modprobe.Hooks = &kmmv1beta1.ModprobeHooks{
+			PreLoad:   &kmmv1beta1.ModprobeHook{Command: []string{"/usr/local/bin/pre-load.sh", "arg"}},
+			PostLoad:  &kmmv1beta1.ModprobeHook{Command: []string{"/opt/hooks/post-load"}},
+			PreUnload: &kmmv1beta1.ModprobeHook{Command: []string{"/opt/hooks/pre-unload"}},
+		}
+
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		kli := &workerPodManagerImpl{
+			client:      client,
+			scheme:      scheme,
+			workerImage: workerImage,
+			workerCfg:   workerCfg,
+		}
+
+		pod, err := kli.LoaderPodTemplate(ctx, nmc, nms)
+		Expect(err).NotTo(HaveOccurred())
+
+		container, _ := podcmd.FindContainerByName(pod, initContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args[0]).To(
+			And(
+				ContainSubstring("mkdir -p /tmp/usr/local/bin;\ncp -R /usr/local/bin/pre-load.sh /tmp/usr/local/bin;"),
+				ContainSubstring("mkdir -p /tmp/opt/hooks;\ncp -R /opt/hooks/post-load /tmp/opt/hooks;"),
+				Not(ContainSubstring("pre-unload")),
+			),
+		)
+	})
+
+	It("should pull the module image with the worker if enabled", func() {
+		moduleConfigToUse.Modprobe.Hooks = &kmmv1beta1.ModprobeHooks{
+			PreLoad: &kmmv1beta1.ModprobeHook{Command: []string{"/usr/local/bin/pre-load.sh"}},
+		}
+
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		workerCfg := *workerCfg
+		workerCfg.PullImages = true
+
+		kli := &workerPodManagerImpl{
+			client:      client,
+			scheme:      scheme,
+			workerImage: workerImage,
+			workerCfg:   &workerCfg,
+		}
+
+		pod, err := kli.LoaderPodTemplate(ctx, nmc, nms)
+		Expect(err).NotTo(HaveOccurred())
+
+		container, _ := podcmd.FindContainerByName(pod, initContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Image).To(Equal(workerImage))
+		Expect(container.Command).To(BeEmpty())
+		Expect(container.Args).To(Equal([]string{"image", "pull", "/etc/kmm-worker/config.yaml"}))
+		Expect(container.VolumeMounts).To(
+			ContainElements(
+				v1.VolumeMount{Name: "tmp", MountPath: "/tmp"},
+				v1.VolumeMount{Name: "config", MountPath: "/etc/kmm-worker", ReadOnly: true},
+				v1.VolumeMount{Name: "images", MountPath: "/var/run/kmm/images"},
+				v1.VolumeMount{Name: "pull-secrets", MountPath: "/var/run/kmm/pull-secrets/" + irsName, ReadOnly: true},
+			),
+		)
+
+		Expect(pod.Spec.Volumes).To(
+			ContainElements(
+				v1.Volume{
+					Name: "images",
+					VolumeSource: v1.VolumeSource{
+						HostPath: &v1.HostPathVolumeSource{
+							Path: "/var/run/kmm/images",
+							Type: ptr.To(v1.HostPathDirectoryOrCreate),
+						},
+					},
+				},
+				v1.Volume{
+					Name: "pull-secrets",
+					VolumeSource: v1.VolumeSource{
+						Secret: &v1.SecretVolumeSource{SecretName: irsName},
+					},
+				},
+			),
+		)
+	})
+
+	It("should mount the signature verification certificate if configured", func() {
+		moduleConfigToUse.Modprobe.VerifySignature = &kmmv1beta1.ModuleSignatureVerification{
+			CertSecret: v1.LocalObjectReference{Name: "signing-cert"},
+		}
+
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		kli := &workerPodManagerImpl{
+			client:      client,
+			scheme:      scheme,
+			workerImage: workerImage,
+			workerCfg:   workerCfg,
+		}
+
+		pod, err := kli.LoaderPodTemplate(ctx, nmc, nms)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pod.Spec.Volumes).To(
+			ContainElement(v1.Volume{
+				Name: "signature-cert",
+				VolumeSource: v1.VolumeSource{
+					Secret: &v1.SecretVolumeSource{
+						SecretName: "signing-cert",
+						Items:      []v1.KeyToPath{{Key: "cert", Path: "cert"}},
+					},
+				},
+			}),
+		)
+
+		container, _ := podcmd.FindContainerByName(pod, "worker")
+		Expect(container).NotTo(BeNil())
+		Expect(container.VolumeMounts).To(
+			ContainElement(v1.VolumeMount{
+				Name:      "signature-cert",
+				MountPath: "/var/run/kmm/signature-cert",
+				ReadOnly:  true,
+			}),
+		)
+	})
 })
 
 var _ = Describe("CreateUnloaderPod", func() {
@@ -337,6 +540,97 @@ var _ = Describe("CreateUnloaderPod", func() {
 	})
 })
 
+var _ = Describe("UnloaderPodTemplate", func() {
+	It("should run a privileged worker to unload a livepatch", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config: kmmv1beta1.ModuleConfig{
+				KernelVersion: "kernel-version",
+				Modprobe: kmmv1beta1.ModprobeSpec{
+					ModuleName: "test",
+					Livepatch:  &kmmv1beta1.Livepatch{},
+				},
+			},
+		}
+
+		wpm := NewWorkerPodManager(nil, workerImage, scheme, workerCfg)
+
+		pod, err := wpm.UnloaderPodTemplate(context.TODO(), nmc, status)
+		Expect(err).NotTo(HaveOccurred())
+
+		container, _ := podcmd.FindContainerByName(pod, WorkerContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.SecurityContext).To(Equal(&v1.SecurityContext{Privileged: ptr.To(true)}))
+	})
+	It("should copy the unload hooks from the module image", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		moduleConfigToUse := moduleConfig
+		moduleConfigToUse.Modprobe.Hooks = &kmmv1beta1.ModprobeHooks{
+			PreLoad:    &kmmv1beta1.ModprobeHook{Command: []string{"/opt/hooks/pre-load"}},
+			PreUnload:  &kmmv1beta1.ModprobeHook{Command: []string{"/usr/local/bin/pre-unload.sh", "arg"}},
+			PostUnload: &kmmv1beta1.ModprobeHook{Command: []string{"/opt/hooks/post-unload"}},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: moduleName, Namespace: namespace},
+			Config:     moduleConfigToUse,
+		}
+
+		wpm := NewWorkerPodManager(nil, workerImage, scheme, workerCfg)
+
+		pod, err := wpm.UnloaderPodTemplate(context.TODO(), nmc, status)
+		Expect(err).NotTo(HaveOccurred())
+
+		container, _ := podcmd.FindContainerByName(pod, initContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args[0]).To(
+			And(
+				ContainSubstring("mkdir -p /tmp/usr/local/bin;\ncp -R /usr/local/bin/pre-unload.sh /tmp/usr/local/bin;"),
+				ContainSubstring("mkdir -p /tmp/opt/hooks;\ncp -R /opt/hooks/post-unload /tmp/opt/hooks;"),
+				Not(ContainSubstring("pre-load")),
+			),
+		)
+	})
+})
+
+var _ = Describe("SetParametersPodTemplate", func() {
+	It("should run the set-params command in a privileged worker", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      moduleName,
+				Namespace: namespace,
+				Version:   "some-version",
+			},
+			Config: moduleConfig,
+		}
+
+		wpm := NewWorkerPodManager(nil, workerImage, scheme, workerCfg)
+
+		pod, err := wpm.SetParametersPodTemplate(context.TODO(), nmc, nms)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wpm.IsSetParametersPod(pod)).To(BeTrue())
+		Expect(wpm.IsLoaderPod(pod)).To(BeFalse())
+		Expect(wpm.GetModuleVersionAnnotation(pod)).To(Equal("some-version"))
+		Expect(pod.Annotations).To(HaveKey(hashAnnotationKey))
+
+		container, _ := podcmd.FindContainerByName(pod, "worker")
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args).To(Equal([]string{"kmod", "set-params", "/etc/kmm-worker/config.yaml"}))
+		Expect(container.SecurityContext).To(Equal(&v1.SecurityContext{Privileged: ptr.To(true)}))
+	})
+})
+
 var _ = Describe("DeletePod", func() {
 	ctx := context.TODO()
 	now := metav1.Now()
@@ -478,7 +772,7 @@ cp -R /firmware-path/* /tmp/firmware-path;
 
 	args := []string{"kmod", subcommand, "/etc/kmm-worker/config.yaml"}
 	if withFirmware {
-		args = append(args, "--firmware-path", *firmwareHostPath)
+		args = append(args, "--firmware-path", *firmwareHostPath, "--firmware-owner", namespace+"/"+moduleName)
 		initContainerArg = strings.Join([]string{initContainerArg, initContainerArgFirmwareAddition}, "")
 	} else {
 		configAnnotationValue = strings.ReplaceAll(configAnnotationValue, "firmwarePath: /firmware-path\n  ", "")
@@ -537,7 +831,8 @@ cp -R /firmware-path/* /tmp/firmware-path;
 						Limits:   limits,
 						Requests: requests,
 					},
-					SecurityContext: &v1.SecurityContext{},
+					SecurityContext:          &v1.SecurityContext{},
+					TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
 					VolumeMounts: []v1.VolumeMount{
 						{
 							Name:      volNameConfig,
@@ -37,7 +37,8 @@ type ManagedClusterModuleValidator struct {
 func NewManagedClusterModuleValidator(logger logr.Logger, kubeVersion *webhook.KubeVersion) *ManagedClusterModuleValidator {
 	return &ManagedClusterModuleValidator{
 		logger: logger,
-		m:      webhook.NewModuleValidator(logger, kubeVersion),
+		// the Modules are created on spoke clusters, where their dependencies and conflicting Modules cannot be looked up
+		m: webhook.NewModuleValidator(logger, kubeVersion, nil, ""),
 	}
 }
 
@@ -22,20 +22,31 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/go-logr/logr"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/constants"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/maintenance"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/module"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -54,10 +65,19 @@ type KubeVersion struct {
 type ModuleValidator struct {
 	logger      logr.Logger
 	kubeVersion *KubeVersion
+	// reader is used to look up the dependencies of Modules and the Modules that use the same kernel modules;
+	// dependency cycles spanning several Modules and conflicts are not detected if it is nil.
+	reader client.Reader
+	// conflictPolicy tells if Modules that conflict with existing ones are rejected or only warned about.
+	conflictPolicy config.ConflictPolicy
 }
 
-func NewModuleValidator(logger logr.Logger, kubeVersion *KubeVersion) *ModuleValidator {
-	return &ModuleValidator{logger: logger, kubeVersion: kubeVersion}
+func NewModuleValidator(
+	logger logr.Logger,
+	kubeVersion *KubeVersion,
+	reader client.Reader,
+	conflictPolicy config.ConflictPolicy) *ModuleValidator {
+	return &ModuleValidator{logger: logger, kubeVersion: kubeVersion, reader: reader, conflictPolicy: conflictPolicy}
 }
 
 // DiscoverKubeVersion queries the Kubernetes API server and returns its version.
@@ -113,7 +133,18 @@ func (m *ModuleValidator) ValidateCreate(ctx context.Context, obj runtime.Object
 
 	m.logger.Info("Validating Module creation", "name", mod.Name, "namespace", mod.Namespace)
 
-	return validateModule(mod, m.kubeVersion)
+	warnings, err := validateModule(mod, m.kubeVersion)
+	if err != nil {
+		return warnings, err
+	}
+
+	if err = m.validateDependencyCycles(ctx, mod); err != nil {
+		return warnings, err
+	}
+
+	conflictWarnings, err := m.validateConflicts(ctx, mod)
+
+	return append(warnings, conflictWarnings...), err
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -137,7 +168,18 @@ func (m *ModuleValidator) ValidateUpdate(ctx context.Context, oldObj, newObj run
 		}
 	}
 
-	return validateModule(newMod, m.kubeVersion)
+	warnings, err := validateModule(newMod, m.kubeVersion)
+	if err != nil {
+		return warnings, err
+	}
+
+	if err = m.validateDependencyCycles(ctx, newMod); err != nil {
+		return warnings, err
+	}
+
+	conflictWarnings, err := m.validateConflicts(ctx, newMod)
+
+	return append(warnings, conflictWarnings...), err
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -168,6 +210,14 @@ func validateModule(mod *kmmv1beta1.Module, kubeVersion *KubeVersion) (admission
 		return nil, fmt.Errorf("failed to validate device plugin volumes: %v", err)
 	}
 
+	if err := validateUpgradeStrategy(mod.Spec.UpgradeStrategy); err != nil {
+		return nil, fmt.Errorf("failed to validate the upgrade strategy: %v", err)
+	}
+
+	if err := validateDependsOn(mod); err != nil {
+		return nil, fmt.Errorf("failed to validate dependencies: %v", err)
+	}
+
 	if mod.Spec.ModuleLoader == nil {
 		// If ModuleLoader is nil, there is no need to validate related fields
 		return nil, nil
@@ -181,6 +231,10 @@ func validateModule(mod *kmmv1beta1.Module, kubeVersion *KubeVersion) (admission
 		return nil, fmt.Errorf("failed to validate modprobe: %v", err)
 	}
 
+	if err := validateMaintenanceWindows(mod.Spec.ModuleLoader.MaintenanceWindows); err != nil {
+		return nil, fmt.Errorf("failed to validate the maintenance windows: %v", err)
+	}
+
 	return nil, validateFilesToSign(mod.Spec.ModuleLoader.Container)
 }
 
@@ -326,6 +380,40 @@ func validateModprobe(modprobe kmmv1beta1.ModprobeSpec) error {
 		return errors.New("load and unload rawArgs must be set when moduleName is unset")
 	}
 
+	if modprobe.VerifySignature != nil {
+		if !moduleNameDefined {
+			return errors.New("verifySignature can only be set when moduleName is set")
+		}
+
+		if modprobe.VerifySignature.CertSecret.Name == "" {
+			return errors.New("verifySignature.certSecret.name must be set")
+		}
+	}
+
+	if err := validateHooks(modprobe.Hooks); err != nil {
+		return err
+	}
+
+	if up := modprobe.UnloadPolicy; up != nil {
+		if !moduleNameDefined {
+			return errors.New("unloadPolicy can only be set when moduleName is set")
+		}
+
+		if up.TimeoutSeconds != 0 && up.Type != kmmv1beta1.UnloadPolicyWait {
+			return errors.New("unloadPolicy.timeoutSeconds can only be set with the Wait type")
+		}
+	}
+
+	if modprobe.Livepatch != nil {
+		if !moduleNameDefined {
+			return errors.New("livepatch can only be set when moduleName is set")
+		}
+
+		if up := modprobe.UnloadPolicy; up != nil && up.Type == kmmv1beta1.UnloadPolicyForce {
+			return errors.New("livepatch modules cannot be unloaded with the Force unload policy")
+		}
+	}
+
 	if modprobe.ModulesLoadingOrder != nil {
 		if len(modprobe.ModulesLoadingOrder) < 2 {
 			return errors.New("if a loading order is defined, at least two values must be defined")
@@ -353,6 +441,40 @@ func validateModprobe(modprobe kmmv1beta1.ModprobeSpec) error {
 	return nil
 }
 
+func validateHooks(hooks *kmmv1beta1.ModprobeHooks) error {
+	if hooks == nil {
+		return nil
+	}
+
+	named := []struct {
+		name string
+		hook *kmmv1beta1.ModprobeHook
+	}{
+		{name: "preLoad", hook: hooks.PreLoad},
+		{name: "postLoad", hook: hooks.PostLoad},
+		{name: "preUnload", hook: hooks.PreUnload},
+		{name: "postUnload", hook: hooks.PostUnload},
+	}
+
+	for _, n := range named {
+		name, hook := n.name, n.hook
+
+		if hook == nil {
+			continue
+		}
+
+		if len(hook.Command) == 0 {
+			return fmt.Errorf("hooks.%s.command must not be empty", name)
+		}
+
+		if !filepath.IsAbs(hook.Command[0]) {
+			return fmt.Errorf("hooks.%s.command[0] must be an absolute path in the module image", name)
+		}
+	}
+
+	return nil
+}
+
 func validateSignSection(sign *kmmv1beta1.Sign, dirName string) error {
 	if sign == nil {
 		return nil
@@ -386,6 +508,208 @@ func validateFilesToSign(container kmmv1beta1.ModuleLoaderContainerSpec) error {
 	return nil
 }
 
+func validateUpgradeStrategy(strategy *kmmv1beta1.UpgradeStrategy) error {
+	if strategy == nil {
+		return nil
+	}
+
+	if strategy.MaxUnavailable != nil {
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(strategy.MaxUnavailable, 100, false)
+		if err != nil {
+			return fmt.Errorf("invalid maxUnavailable: %v", err)
+		}
+
+		if maxUnavailable < 0 {
+			return fmt.Errorf("maxUnavailable cannot be negative: %s", strategy.MaxUnavailable.String())
+		}
+	}
+
+	canary := strategy.Canary
+	if canary == nil {
+		return nil
+	}
+
+	if canary.Nodes != nil {
+		if len(canary.NodeSelector) > 0 {
+			return errors.New("canary.nodes and canary.nodeSelector are mutually exclusive")
+		}
+
+		nodes, err := intstr.GetScaledValueFromIntOrPercent(canary.Nodes, 100, true)
+		if err != nil {
+			return fmt.Errorf("invalid canary.nodes: %v", err)
+		}
+
+		if nodes < 1 {
+			return fmt.Errorf("canary.nodes must be positive: %s", canary.Nodes.String())
+		}
+	}
+
+	if canary.Probe != nil {
+		if _, err := metav1.LabelSelectorAsSelector(canary.Probe); err != nil {
+			return fmt.Errorf("invalid canary.probe: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// dependencyName returns the namespaced name of the Module that ref points to, from a Module in namespace.
+func dependencyName(ref kmmv1beta1.ModuleReference, namespace string) types.NamespacedName {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+func validateDependsOn(mod *kmmv1beta1.Module) error {
+	self := types.NamespacedName{Namespace: mod.Namespace, Name: mod.Name}
+
+	seen := sets.New[types.NamespacedName]()
+
+	for _, ref := range mod.Spec.DependsOn {
+		nsn := dependencyName(ref, mod.Namespace)
+
+		if nsn == self {
+			return errors.New("a Module cannot depend on itself")
+		}
+
+		if seen.Has(nsn) {
+			return fmt.Errorf("duplicate dependency %s", nsn)
+		}
+
+		seen.Insert(nsn)
+	}
+
+	return nil
+}
+
+// validateDependencyCycles returns an error if one of the Modules that mod depends on, directly or not, depends on mod.
+// Dependencies that do not exist yet are ignored.
+func (m *ModuleValidator) validateDependencyCycles(ctx context.Context, mod *kmmv1beta1.Module) error {
+	if m.reader == nil || len(mod.Spec.DependsOn) == 0 {
+		return nil
+	}
+
+	self := types.NamespacedName{Namespace: mod.Namespace, Name: mod.Name}
+	visited := sets.New(self)
+
+	var visit func(nsn types.NamespacedName, dependsOn []kmmv1beta1.ModuleReference, path []string) error
+
+	visit = func(nsn types.NamespacedName, dependsOn []kmmv1beta1.ModuleReference, path []string) error {
+		for _, ref := range dependsOn {
+			dep := dependencyName(ref, nsn.Namespace)
+			depPath := append(slices.Clip(path), dep.String())
+
+			if dep == self {
+				return fmt.Errorf("dependency cycle: %s", strings.Join(depPath, " -> "))
+			}
+
+			if visited.Has(dep) {
+				continue
+			}
+
+			visited.Insert(dep)
+
+			depMod := kmmv1beta1.Module{}
+
+			if err := m.reader.Get(ctx, dep, &depMod); err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+
+				return fmt.Errorf("could not get Module %s: %v", dep, err)
+			}
+
+			if err := visit(dep, depMod.Spec.DependsOn, depPath); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return visit(self, mod.Spec.DependsOn, []string{self.String()})
+}
+
+// validateConflicts looks for other Modules that use the same kernel module as mod on some of the nodes that mod
+// targets.
+// Conflicts are returned as an error with the Reject policy, and as warnings otherwise.
+func (m *ModuleValidator) validateConflicts(ctx context.Context, mod *kmmv1beta1.Module) (admission.Warnings, error) {
+	if m.reader == nil || mod.Spec.ModuleLoader == nil {
+		return nil, nil
+	}
+
+	mods := kmmv1beta1.ModuleList{}
+
+	if err := m.reader.List(ctx, &mods); err != nil {
+		return nil, fmt.Errorf("could not list Modules: %v", err)
+	}
+
+	var (
+		nodes     *corev1.NodeList
+		conflicts []string
+	)
+
+	for _, other := range mods.Items {
+		if other.Namespace == mod.Namespace && other.Name == mod.Name {
+			continue
+		}
+
+		km := module.ConflictingKernelModule(mod, &other)
+		if km == "" {
+			continue
+		}
+
+		if nodes == nil {
+			nodes = &corev1.NodeList{}
+
+			if err := m.reader.List(ctx, nodes, client.MatchingLabels(mod.Spec.Selector)); err != nil {
+				return nil, fmt.Errorf("could not list nodes: %v", err)
+			}
+		}
+
+		for _, n := range nodes.Items {
+			selected, err := utils.IsObjectSelectedByLabels(n.GetLabels(), other.Spec.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("could not determine if node %s is selected by Module %s/%s: %v", n.Name, other.Namespace, other.Name, err)
+			}
+
+			if selected {
+				conflicts = append(conflicts, fmt.Sprintf("%s by Module %s/%s", km, other.Namespace, other.Name))
+				break
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+
+	msg := "kernel modules also used on targeted nodes: " + strings.Join(conflicts, ", ")
+
+	if m.conflictPolicy == config.ConflictPolicyReject {
+		return nil, errors.New(msg)
+	}
+
+	return admission.Warnings{msg}, nil
+}
+
+func validateMaintenanceWindows(windows []kmmv1beta1.MaintenanceWindow) error {
+	for i, mw := range windows {
+		w, err := maintenance.NewWindow(mw.Schedule, mw.Duration.Duration)
+		if err != nil {
+			return fmt.Errorf("maintenanceWindows[%d]: %v", i, err)
+		}
+
+		if _, err = w.NextOpening(time.Now()); err != nil {
+			return fmt.Errorf("maintenanceWindows[%d]: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
 func validateTolerations(tolerations []corev1.Toleration) error {
 
 	for i, toleration := range tolerations {
@@ -18,13 +18,26 @@ package webhook
 
 import (
 	"context"
+	"errors"
 	v1 "k8s.io/api/core/v1"
 	"strings"
+	"time"
 
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	testclient "github.com/kubernetes-sigs/kernel-module-management/internal/client"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/config"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func getLengthAfterSlash(s string) int {
@@ -53,7 +66,7 @@ var (
 		},
 	}
 
-	moduleWebhook = NewModuleValidator(GinkgoLogr, &KubeVersion{Major: 1, Minor: 34})
+	moduleWebhook = NewModuleValidator(GinkgoLogr, &KubeVersion{Major: 1, Minor: 34}, nil, "")
 )
 
 var _ = Describe("maxCombinedLength", func() {
@@ -325,6 +338,142 @@ var _ = Describe("validateModprobe", func() {
 		)
 	})
 
+	It("should fail when verifySignature is set without moduleName", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			RawArgs: &kmmv1beta1.ModprobeArgs{
+				Load:   []string{"arg"},
+				Unload: []string{"arg"},
+			},
+			VerifySignature: &kmmv1beta1.ModuleSignatureVerification{
+				CertSecret: v1.LocalObjectReference{Name: "cert"},
+			},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("verifySignature can only be set when moduleName is set"),
+			),
+		)
+	})
+
+	It("should fail when verifySignature has no Secret name", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName:      "mod-name",
+			VerifySignature: &kmmv1beta1.ModuleSignatureVerification{},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("verifySignature.certSecret.name must be set"),
+			),
+		)
+	})
+
+	It("should fail when a hook has no command", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName: "mod-name",
+			Hooks: &kmmv1beta1.ModprobeHooks{
+				PostUnload: &kmmv1beta1.ModprobeHook{},
+			},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("hooks.postUnload.command must not be empty"),
+			),
+		)
+	})
+
+	It("should fail when a hook command is not an absolute path", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName: "mod-name",
+			Hooks: &kmmv1beta1.ModprobeHooks{
+				PreLoad: &kmmv1beta1.ModprobeHook{Command: []string{"pre-load.sh"}},
+			},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("hooks.preLoad.command[0] must be an absolute path in the module image"),
+			),
+		)
+	})
+
+	It("should fail when unloadPolicy is set without moduleName", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			RawArgs: &kmmv1beta1.ModprobeArgs{
+				Load:   []string{"arg"},
+				Unload: []string{"arg"},
+			},
+			UnloadPolicy: &kmmv1beta1.UnloadPolicy{Type: kmmv1beta1.UnloadPolicyForce},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("unloadPolicy can only be set when moduleName is set"),
+			),
+		)
+	})
+
+	It("should fail when an unload timeout is set without the Wait type", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName:   "mod-name",
+			UnloadPolicy: &kmmv1beta1.UnloadPolicy{Type: kmmv1beta1.UnloadPolicyFail, TimeoutSeconds: 10},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("unloadPolicy.timeoutSeconds can only be set with the Wait type"),
+			),
+		)
+	})
+
+	It("should fail when livepatch is set without moduleName", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			RawArgs: &kmmv1beta1.ModprobeArgs{
+				Load:   []string{"arg"},
+				Unload: []string{"arg"},
+			},
+			Livepatch: &kmmv1beta1.Livepatch{},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("livepatch can only be set when moduleName is set"),
+			),
+		)
+	})
+
+	It("should fail when livepatch is set with the Force unload policy", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName:   "mod-name",
+			Livepatch:    &kmmv1beta1.Livepatch{},
+			UnloadPolicy: &kmmv1beta1.UnloadPolicy{Type: kmmv1beta1.UnloadPolicyForce},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			MatchError(
+				ContainSubstring("livepatch modules cannot be unloaded with the Force unload policy"),
+			),
+		)
+	})
+
 	It("should pass when rawArgs has load and unload values and moduleName is not set", func() {
 		modprobe := kmmv1beta1.ModprobeSpec{
 			RawArgs: &kmmv1beta1.ModprobeArgs{
@@ -830,6 +979,309 @@ var _ = Describe("validateDRA", func() {
 	})
 })
 
+var _ = Describe("validateUpgradeStrategy", func() {
+	DescribeTable("should validate maxUnavailable",
+		func(maxUnavailable *intstr.IntOrString, expectErr bool) {
+			err := validateUpgradeStrategy(&kmmv1beta1.UpgradeStrategy{MaxUnavailable: maxUnavailable})
+
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("not set", nil, false),
+		Entry("number", ptr.To(intstr.FromInt32(2)), false),
+		Entry("percentage", ptr.To(intstr.FromString("25%")), false),
+		Entry("negative number", ptr.To(intstr.FromInt32(-1)), true),
+		Entry("invalid string", ptr.To(intstr.FromString("two")), true),
+	)
+
+	DescribeTable("should validate the canary",
+		func(canary *kmmv1beta1.CanaryStrategy, expectErr bool) {
+			err := validateUpgradeStrategy(&kmmv1beta1.UpgradeStrategy{Canary: canary})
+
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("defaults", &kmmv1beta1.CanaryStrategy{}, false),
+		Entry("node selector", &kmmv1beta1.CanaryStrategy{NodeSelector: map[string]string{"canary": ""}}, false),
+		Entry("percentage", &kmmv1beta1.CanaryStrategy{Nodes: ptr.To(intstr.FromString("10%"))}, false),
+		Entry("zero nodes", &kmmv1beta1.CanaryStrategy{Nodes: ptr.To(intstr.FromInt32(0))}, true),
+		Entry(
+			"nodes and node selector",
+			&kmmv1beta1.CanaryStrategy{Nodes: ptr.To(intstr.FromInt32(1)), NodeSelector: map[string]string{"canary": ""}},
+			true,
+		),
+		Entry(
+			"invalid probe",
+			&kmmv1beta1.CanaryStrategy{
+				Probe: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Invalid"}},
+				},
+			},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validateMaintenanceWindows", func() {
+	DescribeTable("should validate the windows",
+		func(window kmmv1beta1.MaintenanceWindow, expectErr bool) {
+			err := validateMaintenanceWindows([]kmmv1beta1.MaintenanceWindow{window})
+
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("valid", kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * sat", Duration: metav1.Duration{Duration: time.Hour}}, false),
+		Entry("descriptor", kmmv1beta1.MaintenanceWindow{Schedule: "@daily", Duration: metav1.Duration{Duration: time.Hour}}, false),
+		Entry("invalid schedule", kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * *", Duration: metav1.Duration{Duration: time.Hour}}, true),
+		Entry("schedule that never matches", kmmv1beta1.MaintenanceWindow{Schedule: "0 0 30 2 *", Duration: metav1.Duration{Duration: time.Hour}}, true),
+		Entry("no duration", kmmv1beta1.MaintenanceWindow{Schedule: "@daily"}, true),
+	)
+})
+
+var _ = Describe("validateDependsOn", func() {
+	DescribeTable("should validate the dependencies",
+		func(dependsOn []kmmv1beta1.ModuleReference, expectedErr string) {
+			mod := &kmmv1beta1.Module{
+				ObjectMeta: metav1.ObjectMeta{Name: "addon", Namespace: "ns"},
+				Spec:       kmmv1beta1.ModuleSpec{DependsOn: dependsOn},
+			}
+
+			err := validateDependsOn(mod)
+
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			}
+		},
+		Entry("no dependencies", nil, ""),
+		Entry("valid", []kmmv1beta1.ModuleReference{{Name: "core"}, {Name: "addon", Namespace: "other-ns"}}, ""),
+		Entry("self", []kmmv1beta1.ModuleReference{{Name: "addon"}}, "cannot depend on itself"),
+		Entry("self with namespace", []kmmv1beta1.ModuleReference{{Name: "addon", Namespace: "ns"}}, "cannot depend on itself"),
+		Entry("duplicate", []kmmv1beta1.ModuleReference{{Name: "core"}, {Name: "core", Namespace: "ns"}}, "duplicate dependency ns/core"),
+	)
+})
+
+var _ = Describe("validateDependencyCycles", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *testclient.MockClient
+		mv   *ModuleValidator
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = testclient.NewMockClient(ctrl)
+		mv = NewModuleValidator(GinkgoLogr, &KubeVersion{Major: 1, Minor: 34}, clnt, "")
+	})
+
+	ctx := context.TODO()
+
+	mod := &kmmv1beta1.Module{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"},
+		Spec:       kmmv1beta1.ModuleSpec{DependsOn: []kmmv1beta1.ModuleReference{{Name: "b"}}},
+	}
+
+	withDependencies := func(dependsOn ...kmmv1beta1.ModuleReference) func(context.Context, types.NamespacedName, *kmmv1beta1.Module, ...any) error {
+		return func(_ context.Context, _ types.NamespacedName, m *kmmv1beta1.Module, _ ...any) error {
+			m.Spec.DependsOn = dependsOn
+			return nil
+		}
+	}
+
+	It("should do nothing without a reader", func() {
+		Expect(
+			NewModuleValidator(GinkgoLogr, &KubeVersion{Major: 1, Minor: 34}, nil, "").validateDependencyCycles(ctx, mod),
+		).NotTo(HaveOccurred())
+	})
+
+	It("should ignore dependencies that do not exist", func() {
+		clnt.
+			EXPECT().
+			Get(ctx, types.NamespacedName{Namespace: "ns", Name: "b"}, &kmmv1beta1.Module{}).
+			Return(k8serrors.NewNotFound(schema.GroupResource{}, "b"))
+
+		Expect(mv.validateDependencyCycles(ctx, mod)).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if a dependency cannot be fetched", func() {
+		clnt.EXPECT().Get(ctx, types.NamespacedName{Namespace: "ns", Name: "b"}, &kmmv1beta1.Module{}).Return(errors.New("random error"))
+
+		Expect(mv.validateDependencyCycles(ctx, mod)).To(MatchError(ContainSubstring("could not get Module ns/b")))
+	})
+
+	It("should accept dependencies without cycles", func() {
+		gomock.InOrder(
+			clnt.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Namespace: "ns", Name: "b"}, &kmmv1beta1.Module{}).
+				DoAndReturn(withDependencies(kmmv1beta1.ModuleReference{Name: "c", Namespace: "other-ns"})),
+			clnt.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Namespace: "other-ns", Name: "c"}, &kmmv1beta1.Module{}).
+				DoAndReturn(withDependencies(kmmv1beta1.ModuleReference{Name: "a"})),
+			clnt.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Namespace: "other-ns", Name: "a"}, &kmmv1beta1.Module{}).
+				Return(k8serrors.NewNotFound(schema.GroupResource{}, "a")),
+		)
+
+		Expect(mv.validateDependencyCycles(ctx, mod)).NotTo(HaveOccurred())
+	})
+
+	It("should reject a dependency cycle", func() {
+		gomock.InOrder(
+			clnt.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Namespace: "ns", Name: "b"}, &kmmv1beta1.Module{}).
+				DoAndReturn(withDependencies(kmmv1beta1.ModuleReference{Name: "c"})),
+			clnt.
+				EXPECT().
+				Get(ctx, types.NamespacedName{Namespace: "ns", Name: "c"}, &kmmv1beta1.Module{}).
+				DoAndReturn(withDependencies(kmmv1beta1.ModuleReference{Name: "a"})),
+		)
+
+		Expect(
+			mv.validateDependencyCycles(ctx, mod),
+		).To(
+			MatchError("dependency cycle: ns/a -> ns/b -> ns/c -> ns/a"),
+		)
+	})
+
+	It("should be called by ValidateCreate", func() {
+		m := validModule
+		m.Name = "a"
+		m.Namespace = "ns"
+		m.Spec.DependsOn = []kmmv1beta1.ModuleReference{{Name: "b"}}
+
+		clnt.
+			EXPECT().
+			Get(ctx, types.NamespacedName{Namespace: "ns", Name: "b"}, &kmmv1beta1.Module{}).
+			DoAndReturn(withDependencies(kmmv1beta1.ModuleReference{Name: "a"}))
+
+		_, err := mv.ValidateCreate(ctx, &m)
+		Expect(err).To(MatchError(ContainSubstring("dependency cycle")))
+	})
+})
+
+var _ = Describe("validateConflicts", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *testclient.MockClient
+		mod  *kmmv1beta1.Module
+	)
+
+	ctx := context.TODO()
+
+	newModule := func(namespace, name string, selector map[string]string) kmmv1beta1.Module {
+		m := validModule
+		m.Namespace = namespace
+		m.Name = name
+		m.Spec.Selector = selector
+
+		return m
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = testclient.NewMockClient(ctrl)
+
+		m := newModule("ns", "a", map[string]string{"role": "gpu"})
+		mod = &m
+	})
+
+	listModulesAndNodes := func(mods ...kmmv1beta1.Module) {
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleList{}).DoAndReturn(
+				func(_ context.Context, list *kmmv1beta1.ModuleList, _ ...ctrlclient.ListOption) error {
+					list.Items = append([]kmmv1beta1.Module{*mod}, mods...)
+					return nil
+				},
+			),
+			clnt.EXPECT().List(ctx, &v1.NodeList{}, ctrlclient.MatchingLabels{"role": "gpu"}).DoAndReturn(
+				func(_ context.Context, list *v1.NodeList, _ ...ctrlclient.ListOption) error {
+					list.Items = []v1.Node{
+						{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"role": "gpu", "zone": "a"}}},
+					}
+					return nil
+				},
+			),
+		)
+	}
+
+	It("should do nothing without a reader", func() {
+		Expect(
+			NewModuleValidator(GinkgoLogr, &KubeVersion{Major: 1, Minor: 34}, nil, "").validateConflicts(ctx, mod),
+		).To(
+			BeNil(),
+		)
+	})
+
+	It("should return an error if the Modules cannot be listed", func() {
+		clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleList{}).Return(errors.New("random error"))
+
+		_, err := NewModuleValidator(GinkgoLogr, nil, clnt, "").validateConflicts(ctx, mod)
+		Expect(err).To(MatchError(ContainSubstring("could not list Modules")))
+	})
+
+	It("should not list nodes if no other Module uses the same kernel module", func() {
+		other := newModule("ns", "b", map[string]string{"role": "gpu"})
+		other.Spec.ModuleLoader = &kmmv1beta1.ModuleLoaderSpec{
+			Container: kmmv1beta1.ModuleLoaderContainerSpec{
+				Modprobe: kmmv1beta1.ModprobeSpec{ModuleName: "other-mod-name"},
+			},
+		}
+
+		clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleList{}).DoAndReturn(
+			func(_ context.Context, list *kmmv1beta1.ModuleList, _ ...ctrlclient.ListOption) error {
+				list.Items = []kmmv1beta1.Module{*mod, other}
+				return nil
+			},
+		)
+
+		warnings, err := NewModuleValidator(GinkgoLogr, nil, clnt, config.ConflictPolicyReject).validateConflicts(ctx, mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should ignore conflicting Modules that target other nodes", func() {
+		listModulesAndNodes(newModule("ns", "b", map[string]string{"zone": "b"}))
+
+		warnings, err := NewModuleValidator(GinkgoLogr, nil, clnt, config.ConflictPolicyReject).validateConflicts(ctx, mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should reject conflicting Modules with the Reject policy", func() {
+		listModulesAndNodes(newModule("other-ns", "b", map[string]string{"zone": "a"}))
+
+		_, err := NewModuleValidator(GinkgoLogr, nil, clnt, config.ConflictPolicyReject).validateConflicts(ctx, mod)
+		Expect(err).To(MatchError("kernel modules also used on targeted nodes: mod-name by Module other-ns/b"))
+	})
+
+	DescribeTable("should only warn about conflicting Modules with other policies",
+		func(policy config.ConflictPolicy) {
+			listModulesAndNodes(newModule("other-ns", "b", map[string]string{"zone": "a"}))
+
+			warnings, err := NewModuleValidator(GinkgoLogr, nil, clnt, policy).validateConflicts(ctx, mod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(Equal(admission.Warnings{"kernel modules also used on targeted nodes: mod-name by Module other-ns/b"}))
+		},
+		Entry("default", config.ConflictPolicy("")),
+		Entry("Warn", config.ConflictPolicyWarn),
+		Entry("FirstWins", config.ConflictPolicyFirstWins),
+	)
+})
+
 var _ = Describe("validateDevicePluginVolumes", func() {
 	It("should accept nil DevicePlugin", func() {
 		Expect(validateDevicePluginVolumes(nil)).NotTo(HaveOccurred())
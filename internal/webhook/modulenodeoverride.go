@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ModuleNodeOverrideValidator validates ModuleNodeOverride resources.
+type ModuleNodeOverrideValidator struct {
+	logger logr.Logger
+}
+
+func NewModuleNodeOverrideValidator(logger logr.Logger) *ModuleNodeOverrideValidator {
+	return &ModuleNodeOverrideValidator{logger: logger}
+}
+
+func (v *ModuleNodeOverrideValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// controller-runtime will set the path to `validate-<group>-<version>-<resource> so we
+	// need to make sure it is set correctly in the +kubebuilder annotation below.
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kmmv1beta1.ModuleNodeOverride{}).
+		WithValidator(v).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-kmm-sigs-x-k8s-io-v1beta1-modulenodeoverride,mutating=false,failurePolicy=fail,sideEffects=None,groups=kmm.sigs.x-k8s.io,resources=modulenodeoverrides,verbs=create;update,versions=v1beta1,name=vmodulenodeoverride.kb.io,admissionReviewVersions=v1
+
+func (v *ModuleNodeOverrideValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	o, ok := obj.(*kmmv1beta1.ModuleNodeOverride)
+	if !ok {
+		return nil, fmt.Errorf("bad type for the object; expected %v, got %v", o, obj)
+	}
+
+	v.logger.Info("Validating ModuleNodeOverride creation", "name", o.Name, "namespace", o.Namespace)
+	return nil, validateModuleNodeOverride(o)
+}
+
+func (v *ModuleNodeOverrideValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := newObj.(*kmmv1beta1.ModuleNodeOverride)
+	if !ok {
+		return nil, fmt.Errorf("bad type for the new object; expected %v, got %v", o, newObj)
+	}
+
+	v.logger.Info("Validating ModuleNodeOverride update", "name", o.Name, "namespace", o.Namespace)
+	return nil, validateModuleNodeOverride(o)
+}
+
+func (v *ModuleNodeOverrideValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, NotImplemented
+}
+
+func validateModuleNodeOverride(o *kmmv1beta1.ModuleNodeOverride) error {
+	if o.Spec.ModuleName == "" {
+		return errors.New("moduleName cannot be empty")
+	}
+
+	if _, err := labels.ValidatedSelectorFromSet(o.Spec.Selector); err != nil {
+		return fmt.Errorf("invalid selector: %v", err)
+	}
+
+	if err := validateModuleParameters("parameters", o.Spec.Parameters); err != nil {
+		return err
+	}
+
+	if err := validateModuleParameters("extraParameters", o.Spec.ExtraParameters); err != nil {
+		return err
+	}
+
+	if fp := o.Spec.FirmwarePath; fp != "" && !filepath.IsAbs(fp) {
+		return fmt.Errorf("firmwarePath must be an absolute path; got %q", fp)
+	}
+
+	if args := o.Spec.Args; args != nil {
+		if len(args.Load) == 0 && len(args.Unload) == 0 {
+			return errors.New("args must set load or unload arguments")
+		}
+
+		for i, a := range args.Load {
+			if strings.TrimSpace(a) == "" {
+				return fmt.Errorf("args.load[%d] cannot be empty", i)
+			}
+		}
+
+		for i, a := range args.Unload {
+			if strings.TrimSpace(a) == "" {
+				return fmt.Errorf("args.unload[%d] cannot be empty", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateModuleParameters checks that each of params is a single, non-empty kernel module parameter.
+func validateModuleParameters(field string, params []string) error {
+	for i, p := range params {
+		if p == "" || strings.ContainsAny(p, " \t\n") {
+			return fmt.Errorf("%s[%d]: %q is not a valid kernel module parameter", field, i, p)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("validateModuleNodeOverride", func() {
+	var o *kmmv1beta1.ModuleNodeOverride
+
+	BeforeEach(func() {
+		o = &kmmv1beta1.ModuleNodeOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "override", Namespace: "ns"},
+			Spec: kmmv1beta1.ModuleNodeOverrideSpec{
+				ModuleName:      "mod",
+				Selector:        map[string]string{"role": "gpu"},
+				Parameters:      []string{"a=1"},
+				ExtraParameters: []string{"b=2"},
+				FirmwarePath:    "/firmware",
+				Args:            &kmmv1beta1.ModprobeArgs{Load: []string{"-v"}},
+			},
+		}
+	})
+
+	It("should accept a valid override", func() {
+		Expect(validateModuleNodeOverride(o)).To(Succeed())
+	})
+
+	DescribeTable("should reject invalid overrides",
+		func(mutate func(*kmmv1beta1.ModuleNodeOverride), expectedErr string) {
+			mutate(o)
+
+			Expect(
+				validateModuleNodeOverride(o),
+			).To(
+				MatchError(ContainSubstring(expectedErr)),
+			)
+		},
+		Entry("empty moduleName",
+			func(o *kmmv1beta1.ModuleNodeOverride) { o.Spec.ModuleName = "" },
+			"moduleName cannot be empty",
+		),
+		Entry("invalid selector",
+			func(o *kmmv1beta1.ModuleNodeOverride) { o.Spec.Selector = map[string]string{"role": "not valid"} },
+			"invalid selector",
+		),
+		Entry("empty parameter",
+			func(o *kmmv1beta1.ModuleNodeOverride) { o.Spec.Parameters = []string{""} },
+			"parameters[0]",
+		),
+		Entry("several parameters in one extra parameter",
+			func(o *kmmv1beta1.ModuleNodeOverride) { o.Spec.ExtraParameters = []string{"b=2 c=3"} },
+			"extraParameters[0]",
+		),
+		Entry("relative firmware path",
+			func(o *kmmv1beta1.ModuleNodeOverride) { o.Spec.FirmwarePath = "firmware" },
+			"firmwarePath must be an absolute path",
+		),
+		Entry("empty args",
+			func(o *kmmv1beta1.ModuleNodeOverride) { o.Spec.Args = &kmmv1beta1.ModprobeArgs{} },
+			"args must set load or unload arguments",
+		),
+		Entry("empty unload argument",
+			func(o *kmmv1beta1.ModuleNodeOverride) { o.Spec.Args.Unload = []string{" "} },
+			"args.unload[0] cannot be empty",
+		),
+	)
+})
+
+var _ = Describe("ModuleNodeOverrideValidator", func() {
+	v := NewModuleNodeOverrideValidator(GinkgoLogr)
+	ctx := context.TODO()
+
+	It("should reject an override without moduleName on creation", func() {
+		_, err := v.ValidateCreate(ctx, &kmmv1beta1.ModuleNodeOverride{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject an override without moduleName on update", func() {
+		o := &kmmv1beta1.ModuleNodeOverride{Spec: kmmv1beta1.ModuleNodeOverrideSpec{ModuleName: "mod"}}
+
+		_, err := v.ValidateUpdate(ctx, o, &kmmv1beta1.ModuleNodeOverride{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("ValidateDelete should return not implemented", func() {
+		_, err := v.ValidateDelete(ctx, nil)
+		Expect(err).To(Equal(NotImplemented))
+	})
+})
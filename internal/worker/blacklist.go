@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// writeBlacklist writes a modprobe configuration file at path that prevents modules from being loaded automatically,
+// for instance by udev when the node boots.
+func writeBlacklist(path string, modules []string) error {
+	sb := strings.Builder{}
+
+	sb.WriteString("# Managed by KMM; removed when the kernel module that replaces these modules is unloaded.\n")
+
+	for _, m := range modules {
+		fmt.Fprintf(&sb, "blacklist %s\n", m)
+	}
+
+	if err := writeFileAtomic(path, strings.NewReader(sb.String()), 0644); err != nil {
+		return fmt.Errorf("could not write the blacklist %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func removeBlacklist(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not remove the blacklist %s: %v", path, err)
+	}
+
+	return nil
+}
@@ -1,9 +1,22 @@
 package worker
 
 const (
-	FlagFirmwarePath = "firmware-path"
+	FlagBlacklistPath = "blacklist-path"
+	FlagDryRun        = "dry-run"
+	FlagFirmwareOwner = "firmware-owner"
+	FlagFirmwarePath  = "firmware-path"
+	FlagNativeLoader  = "native-loader"
+	FlagOutputDir     = "output-dir"
 
 	FirmwareClassPathLocation = "/sys/module/firmware_class/parameters/path"
 	ImagesDir                 = "/var/run/kmm/images"
 	PullSecretsDir            = "/var/run/kmm/pull-secrets"
+	SignatureCertPath         = "/var/run/kmm/signature-cert/cert"
 )
+
+// ImagesCacheMaxSize is the size, in bytes, above which the least recently used files extracted from images are removed
+// from ImagesDir.
+const ImagesCacheMaxSize int64 = 1 << 30
+
+// ExitCodeModuleBusy is the exit code of the worker when a kernel module could not be unloaded because it is in use.
+const ExitCodeModuleBusy = 3
@@ -0,0 +1,318 @@
+package worker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"golang.org/x/sys/unix"
+)
+
+//go:generate mockgen -source=firmware.go -package=worker -destination=mock_firmware.go
+
+const (
+	firmwareManifestName = ".kmm-firmware.json"
+	firmwareLockName     = ".kmm-firmware.lock"
+)
+
+// FirmwareManager installs firmware files on the host on behalf of Modules.
+// It keeps a manifest in the host firmware directory that records the owners and the checksum of every file, so that
+// files shared by several Modules are only removed when their last owner releases them.
+type FirmwareManager interface {
+	// Install copies files, relative to srcDir, into dstDir on behalf of owner.
+	// It returns an error wrapping ErrFirmwareConflict without copying anything if another owner already installed
+	// one of the files with different contents.
+	Install(owner, srcDir, dstDir string, files []string) error
+	// Remove releases the files that owner installed in dstDir and removes those that have no owner left.
+	// Files that were installed before the manifest existed are found by listing srcDir.
+	Remove(owner, srcDir, dstDir string) error
+}
+
+type firmwareManifest struct {
+	Files map[string]*firmwareManifestEntry `json:"files"`
+}
+
+type firmwareManifestEntry struct {
+	SHA256 string   `json:"sha256"`
+	Owners []string `json:"owners"`
+}
+
+type firmwareManagerImpl struct {
+	logger logr.Logger
+}
+
+func NewFirmwareManager(logger logr.Logger) FirmwareManager {
+	return &firmwareManagerImpl{logger: logger.WithName("firmware")}
+}
+
+func (fm *firmwareManagerImpl) Install(owner, srcDir, dstDir string, files []string) error {
+	unlock, err := lockFirmwareDir(dstDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	manifest, err := readFirmwareManifest(dstDir)
+	if err != nil {
+		return err
+	}
+
+	checksums := make(map[string]string, len(files))
+	conflicts := make([]string, 0)
+
+	for _, f := range files {
+		sum, err := fileSHA256(filepath.Join(srcDir, f))
+		if err != nil {
+			return err
+		}
+
+		checksums[f] = sum
+
+		entry := manifest.Files[f]
+		if entry == nil || entry.SHA256 == sum {
+			continue
+		}
+
+		if others := otherOwners(entry.Owners, owner); len(others) > 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%s (owned by %s)", f, strings.Join(others, ", ")))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: %s", ErrFirmwareConflict, strings.Join(conflicts, "; "))
+	}
+
+	for _, f := range files {
+		dst := filepath.Join(dstDir, f)
+
+		if manifest.Files[f] == nil {
+			if _, err = os.Stat(dst); err == nil {
+				fm.logger.Info(utils.WarnString("Overwriting a firmware file that KMM did not install"), "path", dst)
+			}
+		}
+
+		fm.logger.Info("Installing firmware file", "path", dst, "owner", owner)
+
+		if err = copyFileAtomic(filepath.Join(srcDir, f), dst); err != nil {
+			return err
+		}
+
+		entry := manifest.Files[f]
+		if entry == nil || entry.SHA256 != checksums[f] {
+			entry = &firmwareManifestEntry{SHA256: checksums[f]}
+			manifest.Files[f] = entry
+		}
+
+		if !slices.Contains(entry.Owners, owner) {
+			entry.Owners = append(entry.Owners, owner)
+			sort.Strings(entry.Owners)
+		}
+	}
+
+	return writeFirmwareManifest(dstDir, manifest)
+}
+
+func (fm *firmwareManagerImpl) Remove(owner, srcDir, dstDir string) error {
+	unlock, err := lockFirmwareDir(dstDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	manifest, err := readFirmwareManifest(dstDir)
+	if err != nil {
+		return err
+	}
+
+	owned := make([]string, 0)
+
+	for f, entry := range manifest.Files {
+		if slices.Contains(entry.Owners, owner) {
+			owned = append(owned, f)
+		}
+	}
+
+	if len(owned) == 0 {
+		fm.logger.Info("No firmware file recorded for owner; using the files of the image", "owner", owner)
+
+		files, err := listFiles(srcDir)
+		if err != nil {
+			return fmt.Errorf("could not list the firmware files in %s: %v", srcDir, err)
+		}
+
+		for _, f := range files {
+			if manifest.Files[f] == nil {
+				owned = append(owned, f)
+			}
+		}
+	}
+
+	sort.Strings(owned)
+
+	for _, f := range owned {
+		if entry := manifest.Files[f]; entry != nil {
+			entry.Owners = otherOwners(entry.Owners, owner)
+
+			if len(entry.Owners) > 0 {
+				fm.logger.Info("Firmware file is still used by other owners; keeping it", "path", f, "owners", entry.Owners)
+				continue
+			}
+
+			delete(manifest.Files, f)
+		}
+
+		path := filepath.Join(dstDir, f)
+
+		fm.logger.Info("Removing firmware file", "path", path)
+
+		if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("could not remove firmware file %s: %v", path, err)
+		}
+	}
+
+	return writeFirmwareManifest(dstDir, manifest)
+}
+
+// lockFirmwareDir takes an exclusive lock on dir, so that workers running concurrently on the same node do not
+// overwrite each other's changes to the manifest.
+func lockFirmwareDir(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create the firmware directory %s: %v", dir, err)
+	}
+
+	path := filepath.Join(dir, firmwareLockName)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", path, err)
+	}
+
+	if err = unix.Flock(int(f.Fd()), unix.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("could not lock %s: %v", path, err)
+	}
+
+	return func() {
+		_ = unix.Flock(int(f.Fd()), unix.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+func readFirmwareManifest(dir string) (*firmwareManifest, error) {
+	path := filepath.Join(dir, firmwareManifestName)
+
+	manifest := firmwareManifest{}
+
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not read the firmware manifest %s: %v", path, err)
+	}
+
+	if err == nil {
+		if err = json.Unmarshal(b, &manifest); err != nil {
+			return nil, fmt.Errorf("could not parse the firmware manifest %s: %v", path, err)
+		}
+	}
+
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]*firmwareManifestEntry)
+	}
+
+	return &manifest, nil
+}
+
+func writeFirmwareManifest(dir string, manifest *firmwareManifest) error {
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal the firmware manifest: %v", err)
+	}
+
+	return writeFileAtomic(filepath.Join(dir, firmwareManifestName), bytes.NewReader(b), 0644)
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+
+	if _, err = io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFileAtomic(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", src, err)
+	}
+	defer f.Close()
+
+	if err = os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("could not create directory %s: %v", filepath.Dir(dst), err)
+	}
+
+	return writeFileAtomic(dst, f, 0644)
+}
+
+// writeFileAtomic writes the contents of r to a temporary file in the directory of path and renames it to path, so
+// that readers never see a partially written file.
+func writeFileAtomic(path string, r io.Reader, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("could not create a temporary file for %s: %v", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		return fmt.Errorf("could not write %s: %v", tmp.Name(), err)
+	}
+
+	if err = tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("could not change the mode of %s: %v", tmp.Name(), err)
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("could not sync %s: %v", tmp.Name(), err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("could not close %s: %v", tmp.Name(), err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not rename %s to %s: %v", tmp.Name(), path, err)
+	}
+
+	return nil
+}
+
+func otherOwners(owners []string, owner string) []string {
+	others := make([]string, 0, len(owners))
+
+	for _, o := range owners {
+		if o != owner {
+			others = append(others, o)
+		}
+	}
+
+	return others
+}
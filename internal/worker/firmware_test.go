@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("firmwareManagerImpl", func() {
+	var (
+		dstDir string
+		fm     FirmwareManager
+	)
+
+	BeforeEach(func() {
+		dstDir = GinkgoT().TempDir()
+		fm = NewFirmwareManager(GinkgoLogr)
+	})
+
+	writeSrc := func(files map[string]string) string {
+		GinkgoHelper()
+
+		dir := GinkgoT().TempDir()
+
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		}
+
+		return dir
+	}
+
+	readManifest := func() firmwareManifest {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(filepath.Join(dstDir, firmwareManifestName))
+		Expect(err).NotTo(HaveOccurred())
+
+		m := firmwareManifest{}
+		Expect(json.Unmarshal(b, &m)).To(Succeed())
+
+		return m
+	}
+
+	It("should copy the files and record their owner and checksum", func() {
+		src := writeSrc(map[string]string{"fw.bin": "data", "sub/other.bin": "other"})
+
+		Expect(
+			fm.Install("ns/a", src, dstDir, []string{"fw.bin", "sub/other.bin"}),
+		).To(
+			Succeed(),
+		)
+
+		Expect(os.ReadFile(filepath.Join(dstDir, "fw.bin"))).To(Equal([]byte("data")))
+		Expect(os.ReadFile(filepath.Join(dstDir, "sub/other.bin"))).To(Equal([]byte("other")))
+
+		m := readManifest()
+		Expect(m.Files).To(HaveLen(2))
+		Expect(m.Files["fw.bin"].Owners).To(Equal([]string{"ns/a"}))
+		Expect(m.Files["fw.bin"].SHA256).To(Equal("3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7"))
+	})
+
+	It("should keep a shared file until its last owner removes it", func() {
+		srcA := writeSrc(map[string]string{"shared.bin": "same", "a.bin": "a"})
+		srcB := writeSrc(map[string]string{"shared.bin": "same"})
+
+		Expect(fm.Install("ns/a", srcA, dstDir, []string{"a.bin", "shared.bin"})).To(Succeed())
+		Expect(fm.Install("ns/b", srcB, dstDir, []string{"shared.bin"})).To(Succeed())
+		Expect(readManifest().Files["shared.bin"].Owners).To(Equal([]string{"ns/a", "ns/b"}))
+
+		Expect(fm.Remove("ns/a", srcA, dstDir)).To(Succeed())
+		Expect(filepath.Join(dstDir, "a.bin")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dstDir, "shared.bin")).To(BeAnExistingFile())
+
+		Expect(fm.Remove("ns/b", srcB, dstDir)).To(Succeed())
+		Expect(filepath.Join(dstDir, "shared.bin")).NotTo(BeAnExistingFile())
+		Expect(readManifest().Files).To(BeEmpty())
+	})
+
+	It("should return an error and copy nothing if another owner installed different contents", func() {
+		srcA := writeSrc(map[string]string{"fw.bin": "a"})
+		srcB := writeSrc(map[string]string{"fw.bin": "b", "b.bin": "b"})
+
+		Expect(fm.Install("ns/a", srcA, dstDir, []string{"fw.bin"})).To(Succeed())
+
+		err := fm.Install("ns/b", srcB, dstDir, []string{"b.bin", "fw.bin"})
+		Expect(err).To(MatchError(ErrFirmwareConflict))
+		Expect(err.Error()).To(ContainSubstring("fw.bin (owned by ns/a)"))
+
+		Expect(os.ReadFile(filepath.Join(dstDir, "fw.bin"))).To(Equal([]byte("a")))
+		Expect(filepath.Join(dstDir, "b.bin")).NotTo(BeAnExistingFile())
+	})
+
+	It("should let the only owner update a file", func() {
+		Expect(fm.Install("ns/a", writeSrc(map[string]string{"fw.bin": "v1"}), dstDir, []string{"fw.bin"})).To(Succeed())
+		Expect(fm.Install("ns/a", writeSrc(map[string]string{"fw.bin": "v2"}), dstDir, []string{"fw.bin"})).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(dstDir, "fw.bin"))).To(Equal([]byte("v2")))
+		Expect(readManifest().Files["fw.bin"].Owners).To(Equal([]string{"ns/a"}))
+	})
+
+	It("should remove files that are not in the manifest using the image files", func() {
+		src := writeSrc(map[string]string{"legacy.bin": "legacy", "owned.bin": "owned"})
+
+		Expect(os.WriteFile(filepath.Join(dstDir, "legacy.bin"), []byte("legacy"), 0644)).To(Succeed())
+		Expect(fm.Install("ns/b", src, dstDir, []string{"owned.bin"})).To(Succeed())
+
+		Expect(fm.Remove("ns/a", src, dstDir)).To(Succeed())
+
+		Expect(filepath.Join(dstDir, "legacy.bin")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dstDir, "owned.bin")).To(BeAnExistingFile())
+	})
+})
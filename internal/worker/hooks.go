@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"golang.org/x/sys/unix"
+)
+
+//go:generate mockgen -source=hooks.go -package=worker -destination=mock_hooks.go
+
+type HookRunner interface {
+	RunHook(ctx context.Context, name string, hook *kmmv1beta1.ModprobeHook) error
+}
+
+const (
+	hookPreLoad    = "preLoad"
+	hookPostLoad   = "postLoad"
+	hookPreUnload  = "preUnload"
+	hookPostUnload = "postUnload"
+
+	defaultHookTimeout = 60 * time.Second
+)
+
+// hooksRootDir is where the image-extractor init container copies the hook executables.
+var hooksRootDir = sharedFilesDir
+
+type hookRunnerImpl struct {
+	logger logr.Logger
+}
+
+func NewHookRunner(logger logr.Logger) HookRunner {
+	return &hookRunnerImpl{logger: logger.WithName("hook")}
+}
+
+func (hr *hookRunnerImpl) RunHook(ctx context.Context, name string, hook *kmmv1beta1.ModprobeHook) error {
+	if len(hook.Command) == 0 {
+		return fmt.Errorf("hook %s has no command", name)
+	}
+
+	timeout := defaultHookTimeout
+
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, filepath.Join(hooksRootDir, hook.Command[0]), hook.Command[1:]...)
+
+	// Hooks are often shell scripts; kill the whole process group on timeout so that no child keeps the output pipes
+	// open.
+	cmd.SysProcAttr = &unix.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return unix.Kill(-cmd.Process.Pid, unix.SIGKILL)
+	}
+
+	logger := hr.logger.WithName(name)
+
+	cl, err := NewCommandLogger(cmd, logger)
+	if err != nil {
+		return fmt.Errorf("could not create a command logger: %v", err)
+	}
+
+	logger.Info("Running hook", "command", cmd.String(), "timeout", timeout)
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("could not start hook %s: %v", name, err)
+	}
+
+	if err = cl.Wait(); err != nil {
+		return fmt.Errorf("error while waiting on the command logger: %v", err)
+	}
+
+	if err = cmd.Wait(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("hook %s timed out after %v", name, timeout)
+		}
+
+		return fmt.Errorf("hook %s failed: %v", name, err)
+	}
+
+	return nil
+}
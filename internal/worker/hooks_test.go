@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("hookRunnerImpl_RunHook", func() {
+	var hr HookRunner
+
+	BeforeEach(func() {
+		hooksRootDir = "/"
+		hr = NewHookRunner(GinkgoLogr)
+	})
+
+	AfterEach(func() {
+		hooksRootDir = sharedFilesDir
+	})
+
+	ctx := context.TODO()
+
+	It("should return an error if the hook has no command", func() {
+		Expect(
+			hr.RunHook(ctx, hookPreLoad, &kmmv1beta1.ModprobeHook{}),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should run the command", func() {
+		hook := &kmmv1beta1.ModprobeHook{Command: []string{"/bin/sh", "-c", "echo hello"}}
+
+		Expect(
+			hr.RunHook(ctx, hookPreLoad, hook),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the command failed", func() {
+		hook := &kmmv1beta1.ModprobeHook{Command: []string{"/bin/sh", "-c", "exit 3"}}
+
+		Expect(
+			hr.RunHook(ctx, hookPostLoad, hook),
+		).To(
+			MatchError("hook postLoad failed: exit status 3"),
+		)
+	})
+
+	It("should kill the command after the timeout", func() {
+		hook := &kmmv1beta1.ModprobeHook{
+			Command:        []string{"/bin/sh", "-c", "sleep 30 & sleep 30"},
+			TimeoutSeconds: 1,
+		}
+
+		Expect(
+			hr.RunHook(ctx, hookPreUnload, hook),
+		).To(
+			MatchError("hook preUnload timed out after 1s"),
+		)
+	})
+})
@@ -0,0 +1,346 @@
+package worker
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+)
+
+//go:generate mockgen -source=imagepuller.go -package=worker -destination=mock_imagepuller.go
+
+type ImagePuller interface {
+	// PullImage extracts paths from image and returns the directory that contains them, laid out as in the image.
+	// Extracted files are cached by image digest; the least recently used entries are removed once the cache is larger
+	// than its maximum size.
+	PullImage(ctx context.Context, image string, insecure bool, paths []string) (string, error)
+}
+
+// imagesCacheMinAge is how long a cache entry is kept after it was last used, whatever the size of the cache, so that
+// the workers that use it can still copy its files.
+const imagesCacheMinAge = 10 * time.Minute
+
+type imagePullerImpl struct {
+	imagesDir string
+	maxSize   int64
+	keychain  authn.Keychain
+	logger    logr.Logger
+}
+
+// NewImagePuller returns an ImagePuller that caches extracted files in imagesDir.
+// The cache is not pruned if maxSize is not positive.
+func NewImagePuller(imagesDir string, maxSize int64, keychain authn.Keychain, logger logr.Logger) ImagePuller {
+	return &imagePullerImpl{
+		imagesDir: imagesDir,
+		maxSize:   maxSize,
+		keychain:  keychain,
+		logger:    logger.WithName("image-puller"),
+	}
+}
+
+// ImagePaths returns the paths of the files that the worker needs in the image of cfg: the kernel modules for the
+// config's kernel, the firmware and the hook executables.
+func ImagePaths(cfg *kmmv1beta1.ModuleConfig) []string {
+	paths := []string{filepath.Join(cfg.Modprobe.DirName, "lib", "modules", cfg.KernelVersion)}
+
+	if cfg.Modprobe.FirmwarePath != "" {
+		paths = append(paths, cfg.Modprobe.FirmwarePath)
+	}
+
+	if h := cfg.Modprobe.Hooks; h != nil {
+		for _, hook := range []*kmmv1beta1.ModprobeHook{h.PreLoad, h.PostLoad, h.PreUnload, h.PostUnload} {
+			if hook != nil && len(hook.Command) > 0 {
+				paths = append(paths, hook.Command[0])
+			}
+		}
+	}
+
+	return paths
+}
+
+func (ip *imagePullerImpl) PullImage(ctx context.Context, image string, insecure bool, paths []string) (string, error) {
+	var opts []name.Option
+
+	if insecure {
+		opts = append(opts, name.Insecure)
+	}
+
+	ref, err := name.ParseReference(image, opts...)
+	if err != nil {
+		return "", fmt.Errorf("could not parse the image reference %q: %v", image, err)
+	}
+
+	img, err := remote.Image(
+		ref,
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(ip.keychain),
+		remote.WithPlatform(v1.Platform{OS: "linux", Architecture: runtime.GOARCH}),
+	)
+	if err != nil {
+		return "", fmt.Errorf("could not get image %s: %v", image, err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", fmt.Errorf("could not get the digest of image %s: %v", image, err)
+	}
+
+	cleanPaths := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		cleanPaths = append(cleanPaths, filepath.Clean("/"+p))
+	}
+
+	slices.Sort(cleanPaths)
+	cleanPaths = slices.Compact(cleanPaths)
+
+	// The same image can be pulled for different kernels or configs, so the extracted paths are part of the key.
+	pathsHash := sha256.Sum256([]byte(strings.Join(cleanPaths, "\n")))
+	dir := filepath.Join(ip.imagesDir, digest.Algorithm, digest.Hex, hex.EncodeToString(pathsHash[:8]))
+
+	logger := ip.logger.WithValues("image", image, "digest", digest.String(), "dir", dir)
+
+	if _, err = os.Stat(dir); err == nil {
+		logger.Info("Using cached image files")
+
+		// record the use of the entry, so that it is evicted last
+		now := time.Now()
+		if err = os.Chtimes(dir, now, now); err != nil {
+			logger.Info(utils.WarnString("Could not update the modification time of the cached files"), "error", err)
+		}
+
+		ip.pruneCache(dir)
+
+		return dir, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", fmt.Errorf("could not create the cache directory for %s: %v", image, err)
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), ".tmp-")
+	if err != nil {
+		return "", fmt.Errorf("could not create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	logger.Info("Extracting image files", "paths", cleanPaths)
+
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	if err = extractPaths(tar.NewReader(rc), tmpDir, cleanPaths, logger); err != nil {
+		return "", fmt.Errorf("could not extract the files of image %s: %v", image, err)
+	}
+
+	// Make the files visible atomically; another worker may have extracted the same files in the meantime.
+	if err = os.Rename(tmpDir, dir); err != nil {
+		if _, statErr := os.Stat(dir); statErr == nil {
+			return dir, nil
+		}
+
+		return "", fmt.Errorf("could not move the extracted files to %s: %v", dir, err)
+	}
+
+	ip.pruneCache(dir)
+
+	return dir, nil
+}
+
+type cacheEntry struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// pruneCache removes the least recently used entries of the cache, including leftover temporary directories, until
+// the cache is not larger than ip.maxSize.
+// keep and the entries used in the last imagesCacheMinAge are never removed.
+// Errors are only logged, since the files that the worker needs are already extracted.
+func (ip *imagePullerImpl) pruneCache(keep string) {
+	if ip.maxSize <= 0 {
+		return
+	}
+
+	logger := ip.logger.WithValues("dir", ip.imagesDir)
+
+	// entries are laid out as <algorithm>/<digest>/<paths hash>
+	paths, err := filepath.Glob(filepath.Join(ip.imagesDir, "*", "*", "*"))
+	if err != nil {
+		logger.Info(utils.WarnString("Could not list the cached image files"), "error", err)
+		return
+	}
+
+	entries := make([]cacheEntry, 0, len(paths))
+
+	var total int64
+
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			logger.Info(utils.WarnString("Could not read the cached image files"), "path", p, "error", err)
+			continue
+		}
+
+		size, err := dirSize(p)
+		if err != nil {
+			logger.Info(utils.WarnString("Could not compute the size of the cached image files"), "path", p, "error", err)
+			continue
+		}
+
+		entries = append(entries, cacheEntry{path: p, modTime: fi.ModTime(), size: size})
+		total += size
+	}
+
+	slices.SortFunc(entries, func(a, b cacheEntry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	minModTime := time.Now().Add(-imagesCacheMinAge)
+
+	for _, e := range entries {
+		if total <= ip.maxSize {
+			return
+		}
+
+		if e.path == keep || e.modTime.After(minModTime) {
+			continue
+		}
+
+		logger.Info("Removing cached image files", "path", e.path, "size", e.size)
+
+		if err = os.RemoveAll(e.path); err != nil {
+			logger.Info(utils.WarnString("Could not remove the cached image files"), "path", e.path, "error", err)
+			continue
+		}
+
+		total -= e.size
+
+		// remove the digest directory if it is now empty; this fails harmlessly otherwise
+		_ = os.Remove(filepath.Dir(e.path))
+	}
+}
+
+// dirSize returns the total size of the regular files under dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size += fi.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+// extractPaths writes the entries of tr that are under one of paths into dst.
+// Symbolic links are not extracted, so that no entry can point outside dst.
+func extractPaths(tr *tar.Reader, dst string, paths []string, logger logr.Logger) error {
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read the image filesystem: %v", err)
+		}
+
+		p := filepath.Clean("/" + hdr.Name)
+
+		if !underAny(p, paths) {
+			continue
+		}
+
+		target := filepath.Join(dst, p)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("could not create directory %s: %v", target, err)
+			}
+		case tar.TypeReg:
+			if err = writeFile(target, tr, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			src := filepath.Join(dst, filepath.Clean("/"+hdr.Linkname))
+
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("could not create directory %s: %v", filepath.Dir(target), err)
+			}
+
+			if err = os.Link(src, target); err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					logger.V(1).Info("Hard link target was not extracted; skipping", "path", p, "target", hdr.Linkname)
+					continue
+				}
+
+				return fmt.Errorf("could not create hard link %s: %v", target, err)
+			}
+		default:
+			logger.V(1).Info("Unsupported file type; skipping", "path", p, "type", string(hdr.Typeflag))
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create directory %s: %v", filepath.Dir(path), err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(f, r); err != nil {
+		return fmt.Errorf("could not write %s: %v", path, err)
+	}
+
+	return f.Close()
+}
+
+// underAny returns true if p is one of paths or is under one of them.
+func underAny(p string, paths []string) bool {
+	for _, prefix := range paths {
+		if p == prefix || strings.HasPrefix(p, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,209 @@
+package worker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImagePaths", func() {
+	It("should return the modules, firmware and hooks paths", func() {
+		cfg := kmmv1beta1.ModuleConfig{
+			KernelVersion: "6.0.0",
+			Modprobe: kmmv1beta1.ModprobeSpec{
+				DirName:      "/opt",
+				FirmwarePath: "/firmware",
+				Hooks: &kmmv1beta1.ModprobeHooks{
+					PreLoad:    &kmmv1beta1.ModprobeHook{Command: []string{"/bin/pre-load", "arg"}},
+					PostUnload: &kmmv1beta1.ModprobeHook{Command: []string{"/bin/post-unload"}},
+				},
+			},
+		}
+
+		Expect(
+			ImagePaths(&cfg),
+		).To(
+			Equal([]string{"/opt/lib/modules/6.0.0", "/firmware", "/bin/pre-load", "/bin/post-unload"}),
+		)
+	})
+})
+
+var _ = Describe("imagePullerImpl_PullImage", func() {
+	var (
+		image     string
+		imagesDir string
+		ip        ImagePuller
+	)
+
+	BeforeEach(func() {
+		server := httptest.NewServer(registry.New())
+		DeferCleanup(server.Close)
+
+		image = strings.TrimPrefix(server.URL, "http://") + "/kmod:latest"
+
+		var buf bytes.Buffer
+
+		tw := tar.NewWriter(&buf)
+
+		files := []struct {
+			name     string
+			typeflag byte
+			content  string
+			linkname string
+		}{
+			{name: "opt/lib/modules/6.0.0/", typeflag: tar.TypeDir},
+			{name: "opt/lib/modules/6.0.0/kmm.ko", typeflag: tar.TypeReg, content: "module"},
+			{name: "opt/lib/modules/6.0.0/kmm-link.ko", typeflag: tar.TypeLink, linkname: "opt/lib/modules/6.0.0/kmm.ko"},
+			{name: "opt/lib/modules/6.0.0/escape", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+			{name: "opt/lib/modules/5.0.0/old.ko", typeflag: tar.TypeReg, content: "old"},
+			{name: "firmware/fw.bin", typeflag: tar.TypeReg, content: "firmware"},
+			{name: "bin/sh", typeflag: tar.TypeReg, content: "shell"},
+		}
+
+		for _, f := range files {
+			hdr := &tar.Header{
+				Name:     f.name,
+				Typeflag: f.typeflag,
+				Linkname: f.linkname,
+				Mode:     0644,
+				Size:     int64(len(f.content)),
+			}
+
+			Expect(tw.WriteHeader(hdr)).To(Succeed())
+
+			_, err := tw.Write([]byte(f.content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(tw.Close()).To(Succeed())
+
+		layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err := mutate.AppendLayers(empty.Image, layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		ref, err := name.ParseReference(image, name.Insecure)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(remote.Write(ref, img)).To(Succeed())
+
+		imagesDir = GinkgoT().TempDir()
+		ip = NewImagePuller(imagesDir, 0, authn.DefaultKeychain, GinkgoLogr)
+	})
+
+	ctx := context.TODO()
+
+	It("should extract only the requested paths", func() {
+		dir, err := ip.PullImage(ctx, image, true, []string{"/opt/lib/modules/6.0.0", "firmware"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dir).To(HavePrefix(filepath.Join(imagesDir, "sha256")))
+
+		Expect(os.ReadFile(filepath.Join(dir, "opt/lib/modules/6.0.0/kmm.ko"))).To(Equal([]byte("module")))
+		Expect(os.ReadFile(filepath.Join(dir, "opt/lib/modules/6.0.0/kmm-link.ko"))).To(Equal([]byte("module")))
+		Expect(os.ReadFile(filepath.Join(dir, "firmware/fw.bin"))).To(Equal([]byte("firmware")))
+
+		Expect(filepath.Join(dir, "opt/lib/modules/6.0.0/escape")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dir, "opt/lib/modules/5.0.0")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dir, "bin")).NotTo(BeAnExistingFile())
+	})
+
+	It("should reuse the cached files", func() {
+		paths := []string{"/opt/lib/modules/6.0.0"}
+
+		dir, err := ip.PullImage(ctx, image, true, paths)
+		Expect(err).NotTo(HaveOccurred())
+
+		marker := filepath.Join(dir, "marker")
+		Expect(os.WriteFile(marker, nil, 0644)).To(Succeed())
+
+		Expect(ip.PullImage(ctx, image, true, paths)).To(Equal(dir))
+		Expect(marker).To(BeAnExistingFile())
+
+		otherDir, err := ip.PullImage(ctx, image, true, []string{"/firmware"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(otherDir).NotTo(Equal(dir))
+	})
+
+	It("should remove the least recently used entries once the cache is too large", func() {
+		ip = NewImagePuller(imagesDir, 10, authn.DefaultKeychain, GinkgoLogr)
+
+		old := time.Now().Add(-time.Hour)
+
+		newEntry := func(digest, content string, modTime time.Time) string {
+			dir := filepath.Join(imagesDir, "sha256", digest, "0123456789abcdef")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "file"), []byte(content), 0644)).To(Succeed())
+			Expect(os.Chtimes(dir, modTime, modTime)).To(Succeed())
+
+			return dir
+		}
+
+		oldest := newEntry("oldest", "0123456789", old.Add(-time.Minute))
+		older := newEntry("older", "0123456789", old)
+		recent := newEntry("recent", "0123456789", time.Now())
+
+		dir, err := ip.PullImage(ctx, image, true, []string{"/opt/lib/modules/6.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dir).To(BeADirectory())
+		Expect(recent).To(BeADirectory())
+		Expect(oldest).NotTo(BeAnExistingFile())
+		Expect(filepath.Dir(oldest)).NotTo(BeAnExistingFile())
+		Expect(older).NotTo(BeAnExistingFile())
+	})
+
+	It("should not remove entries while the cache is small enough", func() {
+		ip = NewImagePuller(imagesDir, 1<<20, authn.DefaultKeychain, GinkgoLogr)
+
+		old := time.Now().Add(-time.Hour)
+		entry := filepath.Join(imagesDir, "sha256", "old", "0123456789abcdef")
+		Expect(os.MkdirAll(entry, 0755)).To(Succeed())
+		Expect(os.Chtimes(entry, old, old)).To(Succeed())
+
+		_, err := ip.PullImage(ctx, image, true, []string{"/opt/lib/modules/6.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(entry).To(BeADirectory())
+	})
+
+	It("should mark cached files as recently used", func() {
+		paths := []string{"/opt/lib/modules/6.0.0"}
+
+		dir, err := ip.PullImage(ctx, image, true, paths)
+		Expect(err).NotTo(HaveOccurred())
+
+		old := time.Now().Add(-time.Hour)
+		Expect(os.Chtimes(dir, old, old)).To(Succeed())
+
+		Expect(ip.PullImage(ctx, image, true, paths)).To(Equal(dir))
+
+		fi, err := os.Stat(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fi.ModTime()).To(BeTemporally(">", old))
+	})
+
+	It("should return an error if the image does not exist", func() {
+		_, err := ip.PullImage(ctx, strings.TrimSuffix(image, "kmod:latest")+"missing:latest", true, []string{"/opt"})
+		Expect(err).To(HaveOccurred())
+	})
+})
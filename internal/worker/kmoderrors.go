@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	ErrArchMismatch        = errors.New("module architecture does not match the node")
+	ErrFirmwareConflict    = errors.New("firmware file was installed with different contents by another Module")
+	ErrHookFailed          = errors.New("hook failed")
+	ErrInvalidModuleFormat = errors.New("invalid module format; the module was probably built for another kernel")
+	ErrInvalidSignature    = errors.New("module signature is malformed")
+	ErrKernelMismatch      = errors.New("module was not built for the running kernel")
+	ErrLivepatchTransition = errors.New("livepatch transition did not complete")
+	ErrMissingDependency   = errors.New("module dependency is not available")
+	ErrModuleBusy          = errors.New("module is in use")
+	ErrModuleExists        = errors.New("module is already loaded")
+	ErrModuleNotFound      = errors.New("module not found")
+	ErrModuleNotSigned     = errors.New("module is not signed")
+	ErrModuleNotLoaded     = errors.New("module is not loaded")
+	ErrParamNotWritable    = errors.New("module parameter cannot be changed at runtime")
+	ErrSignatureMismatch   = errors.New("module signature does not match the certificate")
+	ErrSignatureRejected   = errors.New("module signature was rejected by the kernel")
+	ErrUnknownSymbol       = errors.New("module references an unknown symbol")
+)
+
+// KmodError is returned when the kernel refuses to load or unload a module.
+// It wraps one of the Err* sentinel errors when the errno could be classified.
+type KmodError struct {
+	Op     string
+	Module string
+	Errno  unix.Errno
+	Err    error
+}
+
+func (e *KmodError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("could not %s module %s: %v (%v)", e.Op, e.Module, e.Err, e.Errno)
+	}
+
+	return fmt.Sprintf("could not %s module %s: %v", e.Op, e.Module, e.Errno)
+}
+
+func (e *KmodError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	kmodOpLoad   = "load"
+	kmodOpUnload = "unload"
+)
+
+// newKmodError classifies the error returned by finit_module(2) or delete_module(2).
+// Errors that are not an errno are returned unchanged.
+func newKmodError(op, module string, err error) error {
+	var errno unix.Errno
+
+	if !errors.As(err, &errno) {
+		return err
+	}
+
+	ke := &KmodError{Op: op, Module: module, Errno: errno}
+
+	switch {
+	case errno == unix.EEXIST:
+		ke.Err = ErrModuleExists
+	case errno == unix.EKEYREJECTED:
+		ke.Err = ErrSignatureRejected
+	case errno == unix.ENOEXEC:
+		ke.Err = ErrInvalidModuleFormat
+	case op == kmodOpLoad && errno == unix.ENOENT:
+		ke.Err = ErrUnknownSymbol
+	case op == kmodOpUnload && errno == unix.ENOENT:
+		ke.Err = ErrModuleNotLoaded
+	case errno == unix.EBUSY || errno == unix.EAGAIN:
+		ke.Err = ErrModuleBusy
+	}
+
+	return ke
+}
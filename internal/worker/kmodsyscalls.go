@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+//go:generate mockgen -source=kmodsyscalls.go -package=worker -destination=mock_kmodsyscalls.go
+
+// KmodSyscalls wraps the system calls and kernel interfaces used to load and unload kernel modules without kmod.
+type KmodSyscalls interface {
+	FinitModule(path, params string) error
+	DeleteModule(name string, force bool) error
+	IsModuleLoaded(name string) (bool, error)
+	KernelRelease() (string, error)
+}
+
+// moduleInitCompressedFile is MODULE_INIT_COMPRESSED_FILE from include/uapi/linux/module.h.
+// It asks the kernel to decompress the module file itself.
+const moduleInitCompressedFile = 4
+
+var sysModuleDir = "/sys/module"
+
+type kmodSyscallsImpl struct{}
+
+func NewKmodSyscalls() KmodSyscalls {
+	return &kmodSyscallsImpl{}
+}
+
+func (k *kmodSyscallsImpl) FinitModule(path, params string) error {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	flags := 0
+
+	if filepath.Ext(path) != ".ko" {
+		flags |= moduleInitCompressedFile
+	}
+
+	return unix.FinitModule(fd, params, flags)
+}
+
+func (k *kmodSyscallsImpl) DeleteModule(name string, force bool) error {
+	flags := unix.O_NONBLOCK
+
+	if force {
+		flags |= unix.O_TRUNC
+	}
+
+	return unix.DeleteModule(name, flags)
+}
+
+// IsModuleLoaded returns true if the module is present in the kernel and is not built-in.
+// Built-in modules have a directory under /sys/module, but no initstate file.
+func (k *kmodSyscallsImpl) IsModuleLoaded(name string) (bool, error) {
+	b, err := os.ReadFile(filepath.Join(sysModuleDir, name, "initstate"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("could not read the initstate of module %s: %v", name, err)
+	}
+
+	return strings.TrimSpace(string(b)) != "going", nil
+}
+
+func (k *kmodSyscallsImpl) KernelRelease() (string, error) {
+	var uts unix.Utsname
+
+	if err := unix.Uname(&uts); err != nil {
+		return "", fmt.Errorf("could not call uname: %v", err)
+	}
+
+	return unix.ByteSliceToString(uts.Release[:]), nil
+}
@@ -0,0 +1,176 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sys/unix"
+)
+
+//go:generate mockgen -source=kmsg.go -package=worker -destination=mock_kmsg.go
+
+// KernelLog gives access to the kernel ring buffer.
+type KernelLog interface {
+	// Follow returns a stream of the records that the kernel logs from now on, in the /dev/kmsg format.
+	// Reading the stream returns io.EOF once all available records have been read.
+	Follow() (io.ReadCloser, error)
+}
+
+const (
+	// kmsgRecordMaxLength is the size of the buffer that the kernel requires to read one record from /dev/kmsg.
+	kmsgRecordMaxLength = 8192
+
+	// maxKernelLogLines is the maximum number of kernel log lines attached to an error.
+	maxKernelLogLines = 20
+)
+
+var kmsgPath = "/dev/kmsg"
+
+type kernelLogImpl struct{}
+
+func NewKernelLog() KernelLog {
+	return &kernelLogImpl{}
+}
+
+func (k *kernelLogImpl) Follow() (io.ReadCloser, error) {
+	// os.File would wait on the poller instead of returning EAGAIN, so use the file descriptor directly.
+	fd, err := unix.Open(kmsgPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", kmsgPath, err)
+	}
+
+	if _, err = unix.Seek(fd, 0, io.SeekEnd); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("could not seek to the end of %s: %v", kmsgPath, err)
+	}
+
+	return &kmsgFile{fd: fd, buf: make([]byte, kmsgRecordMaxLength)}, nil
+}
+
+// kmsgFile reads whole records from /dev/kmsg, which rejects reads with a buffer smaller than the next record.
+type kmsgFile struct {
+	fd      int
+	buf     []byte
+	pending []byte
+}
+
+func (k *kmsgFile) Read(p []byte) (int, error) {
+	for len(k.pending) == 0 {
+		n, err := unix.Read(k.fd, k.buf)
+
+		switch {
+		case errors.Is(err, unix.EAGAIN):
+			return 0, io.EOF
+		case errors.Is(err, unix.EPIPE):
+			// Records were overwritten before we could read them; the next read returns the oldest available one.
+			continue
+		case err != nil:
+			return 0, fmt.Errorf("could not read %s: %v", kmsgPath, err)
+		}
+
+		k.pending = k.buf[:n]
+	}
+
+	n := copy(p, k.pending)
+	k.pending = k.pending[n:]
+
+	return n, nil
+}
+
+func (k *kmsgFile) Close() error {
+	return unix.Close(k.fd)
+}
+
+// parseKernelLog returns the messages of the kernel facility in a stream of /dev/kmsg records, as described in
+// Documentation/ABI/testing/dev-kmsg.
+// Records written by userspace to /dev/kmsg, such as the ones from systemd, are left out.
+func parseKernelLog(r io.Reader) ([]string, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, kmsgRecordMaxLength), kmsgRecordMaxLength)
+
+	messages := make([]string, 0)
+
+	for s.Scan() {
+		line := s.Text()
+
+		// Continuation lines carry key/value pairs about the previous record.
+		if strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		prefix, msg, ok := strings.Cut(line, ";")
+		if !ok {
+			continue
+		}
+
+		prioStr, _, _ := strings.Cut(prefix, ",")
+
+		prio, err := strconv.Atoi(prioStr)
+		if err != nil {
+			continue
+		}
+
+		// The priority is the facility shifted by 3 bits plus the level; the kernel facility is 0.
+		if prio>>3 != 0 {
+			continue
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, s.Err()
+}
+
+type kernelLogModprobeRunner struct {
+	kl     KernelLog
+	logger logr.Logger
+	mr     ModprobeRunner
+}
+
+// NewKernelLogModprobeRunner returns a ModprobeRunner that runs mr and collects the messages that the kernel logs in
+// the meantime.
+// Those messages are logged, and the last ones are added to the error returned by mr.
+// If the kernel log cannot be read, mr is run without capturing the kernel log.
+func NewKernelLogModprobeRunner(mr ModprobeRunner, kl KernelLog, logger logr.Logger) ModprobeRunner {
+	return &kernelLogModprobeRunner{
+		kl:     kl,
+		logger: logger.WithName("kernel"),
+		mr:     mr,
+	}
+}
+
+func (kr *kernelLogModprobeRunner) Run(ctx context.Context, args ...string) error {
+	stream, err := kr.kl.Follow()
+	if err != nil {
+		kr.logger.V(1).Info("Could not read the kernel log; not capturing it", "error", err)
+		return kr.mr.Run(ctx, args...)
+	}
+	defer stream.Close()
+
+	runErr := kr.mr.Run(ctx, args...)
+
+	messages, err := parseKernelLog(stream)
+	if err != nil {
+		kr.logger.Info("Could not read all kernel log messages", "error", err)
+	}
+
+	for _, m := range messages {
+		kr.logger.Info(m)
+	}
+
+	if runErr == nil || len(messages) == 0 {
+		return runErr
+	}
+
+	if len(messages) > maxKernelLogLines {
+		messages = messages[len(messages)-maxKernelLogLines:]
+	}
+
+	return fmt.Errorf("%w; kernel log:\n%s", runErr, strings.Join(messages, "\n"))
+}
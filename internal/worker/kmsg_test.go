@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("parseKernelLog", func() {
+	It("should return the messages of the kernel facility", func() {
+		const stream = `6,1001,5000000,-;kmm_ci_a: loading out-of-tree module taints kernel.
+ SUBSYSTEM=module
+ DEVICE=+module:kmm_ci_a
+30,1002,5000100,-;systemd[1]: Started something.
+3,1003,5000200,c;kmm_ci_a: Unknown symbol some_symbol (err -2)
+not a record
+`
+
+		Expect(
+			parseKernelLog(strings.NewReader(stream)),
+		).To(
+			Equal([]string{
+				"kmm_ci_a: loading out-of-tree module taints kernel.",
+				"kmm_ci_a: Unknown symbol some_symbol (err -2)",
+			}),
+		)
+	})
+
+	It("should return an empty slice for an empty stream", func() {
+		Expect(
+			parseKernelLog(strings.NewReader("")),
+		).To(
+			BeEmpty(),
+		)
+	})
+})
+
+var _ = Describe("kernelLogModprobeRunner_Run", func() {
+	var (
+		ctrl   *gomock.Controller
+		mockKL *MockKernelLog
+		mockMR *MockModprobeRunner
+		mr     ModprobeRunner
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockKL = NewMockKernelLog(ctrl)
+		mockMR = NewMockModprobeRunner(ctrl)
+		mr = NewKernelLogModprobeRunner(mockMR, mockKL, GinkgoLogr)
+	})
+
+	ctx := context.TODO()
+
+	It("should run modprobe if the kernel log cannot be read", func() {
+		gomock.InOrder(
+			mockKL.EXPECT().Follow().Return(nil, errors.New("random error")),
+			mockMR.EXPECT().Run(ctx, "-v", "mod"),
+		)
+
+		Expect(
+			mr.Run(ctx, "-v", "mod"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should not change the error if the kernel logged nothing", func() {
+		modprobeErr := errors.New("modprobe error")
+
+		gomock.InOrder(
+			mockKL.EXPECT().Follow().Return(io.NopCloser(strings.NewReader("")), nil),
+			mockMR.EXPECT().Run(ctx, "-v", "mod").Return(modprobeErr),
+		)
+
+		Expect(
+			mr.Run(ctx, "-v", "mod"),
+		).To(
+			Equal(modprobeErr),
+		)
+	})
+
+	It("should not return an error if modprobe succeeded", func() {
+		gomock.InOrder(
+			mockKL.EXPECT().Follow().Return(io.NopCloser(strings.NewReader("6,1,1,-;mod: loaded\n")), nil),
+			mockMR.EXPECT().Run(ctx, "-v", "mod"),
+		)
+
+		Expect(
+			mr.Run(ctx, "-v", "mod"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should add the last kernel log lines to the error", func() {
+		sb := strings.Builder{}
+
+		for i := 0; i < maxKernelLogLines+5; i++ {
+			fmt.Fprintf(&sb, "6,%d,1,-;line %d\n", i, i)
+		}
+
+		gomock.InOrder(
+			mockKL.EXPECT().Follow().Return(io.NopCloser(strings.NewReader(sb.String())), nil),
+			mockMR.EXPECT().Run(ctx, "-v", "mod").Return(ErrUnknownSymbol),
+		)
+
+		err := mr.Run(ctx, "-v", "mod")
+		Expect(err).To(MatchError(ErrUnknownSymbol))
+		Expect(err.Error()).To(ContainSubstring("kernel log:\nline 5\n"))
+		Expect(err.Error()).To(HaveSuffix("\nline 24"))
+		Expect(err.Error()).NotTo(ContainSubstring("line 4\n"))
+	})
+})
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const defaultLivepatchTransitionTimeout = 300 * time.Second
+
+var (
+	sysLivepatchDir           = "/sys/kernel/livepatch"
+	livepatchPollInterval     = time.Second
+	errLivepatchNotRegistered = errors.New("livepatch is not registered")
+)
+
+// readLivepatchStatus reads the state of a livepatch from sysfs.
+// It returns an error wrapping errLivepatchNotRegistered if the kernel does not know the patch.
+func readLivepatchStatus(name string) (*kmmv1beta1.LivepatchStatus, error) {
+	dir := filepath.Join(sysLivepatchDir, normalizeModuleName(name))
+
+	enabled, err := readSysfsBool(filepath.Join(dir, "enabled"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", errLivepatchNotRegistered, name)
+		}
+
+		return nil, fmt.Errorf("could not read the state of livepatch %s: %v", name, err)
+	}
+
+	transition, err := readSysfsBool(filepath.Join(dir, "transition"))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the transition state of livepatch %s: %v", name, err)
+	}
+
+	return &kmmv1beta1.LivepatchStatus{Enabled: enabled, Transition: transition}, nil
+}
+
+func disableLivepatch(name string) error {
+	path := filepath.Join(sysLivepatchDir, normalizeModuleName(name), "enabled")
+
+	if err := os.WriteFile(path, []byte("0"), 0644); err != nil {
+		return fmt.Errorf("could not disable livepatch %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// waitForLivepatchTransition polls the state of a livepatch until its transition completes or timeout expires.
+// It returns the last state that it read, even if the transition did not complete.
+func waitForLivepatchTransition(ctx context.Context, name string, timeout time.Duration) (*kmmv1beta1.LivepatchStatus, error) {
+	var status *kmmv1beta1.LivepatchStatus
+
+	err := wait.PollUntilContextTimeout(ctx, livepatchPollInterval, timeout, true, func(_ context.Context) (bool, error) {
+		var err error
+
+		if status, err = readLivepatchStatus(name); err != nil {
+			return false, err
+		}
+
+		return !status.Transition, nil
+	})
+
+	if wait.Interrupted(err) {
+		return status, fmt.Errorf("%w: livepatch %s is still in transition after %v", ErrLivepatchTransition, name, timeout)
+	}
+
+	return status, err
+}
+
+func livepatchTransitionTimeout(lp *kmmv1beta1.Livepatch) time.Duration {
+	if lp.TransitionTimeoutSeconds > 0 {
+		return time.Duration(lp.TransitionTimeoutSeconds) * time.Second
+	}
+
+	return defaultLivepatchTransitionTimeout
+}
+
+func readSysfsBool(path string) (bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(string(b)) == "1", nil
+}
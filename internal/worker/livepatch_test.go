@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// setLivepatchState creates the sysfs files of a livepatch in sysLivepatchDir.
+func setLivepatchState(name, enabled, transition string) {
+	GinkgoHelper()
+
+	dir := filepath.Join(sysLivepatchDir, name)
+
+	Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "enabled"), []byte(enabled+"\n"), 0644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "transition"), []byte(transition+"\n"), 0644)).To(Succeed())
+}
+
+var _ = Describe("waitForLivepatchTransition", func() {
+	ctx := context.TODO()
+
+	BeforeEach(func() {
+		sysLivepatchDir = GinkgoT().TempDir()
+		livepatchPollInterval = time.Millisecond
+
+		DeferCleanup(func() {
+			sysLivepatchDir = "/sys/kernel/livepatch"
+			livepatchPollInterval = time.Second
+		})
+	})
+
+	It("should return an error if the livepatch is not registered", func() {
+		_, err := waitForLivepatchTransition(ctx, "kmm-lp", time.Second)
+		Expect(err).To(MatchError(errLivepatchNotRegistered))
+	})
+
+	It("should return the state once the transition is complete", func() {
+		setLivepatchState("kmm_lp", "1", "1")
+
+		go func() {
+			defer GinkgoRecover()
+
+			time.Sleep(10 * time.Millisecond)
+
+			// Replace the file atomically, so that it is never read empty.
+			Expect(
+				writeFileAtomic(filepath.Join(sysLivepatchDir, "kmm_lp", "transition"), strings.NewReader("0\n"), 0644),
+			).To(
+				Succeed(),
+			)
+		}()
+
+		Expect(
+			waitForLivepatchTransition(ctx, "kmm-lp", 5*time.Second),
+		).To(
+			Equal(&kmmv1beta1.LivepatchStatus{Enabled: true}),
+		)
+	})
+
+	It("should return ErrLivepatchTransition and the last state if the transition does not complete", func() {
+		setLivepatchState("kmm_lp", "0", "1")
+
+		status, err := waitForLivepatchTransition(ctx, "kmm_lp", 20*time.Millisecond)
+		Expect(err).To(MatchError(ErrLivepatchTransition))
+		Expect(status).To(Equal(&kmmv1beta1.LivepatchStatus{Transition: true}))
+	})
+})
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: firmware.go
+//
+// Generated by this command:
+//
+//	mockgen -source=firmware.go -package=worker -destination=mock_firmware.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFirmwareManager is a mock of FirmwareManager interface.
+type MockFirmwareManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockFirmwareManagerMockRecorder
+}
+
+// MockFirmwareManagerMockRecorder is the mock recorder for MockFirmwareManager.
+type MockFirmwareManagerMockRecorder struct {
+	mock *MockFirmwareManager
+}
+
+// NewMockFirmwareManager creates a new mock instance.
+func NewMockFirmwareManager(ctrl *gomock.Controller) *MockFirmwareManager {
+	mock := &MockFirmwareManager{ctrl: ctrl}
+	mock.recorder = &MockFirmwareManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFirmwareManager) EXPECT() *MockFirmwareManagerMockRecorder {
+	return m.recorder
+}
+
+// Install mocks base method.
+func (m *MockFirmwareManager) Install(owner, srcDir, dstDir string, files []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Install", owner, srcDir, dstDir, files)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Install indicates an expected call of Install.
+func (mr *MockFirmwareManagerMockRecorder) Install(owner, srcDir, dstDir, files any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Install", reflect.TypeOf((*MockFirmwareManager)(nil).Install), owner, srcDir, dstDir, files)
+}
+
+// Remove mocks base method.
+func (m *MockFirmwareManager) Remove(owner, srcDir, dstDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", owner, srcDir, dstDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockFirmwareManagerMockRecorder) Remove(owner, srcDir, dstDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFirmwareManager)(nil).Remove), owner, srcDir, dstDir)
+}
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: hooks.go
+//
+// Generated by this command:
+//
+//	mockgen -source=hooks.go -package=worker -destination=mock_hooks.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHookRunner is a mock of HookRunner interface.
+type MockHookRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockHookRunnerMockRecorder
+}
+
+// MockHookRunnerMockRecorder is the mock recorder for MockHookRunner.
+type MockHookRunnerMockRecorder struct {
+	mock *MockHookRunner
+}
+
+// NewMockHookRunner creates a new mock instance.
+func NewMockHookRunner(ctrl *gomock.Controller) *MockHookRunner {
+	mock := &MockHookRunner{ctrl: ctrl}
+	mock.recorder = &MockHookRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHookRunner) EXPECT() *MockHookRunnerMockRecorder {
+	return m.recorder
+}
+
+// RunHook mocks base method.
+func (m *MockHookRunner) RunHook(ctx context.Context, name string, hook *v1beta1.ModprobeHook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunHook", ctx, name, hook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunHook indicates an expected call of RunHook.
+func (mr *MockHookRunnerMockRecorder) RunHook(ctx, name, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunHook", reflect.TypeOf((*MockHookRunner)(nil).RunHook), ctx, name, hook)
+}
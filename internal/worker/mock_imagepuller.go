@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: imagepuller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=imagepuller.go -package=worker -destination=mock_imagepuller.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImagePuller is a mock of ImagePuller interface.
+type MockImagePuller struct {
+	ctrl     *gomock.Controller
+	recorder *MockImagePullerMockRecorder
+}
+
+// MockImagePullerMockRecorder is the mock recorder for MockImagePuller.
+type MockImagePullerMockRecorder struct {
+	mock *MockImagePuller
+}
+
+// NewMockImagePuller creates a new mock instance.
+func NewMockImagePuller(ctrl *gomock.Controller) *MockImagePuller {
+	mock := &MockImagePuller{ctrl: ctrl}
+	mock.recorder = &MockImagePullerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImagePuller) EXPECT() *MockImagePullerMockRecorder {
+	return m.recorder
+}
+
+// PullImage mocks base method.
+func (m *MockImagePuller) PullImage(ctx context.Context, image string, insecure bool, paths []string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullImage", ctx, image, insecure, paths)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullImage indicates an expected call of PullImage.
+func (mr *MockImagePullerMockRecorder) PullImage(ctx, image, insecure, paths any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullImage", reflect.TypeOf((*MockImagePuller)(nil).PullImage), ctx, image, insecure, paths)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: kmodsyscalls.go
+//
+// Generated by this command:
+//
+//	mockgen -source=kmodsyscalls.go -package=worker -destination=mock_kmodsyscalls.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKmodSyscalls is a mock of KmodSyscalls interface.
+type MockKmodSyscalls struct {
+	ctrl     *gomock.Controller
+	recorder *MockKmodSyscallsMockRecorder
+}
+
+// MockKmodSyscallsMockRecorder is the mock recorder for MockKmodSyscalls.
+type MockKmodSyscallsMockRecorder struct {
+	mock *MockKmodSyscalls
+}
+
+// NewMockKmodSyscalls creates a new mock instance.
+func NewMockKmodSyscalls(ctrl *gomock.Controller) *MockKmodSyscalls {
+	mock := &MockKmodSyscalls{ctrl: ctrl}
+	mock.recorder = &MockKmodSyscallsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKmodSyscalls) EXPECT() *MockKmodSyscallsMockRecorder {
+	return m.recorder
+}
+
+// DeleteModule mocks base method.
+func (m *MockKmodSyscalls) DeleteModule(name string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModule", name, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModule indicates an expected call of DeleteModule.
+func (mr *MockKmodSyscallsMockRecorder) DeleteModule(name, force any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModule", reflect.TypeOf((*MockKmodSyscalls)(nil).DeleteModule), name, force)
+}
+
+// FinitModule mocks base method.
+func (m *MockKmodSyscalls) FinitModule(path, params string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinitModule", path, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinitModule indicates an expected call of FinitModule.
+func (mr *MockKmodSyscallsMockRecorder) FinitModule(path, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinitModule", reflect.TypeOf((*MockKmodSyscalls)(nil).FinitModule), path, params)
+}
+
+// IsModuleLoaded mocks base method.
+func (m *MockKmodSyscalls) IsModuleLoaded(name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsModuleLoaded", name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsModuleLoaded indicates an expected call of IsModuleLoaded.
+func (mr *MockKmodSyscallsMockRecorder) IsModuleLoaded(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsModuleLoaded", reflect.TypeOf((*MockKmodSyscalls)(nil).IsModuleLoaded), name)
+}
+
+// KernelRelease mocks base method.
+func (m *MockKmodSyscalls) KernelRelease() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KernelRelease")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KernelRelease indicates an expected call of KernelRelease.
+func (mr *MockKmodSyscallsMockRecorder) KernelRelease() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KernelRelease", reflect.TypeOf((*MockKmodSyscalls)(nil).KernelRelease))
+}
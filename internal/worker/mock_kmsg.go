@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: kmsg.go
+//
+// Generated by this command:
+//
+//	mockgen -source=kmsg.go -package=worker -destination=mock_kmsg.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKernelLog is a mock of KernelLog interface.
+type MockKernelLog struct {
+	ctrl     *gomock.Controller
+	recorder *MockKernelLogMockRecorder
+}
+
+// MockKernelLogMockRecorder is the mock recorder for MockKernelLog.
+type MockKernelLogMockRecorder struct {
+	mock *MockKernelLog
+}
+
+// NewMockKernelLog creates a new mock instance.
+func NewMockKernelLog(ctrl *gomock.Controller) *MockKernelLog {
+	mock := &MockKernelLog{ctrl: ctrl}
+	mock.recorder = &MockKernelLogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKernelLog) EXPECT() *MockKernelLogMockRecorder {
+	return m.recorder
+}
+
+// Follow mocks base method.
+func (m *MockKernelLog) Follow() (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow")
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockKernelLogMockRecorder) Follow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockKernelLog)(nil).Follow))
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modcheck.go
+//
+// Generated by this command:
+//
+//	mockgen -source=modcheck.go -package=worker -destination=mock_modcheck.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	v1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModuleChecker is a mock of ModuleChecker interface.
+type MockModuleChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockModuleCheckerMockRecorder
+}
+
+// MockModuleCheckerMockRecorder is the mock recorder for MockModuleChecker.
+type MockModuleCheckerMockRecorder struct {
+	mock *MockModuleChecker
+}
+
+// NewMockModuleChecker creates a new mock instance.
+func NewMockModuleChecker(ctrl *gomock.Controller) *MockModuleChecker {
+	mock := &MockModuleChecker{ctrl: ctrl}
+	mock.recorder = &MockModuleCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModuleChecker) EXPECT() *MockModuleCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckModule mocks base method.
+func (m *MockModuleChecker) CheckModule(rootDir, moduleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckModule", rootDir, moduleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckModule indicates an expected call of CheckModule.
+func (mr *MockModuleCheckerMockRecorder) CheckModule(rootDir, moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckModule", reflect.TypeOf((*MockModuleChecker)(nil).CheckModule), rootDir, moduleName)
+}
+
+// LoadedModules mocks base method.
+func (m *MockModuleChecker) LoadedModules(rootDir, moduleName string) ([]v1beta1.LoadedKernelModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadedModules", rootDir, moduleName)
+	ret0, _ := ret[0].([]v1beta1.LoadedKernelModule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadedModules indicates an expected call of LoadedModules.
+func (mr *MockModuleCheckerMockRecorder) LoadedModules(rootDir, moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadedModules", reflect.TypeOf((*MockModuleChecker)(nil).LoadedModules), rootDir, moduleName)
+}
+
+// ModuleParameters mocks base method.
+func (m *MockModuleChecker) ModuleParameters(moduleName string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModuleParameters", moduleName)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModuleParameters indicates an expected call of ModuleParameters.
+func (mr *MockModuleCheckerMockRecorder) ModuleParameters(moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleParameters", reflect.TypeOf((*MockModuleChecker)(nil).ModuleParameters), moduleName)
+}
+
+// ModuleState mocks base method.
+func (m *MockModuleChecker) ModuleState(moduleName string) (*ModuleState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModuleState", moduleName)
+	ret0, _ := ret[0].(*ModuleState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModuleState indicates an expected call of ModuleState.
+func (mr *MockModuleCheckerMockRecorder) ModuleState(moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleState", reflect.TypeOf((*MockModuleChecker)(nil).ModuleState), moduleName)
+}
+
+// ModuleUsage mocks base method.
+func (m *MockModuleChecker) ModuleUsage(moduleName string) (*ModuleUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModuleUsage", moduleName)
+	ret0, _ := ret[0].(*ModuleUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModuleUsage indicates an expected call of ModuleUsage.
+func (mr *MockModuleCheckerMockRecorder) ModuleUsage(moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleUsage", reflect.TypeOf((*MockModuleChecker)(nil).ModuleUsage), moduleName)
+}
+
+// VerifySignatures mocks base method.
+func (m *MockModuleChecker) VerifySignatures(rootDir, moduleName, certPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySignatures", rootDir, moduleName, certPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifySignatures indicates an expected call of VerifySignatures.
+func (mr *MockModuleCheckerMockRecorder) VerifySignatures(rootDir, moduleName, certPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySignatures", reflect.TypeOf((*MockModuleChecker)(nil).VerifySignatures), rootDir, moduleName, certPath)
+}
@@ -39,18 +39,49 @@ func (m *MockWorker) EXPECT() *MockWorkerMockRecorder {
 	return m.recorder
 }
 
+// KmodStatus mocks base method.
+func (m *MockWorker) KmodStatus(cfg *v1beta1.ModuleConfig, firmwareMountPath string) (*HostStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KmodStatus", cfg, firmwareMountPath)
+	ret0, _ := ret[0].(*HostStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KmodStatus indicates an expected call of KmodStatus.
+func (mr *MockWorkerMockRecorder) KmodStatus(cfg, firmwareMountPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KmodStatus", reflect.TypeOf((*MockWorker)(nil).KmodStatus), cfg, firmwareMountPath)
+}
+
 // LoadKmod mocks base method.
-func (m *MockWorker) LoadKmod(ctx context.Context, cfg *v1beta1.ModuleConfig, firmwareMountPath string) error {
+func (m *MockWorker) LoadKmod(ctx context.Context, cfg *v1beta1.ModuleConfig, opts KmodOptions) (*Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadKmod", ctx, cfg, firmwareMountPath)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "LoadKmod", ctx, cfg, opts)
+	ret0, _ := ret[0].(*Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // LoadKmod indicates an expected call of LoadKmod.
-func (mr *MockWorkerMockRecorder) LoadKmod(ctx, cfg, firmwareMountPath any) *gomock.Call {
+func (mr *MockWorkerMockRecorder) LoadKmod(ctx, cfg, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadKmod", reflect.TypeOf((*MockWorker)(nil).LoadKmod), ctx, cfg, opts)
+}
+
+// PlanLoadKmod mocks base method.
+func (m *MockWorker) PlanLoadKmod(cfg *v1beta1.ModuleConfig, firmwareMountPath string) (*LoadPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanLoadKmod", cfg, firmwareMountPath)
+	ret0, _ := ret[0].(*LoadPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanLoadKmod indicates an expected call of PlanLoadKmod.
+func (mr *MockWorkerMockRecorder) PlanLoadKmod(cfg, firmwareMountPath any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadKmod", reflect.TypeOf((*MockWorker)(nil).LoadKmod), ctx, cfg, firmwareMountPath)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanLoadKmod", reflect.TypeOf((*MockWorker)(nil).PlanLoadKmod), cfg, firmwareMountPath)
 }
 
 // SetFirmwareClassPath mocks base method.
@@ -67,16 +98,32 @@ func (mr *MockWorkerMockRecorder) SetFirmwareClassPath(value any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFirmwareClassPath", reflect.TypeOf((*MockWorker)(nil).SetFirmwareClassPath), value)
 }
 
+// SetKmodParameters mocks base method.
+func (m *MockWorker) SetKmodParameters(ctx context.Context, cfg *v1beta1.ModuleConfig) (*Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKmodParameters", ctx, cfg)
+	ret0, _ := ret[0].(*Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetKmodParameters indicates an expected call of SetKmodParameters.
+func (mr *MockWorkerMockRecorder) SetKmodParameters(ctx, cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKmodParameters", reflect.TypeOf((*MockWorker)(nil).SetKmodParameters), ctx, cfg)
+}
+
 // UnloadKmod mocks base method.
-func (m *MockWorker) UnloadKmod(ctx context.Context, cfg *v1beta1.ModuleConfig, firmwareMountPath string) error {
+func (m *MockWorker) UnloadKmod(ctx context.Context, cfg *v1beta1.ModuleConfig, opts KmodOptions) (*Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UnloadKmod", ctx, cfg, firmwareMountPath)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UnloadKmod", ctx, cfg, opts)
+	ret0, _ := ret[0].(*Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UnloadKmod indicates an expected call of UnloadKmod.
-func (mr *MockWorkerMockRecorder) UnloadKmod(ctx, cfg, firmwareMountPath any) *gomock.Call {
+func (mr *MockWorkerMockRecorder) UnloadKmod(ctx, cfg, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadKmod", reflect.TypeOf((*MockWorker)(nil).UnloadKmod), ctx, cfg, firmwareMountPath)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadKmod", reflect.TypeOf((*MockWorker)(nil).UnloadKmod), ctx, cfg, opts)
 }
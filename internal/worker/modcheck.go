@@ -0,0 +1,389 @@
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+//go:generate mockgen -source=modcheck.go -package=worker -destination=mock_modcheck.go
+
+// ModuleChecker verifies that the kernel modules shipped in an image can be loaded on the running kernel, and reports
+// what keeps loaded modules from being unloaded.
+type ModuleChecker interface {
+	CheckModule(rootDir, moduleName string) error
+	LoadedModules(rootDir, moduleName string) ([]kmmv1beta1.LoadedKernelModule, error)
+	ModuleParameters(moduleName string) (map[string]string, error)
+	ModuleState(moduleName string) (*ModuleState, error)
+	ModuleUsage(moduleName string) (*ModuleUsage, error)
+	VerifySignatures(rootDir, moduleName, certPath string) error
+}
+
+var (
+	nodeArch        = runtime.GOARCH
+	procModulesPath = "/proc/modules"
+)
+
+var goarchToMachine = map[string]elf.Machine{
+	"386":     elf.EM_386,
+	"amd64":   elf.EM_X86_64,
+	"arm64":   elf.EM_AARCH64,
+	"ppc64le": elf.EM_PPC64,
+	"s390x":   elf.EM_S390,
+}
+
+// moduleInfo contains the fields of a module's .modinfo section that are relevant to compatibility checks.
+type moduleInfo struct {
+	machine  elf.Machine
+	vermagic string
+	depends  []string
+}
+
+type moduleCheckerImpl struct {
+	logger logr.Logger
+	sc     KmodSyscalls
+}
+
+func NewModuleChecker(sc KmodSyscalls, logger logr.Logger) ModuleChecker {
+	return &moduleCheckerImpl{
+		logger: logger.WithName("module-checker"),
+		sc:     sc,
+	}
+}
+
+// CheckModule opens moduleName and every module that modprobe would load with it from
+// rootDir/lib/modules/<kernel release>, and verifies their vermagic, architecture and dependencies against the running
+// kernel.
+// Modules that are already loaded are not checked, because modprobe would not load them again.
+func (mc *moduleCheckerImpl) CheckModule(rootDir, moduleName string) error {
+	ml, err := mc.modulesToLoad(rootDir, moduleName)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ml.names {
+		path, _ := ml.db.path(m)
+
+		mi, err := readModuleInfo(path)
+		if err != nil {
+			if errors.Is(err, errUnsupportedCompression) {
+				mc.logger.Info(utils.WarnString("could not inspect compressed module; skipping the compatibility checks"), "path", path)
+				continue
+			}
+
+			return fmt.Errorf("could not read the module information of %s: %w", path, err)
+		}
+
+		if err = mc.checkModuleInfo(m, mi, ml.release, ml.db, ml.loaded); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VerifySignatures verifies the signature appended to moduleName and to every module that modprobe would load with
+// it, against the X.509 certificate in certPath.
+// Like CheckModule, modules that are already loaded are not verified.
+func (mc *moduleCheckerImpl) VerifySignatures(rootDir, moduleName, certPath string) error {
+	cert, err := readCertificate(certPath)
+	if err != nil {
+		return err
+	}
+
+	ml, err := mc.modulesToLoad(rootDir, moduleName)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ml.names {
+		path, _ := ml.db.path(m)
+
+		mc.logger.Info("Verifying module signature", "path", path, "certificate subject", cert.Subject.String())
+
+		if err = verifyModuleSignature(path, cert); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadedModules returns moduleName and the modules it depends on in rootDir that are currently loaded, with their
+// srcversion.
+func (mc *moduleCheckerImpl) LoadedModules(rootDir, moduleName string) ([]kmmv1beta1.LoadedKernelModule, error) {
+	release, err := mc.sc.KernelRelease()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine the kernel release: %v", err)
+	}
+
+	db, err := newModuleDB(rootDir, release)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the module database: %v", err)
+	}
+
+	order, err := db.loadOrder(moduleName)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve the dependencies of %s: %w", moduleName, err)
+	}
+
+	loaded, err := readLoadedModules()
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]kmmv1beta1.LoadedKernelModule, 0, len(order))
+
+	for _, m := range order {
+		if !loaded.Has(m) {
+			continue
+		}
+
+		srcVersion, err := readSrcVersion(m)
+		if err != nil {
+			return nil, err
+		}
+
+		modules = append(modules, kmmv1beta1.LoadedKernelModule{Name: m, SrcVersion: srcVersion})
+	}
+
+	return modules, nil
+}
+
+// ModuleParameters returns the readable parameters of moduleName from sysfs.
+func (mc *moduleCheckerImpl) ModuleParameters(moduleName string) (map[string]string, error) {
+	return readModuleParameters(moduleName)
+}
+
+// ModuleState returns the state of moduleName from sysfs.
+func (mc *moduleCheckerImpl) ModuleState(moduleName string) (*ModuleState, error) {
+	return readModuleState(moduleName)
+}
+
+// ModuleUsage returns the reference count and the holders of moduleName, or nil if it is not loaded.
+func (mc *moduleCheckerImpl) ModuleUsage(moduleName string) (*ModuleUsage, error) {
+	return readModuleUsage(moduleName)
+}
+
+type modulesToLoad struct {
+	db      *moduleDB
+	loaded  sets.Set[string]
+	names   []string
+	release string
+}
+
+// modulesToLoad returns the modules that modprobe would insert to load moduleName from rootDir.
+func (mc *moduleCheckerImpl) modulesToLoad(rootDir, moduleName string) (*modulesToLoad, error) {
+	release, err := mc.sc.KernelRelease()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine the kernel release: %v", err)
+	}
+
+	modulesDir := filepath.Join(rootDir, "lib", "modules", release)
+
+	if _, err = os.Stat(modulesDir); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("could not stat %s: %v", modulesDir, err)
+		}
+
+		available, _ := filepath.Glob(filepath.Join(rootDir, "lib", "modules", "*"))
+
+		for i := range available {
+			available[i] = filepath.Base(available[i])
+		}
+
+		return nil, fmt.Errorf(
+			"%w: the image has no modules for kernel %s under %s; found %v",
+			ErrKernelMismatch,
+			release,
+			filepath.Dir(modulesDir),
+			available,
+		)
+	}
+
+	db, err := newModuleDB(rootDir, release)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the module database: %v", err)
+	}
+
+	order, err := db.loadOrder(moduleName)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve the dependencies of %s: %w", moduleName, err)
+	}
+
+	loaded, err := readLoadedModules()
+	if err != nil {
+		return nil, err
+	}
+
+	ml := modulesToLoad{
+		db:      db,
+		loaded:  loaded,
+		names:   make([]string, 0, len(order)),
+		release: release,
+	}
+
+	for _, m := range order {
+		if loaded.Has(m) {
+			mc.logger.V(1).Info("Module already loaded; not checking it", "name", m)
+			continue
+		}
+
+		ml.names = append(ml.names, m)
+	}
+
+	return &ml, nil
+}
+
+func (mc *moduleCheckerImpl) checkModuleInfo(name string, mi *moduleInfo, release string, db *moduleDB, loaded sets.Set[string]) error {
+	if machine, ok := goarchToMachine[nodeArch]; ok && mi.machine != machine {
+		return fmt.Errorf("%w: module %s was built for %s but the node runs %s", ErrArchMismatch, name, mi.machine, machine)
+	}
+
+	if mi.vermagic == "" {
+		return fmt.Errorf("%w: module %s has no vermagic", ErrInvalidModuleFormat, name)
+	}
+
+	builtFor, _, _ := strings.Cut(mi.vermagic, " ")
+
+	if builtFor != release {
+		return fmt.Errorf("%w: module %s was built for %s but the node runs %s", ErrKernelMismatch, name, builtFor, release)
+	}
+
+	for _, dep := range mi.depends {
+		if _, ok := db.path(dep); ok || loaded.Has(dep) {
+			continue
+		}
+
+		return fmt.Errorf(
+			"%w: module %s depends on %s, which is neither loaded on the node nor present in %s",
+			ErrMissingDependency,
+			name,
+			dep,
+			db.modulesDir,
+		)
+	}
+
+	return nil
+}
+
+var errUnsupportedCompression = errors.New("unsupported compression")
+
+// readModuleFile returns the contents of a kernel module file.
+// gzip-compressed modules are decompressed in memory; other compression formats are not supported.
+func readModuleFile(path string) ([]byte, error) {
+	switch filepath.Ext(path) {
+	case ".ko":
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %v", path, err)
+		}
+
+		return b, nil
+	case ".gz":
+		fd, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not open %s: %v", path, err)
+		}
+		defer fd.Close()
+
+		gzr, err := gzip.NewReader(fd)
+		if err != nil {
+			return nil, fmt.Errorf("could not create a gzip reader: %v", err)
+		}
+
+		b, err := io.ReadAll(gzr)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress %s: %v", path, err)
+		}
+
+		return b, nil
+	default:
+		return nil, fmt.Errorf("%s: %w", path, errUnsupportedCompression)
+	}
+}
+
+// readModuleInfo parses the ELF header and the .modinfo section of a kernel module.
+func readModuleInfo(path string) (*moduleInfo, error) {
+	b, err := readModuleFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ef, err := elf.NewFile(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not parse the ELF file: %v", ErrInvalidModuleFormat, err)
+	}
+	defer ef.Close()
+
+	section := ef.Section(".modinfo")
+	if section == nil {
+		return nil, fmt.Errorf("%w: no .modinfo section", ErrInvalidModuleFormat)
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the .modinfo section: %v", err)
+	}
+
+	mi := moduleInfo{machine: ef.Machine}
+
+	for _, field := range bytes.Split(data, []byte{0}) {
+		key, value, ok := strings.Cut(string(field), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "vermagic":
+			mi.vermagic = strings.TrimSpace(value)
+		case "depends":
+			for _, d := range strings.Split(value, ",") {
+				if d != "" {
+					mi.depends = append(mi.depends, normalizeModuleName(d))
+				}
+			}
+		}
+	}
+
+	return &mi, nil
+}
+
+// readLoadedModules returns the names of all modules listed in /proc/modules.
+func readLoadedModules() (sets.Set[string], error) {
+	fd, err := os.Open(procModulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", procModulesPath, err)
+	}
+	defer fd.Close()
+
+	loaded := sets.New[string]()
+
+	s := bufio.NewScanner(fd)
+
+	for s.Scan() {
+		if name, _, ok := strings.Cut(s.Text(), " "); ok {
+			loaded.Insert(name)
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", procModulesPath, err)
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,426 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("moduleCheckerImpl_CheckModule", func() {
+	const rootDir = "testdata/modules"
+
+	var (
+		mc ModuleChecker
+		sc *MockKmodSyscalls
+	)
+
+	writeProcModules := func(contents string) {
+		GinkgoHelper()
+
+		Expect(
+			os.WriteFile(procModulesPath, []byte(contents), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+	}
+
+	BeforeEach(func() {
+		sc = NewMockKmodSyscalls(gomock.NewController(GinkgoT()))
+		mc = NewModuleChecker(sc, GinkgoLogr)
+
+		// The fixtures are x86_64 relocatable objects.
+		nodeArch = "amd64"
+		modprobeConfigDir = GinkgoT().TempDir()
+		procModulesPath = filepath.Join(GinkgoT().TempDir(), "modules")
+
+		writeProcModules("")
+	})
+
+	AfterEach(func() {
+		modprobeConfigDir = "/etc/modprobe.d"
+		nodeArch = runtime.GOARCH
+		procModulesPath = "/proc/modules"
+	})
+
+	It("should return an error if the image has no modules for the running kernel", func() {
+		sc.EXPECT().KernelRelease().Return("5.14.0-427.el9.x86_64", nil)
+
+		err := mc.CheckModule(rootDir, "kmm_a")
+		Expect(err).To(MatchError(ErrKernelMismatch))
+		Expect(err.Error()).To(ContainSubstring("found [5.14.0-362.el9.x86_64]"))
+	})
+
+	It("should accept a module and its dependencies built for the running kernel", func() {
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.CheckModule(rootDir, "kmm_a"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the vermagic does not match", func() {
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		err := mc.CheckModule(rootDir, "kmm_old")
+		Expect(err).To(MatchError(ErrKernelMismatch))
+		Expect(err.Error()).To(ContainSubstring("module kmm_old was built for 5.14.0-284.el9.x86_64 but the node runs 5.14.0-362.el9.x86_64"))
+	})
+
+	It("should read gzip-compressed modules", func() {
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.CheckModule(rootDir, "kmm_d"),
+		).To(
+			MatchError(ErrKernelMismatch),
+		)
+	})
+
+	It("should return an error if the architecture does not match", func() {
+		nodeArch = "arm64"
+
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.CheckModule(rootDir, "kmm_a"),
+		).To(
+			MatchError(ErrArchMismatch),
+		)
+	})
+
+	It("should return an error if a dependency is neither loaded nor in the image", func() {
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		err := mc.CheckModule(rootDir, "kmm_c")
+		Expect(err).To(MatchError(ErrMissingDependency))
+		Expect(err.Error()).To(ContainSubstring("module kmm_c depends on kmm_missing"))
+	})
+
+	It("should accept a dependency that is already loaded", func() {
+		writeProcModules("kmm_missing 16384 1 kmm_c, Live 0x0000000000000000\n")
+
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.CheckModule(rootDir, "kmm_c"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should not check modules that are already loaded", func() {
+		writeProcModules("kmm_old 16384 0 - Live 0x0000000000000000\n")
+
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.CheckModule(rootDir, "kmm_old"),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("moduleCheckerImpl_VerifySignatures", func() {
+	const (
+		certPath      = "testdata/signature/cert.der"
+		otherCertPath = "testdata/signature/other-cert.pem"
+		rootDir       = "testdata/modules"
+	)
+
+	var (
+		mc ModuleChecker
+		sc *MockKmodSyscalls
+	)
+
+	BeforeEach(func() {
+		sc = NewMockKmodSyscalls(gomock.NewController(GinkgoT()))
+		mc = NewModuleChecker(sc, GinkgoLogr)
+
+		modprobeConfigDir = GinkgoT().TempDir()
+		procModulesPath = filepath.Join(GinkgoT().TempDir(), "modules")
+
+		Expect(
+			os.WriteFile(procModulesPath, nil, 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	AfterEach(func() {
+		modprobeConfigDir = "/etc/modprobe.d"
+		procModulesPath = "/proc/modules"
+	})
+
+	It("should return an error if the certificate cannot be read", func() {
+		Expect(
+			mc.VerifySignatures(rootDir, "kmm_a", "/non-existent"),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should accept modules signed with and without authenticated attributes", func() {
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.VerifySignatures(rootDir, "kmm_a", certPath),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should reject unsigned modules", func() {
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		err := mc.VerifySignatures(rootDir, "kmm_c", certPath)
+		Expect(err).To(MatchError(ErrModuleNotSigned))
+		Expect(err.Error()).To(ContainSubstring("kmm_c.ko"))
+	})
+
+	It("should reject modules signed with another key", func() {
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.VerifySignatures(rootDir, "kmm_a", otherCertPath),
+		).To(
+			MatchError(ErrSignatureMismatch),
+		)
+	})
+})
+
+var _ = Describe("splitModuleSignature", func() {
+	It("should reject a signature length larger than the file", func() {
+		b := append([]byte("data"), 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1, 0)
+		b = append(b, moduleSignatureMagic...)
+
+		_, _, err := splitModuleSignature(b)
+		Expect(err).To(MatchError(ErrInvalidSignature))
+	})
+
+	It("should reject non-PKCS#7 signatures", func() {
+		b := append([]byte("data"), 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 4)
+		b = append(b, moduleSignatureMagic...)
+
+		_, _, err := splitModuleSignature(b)
+		Expect(err).To(MatchError(ErrInvalidSignature))
+	})
+
+	It("should return the content and the signature", func() {
+		b := append([]byte("contentsig"), 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 3)
+		b = append(b, moduleSignatureMagic...)
+
+		content, sig, err := splitModuleSignature(b)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("content"))
+		Expect(string(sig)).To(Equal("sig"))
+	})
+})
+
+var _ = Describe("moduleCheckerImpl_ModuleUsage", func() {
+	var mc ModuleChecker
+
+	BeforeEach(func() {
+		mc = NewModuleChecker(nil, GinkgoLogr)
+		sysModuleDir = GinkgoT().TempDir()
+	})
+
+	AfterEach(func() {
+		sysModuleDir = "/sys/module"
+	})
+
+	It("should return nil if the module is not loaded", func() {
+		Expect(
+			mc.ModuleUsage("kmm_a"),
+		).To(
+			BeNil(),
+		)
+	})
+
+	It("should return the reference count and the holders", func() {
+		Expect(
+			os.MkdirAll(filepath.Join(sysModuleDir, "kmm_a", "holders", "kmm_b"), 0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(sysModuleDir, "kmm_a", "refcnt"), []byte("1\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			mc.ModuleUsage("kmm-a"),
+		).To(
+			Equal(&ModuleUsage{RefCount: 1, Holders: []string{"kmm_b"}}),
+		)
+	})
+})
+
+var _ = Describe("moduleCheckerImpl_LoadedModules", func() {
+	const rootDir = "testdata/modules"
+
+	var (
+		mc ModuleChecker
+		sc *MockKmodSyscalls
+	)
+
+	BeforeEach(func() {
+		sc = NewMockKmodSyscalls(gomock.NewController(GinkgoT()))
+		mc = NewModuleChecker(sc, GinkgoLogr)
+
+		modprobeConfigDir = GinkgoT().TempDir()
+		procModulesPath = filepath.Join(GinkgoT().TempDir(), "modules")
+		sysModuleDir = GinkgoT().TempDir()
+	})
+
+	AfterEach(func() {
+		modprobeConfigDir = "/etc/modprobe.d"
+		procModulesPath = "/proc/modules"
+		sysModuleDir = "/sys/module"
+	})
+
+	It("should return the loaded modules with their srcversion", func() {
+		Expect(
+			os.WriteFile(procModulesPath, []byte("kmm_a 16384 0 - Live 0x0\nkmm_b 16384 1 kmm_a, Live 0x0\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.MkdirAll(filepath.Join(sysModuleDir, "kmm_a"), 0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(sysModuleDir, "kmm_a", "srcversion"), []byte("0123456789ABCDEF\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+
+		Expect(
+			mc.LoadedModules(rootDir, "kmm_a"),
+		).To(
+			Equal([]kmmv1beta1.LoadedKernelModule{
+				{Name: "kmm_b"},
+				{Name: "kmm_a", SrcVersion: "0123456789ABCDEF"},
+			}),
+		)
+	})
+})
+
+var _ = Describe("moduleCheckerImpl_ModuleParameters", func() {
+	var mc ModuleChecker
+
+	BeforeEach(func() {
+		mc = NewModuleChecker(nil, GinkgoLogr)
+		sysModuleDir = GinkgoT().TempDir()
+	})
+
+	AfterEach(func() {
+		sysModuleDir = "/sys/module"
+	})
+
+	It("should return nil if the module has no parameters", func() {
+		Expect(
+			mc.ModuleParameters("kmm_a"),
+		).To(
+			BeNil(),
+		)
+	})
+
+	It("should return the parameter values", func() {
+		paramsDir := filepath.Join(sysModuleDir, "kmm_a", "parameters")
+
+		Expect(
+			os.MkdirAll(paramsDir, 0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(paramsDir, "debug"), []byte("Y\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			mc.ModuleParameters("kmm_a"),
+		).To(
+			Equal(map[string]string{"debug": "Y"}),
+		)
+	})
+})
+
+var _ = Describe("moduleCheckerImpl_ModuleState", func() {
+	var mc ModuleChecker
+
+	BeforeEach(func() {
+		mc = NewModuleChecker(nil, GinkgoLogr)
+		sysModuleDir = GinkgoT().TempDir()
+	})
+
+	AfterEach(func() {
+		sysModuleDir = "/sys/module"
+	})
+
+	It("should report a module that is not loaded", func() {
+		Expect(
+			mc.ModuleState("kmm-a"),
+		).To(
+			Equal(&ModuleState{Name: "kmm-a"}),
+		)
+	})
+
+	It("should report the state of a loaded module", func() {
+		moduleDir := filepath.Join(sysModuleDir, "kmm_a")
+
+		Expect(
+			os.MkdirAll(filepath.Join(moduleDir, "parameters"), 0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(moduleDir, "refcnt"), []byte("2\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(moduleDir, "srcversion"), []byte("ABC\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(moduleDir, "parameters", "debug"), []byte("1\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			mc.ModuleState("kmm-a"),
+		).To(
+			Equal(&ModuleState{
+				Name:       "kmm-a",
+				Loaded:     true,
+				RefCount:   2,
+				Holders:    []string{},
+				SrcVersion: "ABC",
+				Parameters: map[string]string{"debug": "1"},
+			}),
+		)
+	})
+})
@@ -0,0 +1,306 @@
+package worker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var modprobeConfigDir = "/etc/modprobe.d"
+
+type softdep struct {
+	pre  []string
+	post []string
+}
+
+// moduleDB holds the information that modprobe would read from the files generated by depmod for one kernel, and from
+// the modprobe configuration directory.
+// All module names are normalized: dashes are replaced with underscores.
+type moduleDB struct {
+	modulesDir string
+	paths      map[string]string
+	deps       map[string][]string
+	softdeps   map[string]softdep
+	options    map[string][]string
+}
+
+// newModuleDB reads modules.dep and modules.softdep in rootDir/lib/modules/kernelRelease, as well as all .conf files in
+// the modprobe configuration directory.
+// A missing modules.dep is not an error, because unloading modules does not require it.
+func newModuleDB(rootDir, kernelRelease string) (*moduleDB, error) {
+	db := &moduleDB{
+		modulesDir: filepath.Join(rootDir, "lib", "modules", kernelRelease),
+		paths:      make(map[string]string),
+		deps:       make(map[string][]string),
+		softdeps:   make(map[string]softdep),
+		options:    make(map[string][]string),
+	}
+
+	if err := db.readModulesDep(filepath.Join(db.modulesDir, "modules.dep")); err != nil {
+		return nil, err
+	}
+
+	configFiles := []string{filepath.Join(db.modulesDir, "modules.softdep")}
+
+	entries, err := os.ReadDir(modprobeConfigDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not list %s: %v", modprobeConfigDir, err)
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".conf" {
+			configFiles = append(configFiles, filepath.Join(modprobeConfigDir, e.Name()))
+		}
+	}
+
+	for _, f := range configFiles {
+		if err = db.readConfig(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+func (db *moduleDB) readModulesDep(path string) error {
+	fd, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	s := bufio.NewScanner(fd)
+
+	for s.Scan() {
+		modPath, depsStr, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		name := moduleNameFromPath(modPath)
+
+		db.paths[name] = db.absPath(modPath)
+
+		deps := strings.Fields(depsStr)
+		depNames := make([]string, 0, len(deps))
+
+		for _, d := range deps {
+			depName := moduleNameFromPath(d)
+
+			depNames = append(depNames, depName)
+
+			if _, ok := db.paths[depName]; !ok {
+				db.paths[depName] = db.absPath(d)
+			}
+		}
+
+		db.deps[name] = depNames
+	}
+
+	if err = s.Err(); err != nil {
+		return fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// readConfig parses the softdep and options commands in a modprobe configuration file.
+// All other commands are ignored.
+func (db *moduleDB) readConfig(path string) error {
+	fd, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	s := bufio.NewScanner(fd)
+
+	var line string
+
+	for s.Scan() {
+		text := s.Text()
+
+		// Lines ending with a backslash continue on the next one.
+		if cont, ok := strings.CutSuffix(text, `\`); ok {
+			line += cont + " "
+			continue
+		}
+
+		line += text
+
+		fields := strings.Fields(line)
+		line = ""
+
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		name := normalizeModuleName(fields[1])
+
+		switch fields[0] {
+		case "options":
+			db.options[name] = append(db.options[name], fields[2:]...)
+		case "softdep":
+			sd := db.softdeps[name]
+			target := &sd.pre
+
+			for _, f := range fields[2:] {
+				switch f {
+				case "pre:":
+					target = &sd.pre
+				case "post:":
+					target = &sd.post
+				default:
+					*target = append(*target, normalizeModuleName(f))
+				}
+			}
+
+			db.softdeps[name] = sd
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func (db *moduleDB) absPath(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+
+	return filepath.Join(db.modulesDir, p)
+}
+
+func (db *moduleDB) path(name string) (string, bool) {
+	p, ok := db.paths[normalizeModuleName(name)]
+	return p, ok
+}
+
+// loadOrder returns the list of modules to insert, in order, so that name can be loaded.
+// Hard dependencies listed in modules.dep are required; soft dependencies that cannot be found are skipped, like
+// modprobe does.
+func (db *moduleDB) loadOrder(name string) ([]string, error) {
+	name = normalizeModuleName(name)
+
+	if _, ok := db.paths[name]; !ok {
+		return nil, fmt.Errorf("%s in %s: %w", name, db.modulesDir, ErrModuleNotFound)
+	}
+
+	order := make([]string, 0)
+	visited := sets.New[string]()
+
+	var visit func(n string) error
+
+	visit = func(n string) error {
+		if visited.Has(n) {
+			return nil
+		}
+
+		visited.Insert(n)
+
+		sd := db.softdeps[n]
+
+		for _, pre := range sd.pre {
+			if _, ok := db.paths[pre]; ok {
+				if err := visit(pre); err != nil {
+					return err
+				}
+			}
+		}
+
+		// modules.dep lists direct dependencies first; load them last.
+		deps := db.deps[n]
+
+		for i := len(deps) - 1; i >= 0; i-- {
+			if _, ok := db.paths[deps[i]]; !ok {
+				return fmt.Errorf("dependency %s of %s: %w", deps[i], n, ErrModuleNotFound)
+			}
+
+			if err := visit(deps[i]); err != nil {
+				return err
+			}
+		}
+
+		order = append(order, n)
+
+		for _, post := range sd.post {
+			if _, ok := db.paths[post]; ok {
+				if err := visit(post); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	return order, visit(name)
+}
+
+// unloadOrder returns the list of modules that modprobe -r would try to remove, in order.
+// The first module removed after the post soft dependencies is always name.
+func (db *moduleDB) unloadOrder(name string) []string {
+	order := make([]string, 0)
+	visited := sets.New[string]()
+
+	var visit func(n string)
+
+	visit = func(n string) {
+		if visited.Has(n) {
+			return
+		}
+
+		visited.Insert(n)
+
+		sd := db.softdeps[n]
+
+		for i := len(sd.post) - 1; i >= 0; i-- {
+			visit(sd.post[i])
+		}
+
+		order = append(order, n)
+
+		for _, d := range db.deps[n] {
+			visit(d)
+		}
+
+		for i := len(sd.pre) - 1; i >= 0; i-- {
+			visit(sd.pre[i])
+		}
+	}
+
+	visit(normalizeModuleName(name))
+
+	return order
+}
+
+func normalizeModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func moduleNameFromPath(p string) string {
+	base := filepath.Base(p)
+
+	for _, ext := range []string{".xz", ".gz", ".zst"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+
+	return normalizeModuleName(strings.TrimSuffix(base, ".ko"))
+}
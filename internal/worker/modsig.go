@@ -0,0 +1,292 @@
+package worker
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// moduleSignatureMagic is appended to signed kernel modules by scripts/sign-file.
+const moduleSignatureMagic = "~Module signature appended~\n"
+
+// pkeyIDPKCS7 is PKEY_ID_PKCS7 from include/linux/module_signature.h.
+const pkeyIDPKCS7 = 2
+
+// moduleSignatureInfo is struct module_signature from include/linux/module_signature.h.
+type moduleSignatureInfo struct {
+	Algo      uint8
+	Hash      uint8
+	IDType    uint8
+	SignerLen uint8
+	KeyIDLen  uint8
+	_         [3]uint8
+	SigLen    uint32
+}
+
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+	oidDigestAlgorithms = map[string]crypto.Hash{
+		"2.16.840.1.101.3.4.2.1": crypto.SHA256,
+		"2.16.840.1.101.3.4.2.2": crypto.SHA384,
+		"2.16.840.1.101.3.4.2.3": crypto.SHA512,
+		"2.16.840.1.101.3.4.2.4": crypto.SHA224,
+	}
+)
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      asn1.RawValue
+	Certificates     asn1.RawValue     `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue     `asn1:"optional,tag:1"`
+	SignerInfos      []pkcs7SignerInfo `asn1:"set"`
+}
+
+type pkcs7IssuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type pkcs7Attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+type pkcs7SignerInfo struct {
+	Version                   int
+	SID                       asn1.RawValue
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+	UnauthenticatedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// readCertificate reads a PEM or DER encoded X.509 certificate.
+func readCertificate(path string) (*x509.Certificate, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	if block, _ := pem.Decode(b); block != nil {
+		b = block.Bytes
+	}
+
+	cert, err := x509.ParseCertificate(b)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the certificate in %s: %v", path, err)
+	}
+
+	return cert, nil
+}
+
+// verifyModuleSignature checks that the module file at path has a PKCS#7 signature appended by sign-file, and that
+// this signature was made with the private key matching cert.
+func verifyModuleSignature(path string, cert *x509.Certificate) error {
+	b, err := readModuleFile(path)
+	if err != nil {
+		return err
+	}
+
+	content, sig, err := splitModuleSignature(b)
+	if err != nil {
+		return fmt.Errorf("module %s: %w", path, err)
+	}
+
+	if err = verifyPKCS7(content, sig, cert); err != nil {
+		return fmt.Errorf("module %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// splitModuleSignature returns the signed part of a module and its detached PKCS#7 signature.
+func splitModuleSignature(b []byte) ([]byte, []byte, error) {
+	rest, ok := bytes.CutSuffix(b, []byte(moduleSignatureMagic))
+	if !ok {
+		return nil, nil, ErrModuleNotSigned
+	}
+
+	var info moduleSignatureInfo
+
+	infoLen := binary.Size(info)
+
+	if len(rest) < infoLen {
+		return nil, nil, fmt.Errorf("%w: truncated signature information", ErrInvalidSignature)
+	}
+
+	if _, err := binary.Decode(rest[len(rest)-infoLen:], binary.BigEndian, &info); err != nil {
+		return nil, nil, fmt.Errorf("%w: could not decode the signature information: %v", ErrInvalidSignature, err)
+	}
+
+	if info.IDType != pkeyIDPKCS7 {
+		return nil, nil, fmt.Errorf("%w: unsupported signature type %d", ErrInvalidSignature, info.IDType)
+	}
+
+	rest = rest[:len(rest)-infoLen]
+
+	if uint64(len(rest)) < uint64(info.SigLen) {
+		return nil, nil, fmt.Errorf("%w: signature length %d exceeds the file size", ErrInvalidSignature, info.SigLen)
+	}
+
+	split := len(rest) - int(info.SigLen)
+
+	return rest[:split], rest[split:], nil
+}
+
+func verifyPKCS7(content, sig []byte, cert *x509.Certificate) error {
+	var ci pkcs7ContentInfo
+
+	if _, err := asn1.Unmarshal(sig, &ci); err != nil {
+		return fmt.Errorf("%w: could not parse the PKCS#7 message: %v", ErrInvalidSignature, err)
+	}
+
+	if !ci.ContentType.Equal(oidSignedData) {
+		return fmt.Errorf("%w: PKCS#7 content type %s is not signedData", ErrInvalidSignature, ci.ContentType)
+	}
+
+	var sd pkcs7SignedData
+
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return fmt.Errorf("%w: could not parse the PKCS#7 signed data: %v", ErrInvalidSignature, err)
+	}
+
+	if len(sd.SignerInfos) == 0 {
+		return fmt.Errorf("%w: no signer", ErrInvalidSignature)
+	}
+
+	var errs []error
+
+	for _, si := range sd.SignerInfos {
+		err := verifySignerInfo(content, &si, cert)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return fmt.Errorf("%w: %v", ErrSignatureMismatch, errors.Join(errs...))
+}
+
+func verifySignerInfo(content []byte, si *pkcs7SignerInfo, cert *x509.Certificate) error {
+	if err := matchSigner(si.SID, cert); err != nil {
+		return err
+	}
+
+	hash, ok := oidDigestAlgorithms[si.DigestAlgorithm.Algorithm.String()]
+	if !ok {
+		return fmt.Errorf("unsupported digest algorithm %s", si.DigestAlgorithm.Algorithm)
+	}
+
+	signed := content
+
+	// With authenticated attributes, the signature covers the DER encoding of the attributes as a SET, and the
+	// messageDigest attribute contains the hash of the content.
+	if len(si.AuthenticatedAttributes.FullBytes) > 0 {
+		var attrs []pkcs7Attribute
+
+		if _, err := asn1.UnmarshalWithParams(si.AuthenticatedAttributes.FullBytes, &attrs, "set,tag:0"); err != nil {
+			return fmt.Errorf("could not parse the authenticated attributes: %v", err)
+		}
+
+		h := hash.New()
+		h.Write(content)
+
+		if err := checkMessageDigest(attrs, h.Sum(nil)); err != nil {
+			return err
+		}
+
+		signed = bytes.Clone(si.AuthenticatedAttributes.FullBytes)
+		signed[0] = asn1.TagSet | 0x20
+	}
+
+	var algo x509.SignatureAlgorithm
+
+	switch cert.PublicKeyAlgorithm {
+	case x509.RSA:
+		algo = map[crypto.Hash]x509.SignatureAlgorithm{
+			crypto.SHA256: x509.SHA256WithRSA,
+			crypto.SHA384: x509.SHA384WithRSA,
+			crypto.SHA512: x509.SHA512WithRSA,
+		}[hash]
+	case x509.ECDSA:
+		algo = map[crypto.Hash]x509.SignatureAlgorithm{
+			crypto.SHA256: x509.ECDSAWithSHA256,
+			crypto.SHA384: x509.ECDSAWithSHA384,
+			crypto.SHA512: x509.ECDSAWithSHA512,
+		}[hash]
+	}
+
+	if algo == x509.UnknownSignatureAlgorithm {
+		return fmt.Errorf("unsupported combination of %s key and %s digest", cert.PublicKeyAlgorithm, hash)
+	}
+
+	if err := cert.CheckSignature(algo, signed, si.EncryptedDigest); err != nil {
+		return fmt.Errorf("signature verification failed: %v", err)
+	}
+
+	return nil
+}
+
+// matchSigner checks that the signer identifier designates cert.
+// sign-file identifies the signer by issuer and serial number, or by subject key identifier when called with -k.
+func matchSigner(sid asn1.RawValue, cert *x509.Certificate) error {
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		if !bytes.Equal(sid.Bytes, cert.SubjectKeyId) {
+			return fmt.Errorf("signer key identifier %x does not match the certificate's %x", sid.Bytes, cert.SubjectKeyId)
+		}
+
+		return nil
+	}
+
+	var ias pkcs7IssuerAndSerialNumber
+
+	if _, err := asn1.Unmarshal(sid.FullBytes, &ias); err != nil {
+		return fmt.Errorf("could not parse the signer identifier: %v", err)
+	}
+
+	if !bytes.Equal(ias.Issuer.FullBytes, cert.RawIssuer) || ias.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		return fmt.Errorf("module was signed by another certificate (serial number %x)", ias.SerialNumber)
+	}
+
+	return nil
+}
+
+func checkMessageDigest(attrs []pkcs7Attribute, digest []byte) error {
+	for _, a := range attrs {
+		if !a.Type.Equal(oidMessageDigest) {
+			continue
+		}
+
+		var md []byte
+
+		if _, err := asn1.Unmarshal(a.Values.Bytes, &md); err != nil {
+			return fmt.Errorf("could not parse the messageDigest attribute: %v", err)
+		}
+
+		if !bytes.Equal(md, digest) {
+			return errors.New("the module content does not match the signed digest")
+		}
+
+		return nil
+	}
+
+	return errors.New("missing messageDigest attribute")
+}
@@ -0,0 +1,203 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ModuleUsage describes what keeps a loaded kernel module from being unloaded.
+type ModuleUsage struct {
+	// RefCount is the reference count of the module, as reported in /sys/module/<name>/refcnt.
+	RefCount int
+	// Holders are the loaded modules that depend on the module.
+	Holders []string
+}
+
+func (mu *ModuleUsage) InUse() bool {
+	return mu.RefCount > 0 || len(mu.Holders) > 0
+}
+
+func (mu *ModuleUsage) String() string {
+	return fmt.Sprintf("reference count %d, holders %v", mu.RefCount, mu.Holders)
+}
+
+// readModuleUsage reads the reference count and the holders of a loaded module from sysfs.
+// It returns nil if the module is not loaded or is built into the kernel.
+func readModuleUsage(name string) (*ModuleUsage, error) {
+	moduleDir := filepath.Join(sysModuleDir, normalizeModuleName(name))
+
+	b, err := os.ReadFile(filepath.Join(moduleDir, "refcnt"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read the reference count of module %s: %v", name, err)
+	}
+
+	refCount, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the reference count of module %s: %v", name, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(moduleDir, "holders"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not list the holders of module %s: %v", name, err)
+	}
+
+	mu := ModuleUsage{
+		RefCount: refCount,
+		Holders:  make([]string, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		mu.Holders = append(mu.Holders, e.Name())
+	}
+
+	return &mu, nil
+}
+
+// readSrcVersion returns the srcversion of a loaded module, or an empty string if the module does not have one.
+func readSrcVersion(name string) (string, error) {
+	path := filepath.Join(sysModuleDir, normalizeModuleName(name), "srcversion")
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// readModuleParameters returns the values of the readable parameters of a loaded module.
+func readModuleParameters(name string) (map[string]string, error) {
+	paramsDir := filepath.Join(sysModuleDir, normalizeModuleName(name), "parameters")
+
+	entries, err := os.ReadDir(paramsDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not list the parameters of module %s: %v", name, err)
+	}
+
+	params := make(map[string]string, len(entries))
+
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(paramsDir, e.Name()))
+		if err != nil {
+			// Parameters declared with a mode of 0200 are only writable.
+			if errors.Is(err, fs.ErrPermission) {
+				continue
+			}
+
+			return nil, fmt.Errorf("could not read parameter %s of module %s: %v", e.Name(), name, err)
+		}
+
+		params[e.Name()] = strings.TrimSpace(string(b))
+	}
+
+	return params, nil
+}
+
+// writeModuleParameters changes the parameters of a loaded module through sysfs.
+// params are in the key=value format used on the modprobe command line.
+// All parameters are checked before any of them is written, so that the module is left unchanged if one of them
+// cannot be changed at runtime.
+func writeModuleParameters(name string, params []string) error {
+	moduleDir := filepath.Join(sysModuleDir, normalizeModuleName(name))
+
+	if _, err := os.Stat(moduleDir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrModuleNotLoaded, name)
+		}
+
+		return fmt.Errorf("could not check if module %s is loaded: %v", name, err)
+	}
+
+	paths := make([]string, 0, len(params))
+	values := make([]string, 0, len(params))
+
+	for _, p := range params {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid parameter %q: expected key=value", p)
+		}
+
+		path := filepath.Join(moduleDir, "parameters", key)
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("%w: module %s has no parameter %s in sysfs", ErrParamNotWritable, name, key)
+			}
+
+			return fmt.Errorf("could not check parameter %s of module %s: %v", key, name, err)
+		}
+
+		// The kernel exposes parameters declared with a writable mode with the owner write bit set.
+		if fi.Mode().Perm()&0200 == 0 {
+			return fmt.Errorf("%w: parameter %s of module %s is read-only", ErrParamNotWritable, key, name)
+		}
+
+		paths = append(paths, path)
+		values = append(values, value)
+	}
+
+	for i, path := range paths {
+		if err := os.WriteFile(path, []byte(values[i]), 0); err != nil {
+			return fmt.Errorf("could not write %q into %s: %v", values[i], path, err)
+		}
+	}
+
+	return nil
+}
+
+// ModuleState is the state of a kernel module on the node, as reported by sysfs.
+type ModuleState struct {
+	Name string `json:"name"`
+	// Loaded is false if the module is not loaded or is built into the kernel.
+	Loaded     bool              `json:"loaded"`
+	RefCount   int               `json:"refCount"`
+	Holders    []string          `json:"holders,omitempty"`
+	SrcVersion string            `json:"srcVersion,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// readModuleState returns the state of a module from sysfs.
+func readModuleState(name string) (*ModuleState, error) {
+	state := ModuleState{Name: name}
+
+	usage, err := readModuleUsage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if usage == nil {
+		return &state, nil
+	}
+
+	state.Loaded = true
+	state.RefCount = usage.RefCount
+	state.Holders = usage.Holders
+
+	if state.SrcVersion, err = readSrcVersion(name); err != nil {
+		return nil, err
+	}
+
+	if state.Parameters, err = readModuleParameters(name); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,253 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// modprobeInvocation is the subset of the modprobe command-line that the native runner understands.
+type modprobeInvocation struct {
+	dirName string
+	force   bool
+	modules []string
+	params  []string
+	remove  bool
+}
+
+type nativeModprobeRunner struct {
+	logger logr.Logger
+	sc     KmodSyscalls
+}
+
+// NewNativeModprobeRunner returns a ModprobeRunner that resolves dependencies from the files generated by depmod and
+// loads modules with finit_module(2), without running the modprobe binary.
+func NewNativeModprobeRunner(sc KmodSyscalls, logger logr.Logger) ModprobeRunner {
+	return &nativeModprobeRunner{
+		logger: logger.WithName("native-modprobe"),
+		sc:     sc,
+	}
+}
+
+func (nr *nativeModprobeRunner) Run(ctx context.Context, args ...string) error {
+	nr.logger.Info("Running native modprobe", "args", args)
+
+	inv, err := parseModprobeArgs(args)
+	if err != nil {
+		return fmt.Errorf("could not parse the modprobe arguments: %v", err)
+	}
+
+	release, err := nr.sc.KernelRelease()
+	if err != nil {
+		return fmt.Errorf("could not determine the kernel release: %v", err)
+	}
+
+	db, err := newModuleDB(inv.dirName, release)
+	if err != nil {
+		return fmt.Errorf("could not read the module database: %v", err)
+	}
+
+	if inv.remove {
+		for _, m := range inv.modules {
+			if err = nr.remove(ctx, db, m, inv.force); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, m := range inv.modules {
+		if err = nr.load(ctx, db, m, inv.params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (nr *nativeModprobeRunner) load(ctx context.Context, db *moduleDB, name string, params []string) error {
+	order, err := db.loadOrder(name)
+	if err != nil {
+		return fmt.Errorf("could not resolve the dependencies of %s: %w", name, err)
+	}
+
+	target := normalizeModuleName(name)
+
+	for _, m := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		loaded, err := nr.sc.IsModuleLoaded(m)
+		if err != nil {
+			return fmt.Errorf("could not check if module %s is loaded: %v", m, err)
+		}
+
+		if loaded {
+			nr.logger.Info("Module already loaded; skipping", "name", m)
+			continue
+		}
+
+		modParams := slices.Clone(db.options[m])
+
+		if m == target {
+			modParams = append(modParams, params...)
+		}
+
+		path, _ := db.path(m)
+
+		nr.logger.Info("Loading module", "name", m, "path", path, "parameters", modParams)
+
+		if err = nr.sc.FinitModule(path, strings.Join(modParams, " ")); err != nil {
+			err = newKmodError(kmodOpLoad, m, err)
+
+			if errors.Is(err, ErrModuleExists) {
+				nr.logger.Info("Module was loaded concurrently; skipping", "name", m)
+				continue
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// remove unloads name and its unused dependencies.
+// If force is true, name is unloaded even if its reference count is not zero; dependencies are never forced.
+func (nr *nativeModprobeRunner) remove(ctx context.Context, db *moduleDB, name string, force bool) error {
+	target := normalizeModuleName(name)
+
+	for _, m := range db.unloadOrder(target) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		loaded, err := nr.sc.IsModuleLoaded(m)
+		if err != nil {
+			return fmt.Errorf("could not check if module %s is loaded: %v", m, err)
+		}
+
+		if !loaded {
+			nr.logger.Info("Module not loaded; skipping", "name", m)
+			continue
+		}
+
+		nr.logger.Info("Unloading module", "name", m)
+
+		if err = nr.sc.DeleteModule(m, force && m == target); err != nil {
+			err = newKmodError(kmodOpUnload, m, err)
+
+			// Like modprobe -r, only the requested module must be removed; dependencies that are still used by
+			// other modules are left in place.
+			if m != target && (errors.Is(err, ErrModuleBusy) || errors.Is(err, ErrModuleNotLoaded)) {
+				nr.logger.Info("Dependency still in use; not unloading it", "name", m)
+				continue
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseModprobeArgs(args []string) (*modprobeInvocation, error) {
+	var (
+		all        bool
+		inv        = modprobeInvocation{dirName: "/"}
+		positional = make([]string, 0, len(args))
+	)
+
+	nextValue := func(i *int, opt string) (string, error) {
+		*i++
+
+		if *i >= len(args) {
+			return "", fmt.Errorf("option %s requires a value", opt)
+		}
+
+		return args[*i], nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+
+			switch name {
+			case "quiet", "verbose":
+			case "all":
+				all = true
+			case "force":
+				inv.force = true
+			case "remove":
+				inv.remove = true
+			case "dirname":
+				if !hasValue {
+					var err error
+
+					if value, err = nextValue(&i, arg); err != nil {
+						return nil, err
+					}
+				}
+
+				inv.dirName = value
+			default:
+				return nil, fmt.Errorf("unsupported option %s", arg)
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+		shortOpts:
+			for j, c := range arg[1:] {
+				switch c {
+				case 'q', 'v':
+				case 'a':
+					all = true
+				case 'f':
+					inv.force = true
+				case 'r':
+					inv.remove = true
+				case 'd':
+					if rest := arg[j+2:]; rest != "" {
+						inv.dirName = rest
+					} else {
+						value, err := nextValue(&i, "-d")
+						if err != nil {
+							return nil, err
+						}
+
+						inv.dirName = value
+					}
+
+					break shortOpts
+				default:
+					return nil, fmt.Errorf("unsupported option -%c", c)
+				}
+			}
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) == 0 {
+		return nil, errors.New("missing module name")
+	}
+
+	if inv.remove || all {
+		inv.modules = positional
+	} else {
+		inv.modules = positional[:1]
+		inv.params = positional[1:]
+	}
+
+	return &inv, nil
+}
@@ -0,0 +1,291 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/sys/unix"
+)
+
+const testKernelRelease = "5.14.0-362.el9.x86_64"
+
+// writeModulesDep creates a minimal depmod output under rootDir for testKernelRelease.
+func writeModulesDep(rootDir, modulesDep, modulesSoftdep string) string {
+	GinkgoHelper()
+
+	modulesDir := filepath.Join(rootDir, "lib", "modules", testKernelRelease)
+
+	Expect(
+		os.MkdirAll(modulesDir, 0755),
+	).NotTo(
+		HaveOccurred(),
+	)
+
+	Expect(
+		os.WriteFile(filepath.Join(modulesDir, "modules.dep"), []byte(modulesDep), 0644),
+	).NotTo(
+		HaveOccurred(),
+	)
+
+	if modulesSoftdep != "" {
+		Expect(
+			os.WriteFile(filepath.Join(modulesDir, "modules.softdep"), []byte(modulesSoftdep), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+	}
+
+	return modulesDir
+}
+
+var _ = Describe("parseModprobeArgs", func() {
+	DescribeTable(
+		"should parse the arguments built by the worker",
+		func(args []string, expected modprobeInvocation) {
+			Expect(
+				parseModprobeArgs(args),
+			).To(
+				Equal(&expected),
+			)
+		},
+		Entry(
+			"load",
+			[]string{"-vd", "/tmp/opt", "mod_a", "p1=v1", "p2=v2"},
+			modprobeInvocation{dirName: "/tmp/opt", modules: []string{"mod_a"}, params: []string{"p1=v1", "p2=v2"}},
+		),
+		Entry(
+			"unload",
+			[]string{"-rvd", "/tmp/opt", "mod_a"},
+			modprobeInvocation{dirName: "/tmp/opt", modules: []string{"mod_a"}, remove: true},
+		),
+		Entry(
+			"in-tree removal",
+			[]string{"-rv", "intree1", "intree2"},
+			modprobeInvocation{dirName: "/", modules: []string{"intree1", "intree2"}, remove: true},
+		),
+		Entry(
+			"long options",
+			[]string{"--all", "--dirname=/opt", "--quiet", "mod_a", "mod_b"},
+			modprobeInvocation{dirName: "/opt", modules: []string{"mod_a", "mod_b"}},
+		),
+		Entry(
+			"forced removal",
+			[]string{"-rvd", "/opt", "--force", "mod_a"},
+			modprobeInvocation{dirName: "/opt", force: true, modules: []string{"mod_a"}, remove: true},
+		),
+		Entry(
+			"directory glued to the short option",
+			[]string{"-d/opt", "mod_a"},
+			modprobeInvocation{dirName: "/opt", modules: []string{"mod_a"}, params: []string{}},
+		),
+	)
+
+	DescribeTable(
+		"should return an error",
+		func(args []string) {
+			_, err := parseModprobeArgs(args)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no module", []string{"-v"}),
+		Entry("missing directory", []string{"mod_a", "-d"}),
+		Entry("unsupported short option", []string{"-i", "mod_a"}),
+		Entry("unsupported long option", []string{"--show-depends", "mod_a"}),
+	)
+})
+
+var _ = Describe("nativeModprobeRunner", func() {
+	var (
+		ctx     context.Context
+		rootDir string
+		sc      *MockKmodSyscalls
+		mr      ModprobeRunner
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		rootDir = GinkgoT().TempDir()
+		modprobeConfigDir = GinkgoT().TempDir()
+		sc = NewMockKmodSyscalls(gomock.NewController(GinkgoT()))
+		mr = NewNativeModprobeRunner(sc, GinkgoLogr)
+
+		sc.EXPECT().KernelRelease().Return(testKernelRelease, nil)
+	})
+
+	AfterEach(func() {
+		modprobeConfigDir = "/etc/modprobe.d"
+	})
+
+	Describe("load", func() {
+		It("should load dependencies first and pass the parameters to the target only", func() {
+			modulesDir := writeModulesDep(
+				rootDir,
+				"extra/mod-a.ko: extra/mod_b.ko kernel/mod_c.ko.xz\nextra/mod_b.ko: kernel/mod_c.ko.xz\nkernel/mod_c.ko.xz:\n",
+				"",
+			)
+
+			gomock.InOrder(
+				sc.EXPECT().IsModuleLoaded("mod_c").Return(true, nil),
+				sc.EXPECT().IsModuleLoaded("mod_b").Return(false, nil),
+				sc.EXPECT().FinitModule(filepath.Join(modulesDir, "extra/mod_b.ko"), ""),
+				sc.EXPECT().IsModuleLoaded("mod_a").Return(false, nil),
+				sc.EXPECT().FinitModule(filepath.Join(modulesDir, "extra/mod-a.ko"), "p1=v1 p2=v2"),
+			)
+
+			Expect(
+				mr.Run(ctx, "-vd", rootDir, "mod-a", "p1=v1", "p2=v2"),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should honour soft dependencies and options from the modprobe configuration", func() {
+			modulesDir := writeModulesDep(rootDir, "mod_a.ko:\nmod_b.ko:\nmod_c.ko:\nmod_d.ko:\n", "softdep mod_a post: mod_d\n")
+
+			const softdepConf = "# generated by KMM\nsoftdep mod_a pre: mod_b\nsoftdep mod_b pre: mod_c \\\n  mod_missing\noptions mod_a opt=1\n"
+
+			Expect(
+				os.WriteFile(filepath.Join(modprobeConfigDir, "softdep.conf"), []byte(softdepConf), 0644),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			gomock.InOrder(
+				sc.EXPECT().IsModuleLoaded("mod_c"),
+				sc.EXPECT().FinitModule(filepath.Join(modulesDir, "mod_c.ko"), ""),
+				sc.EXPECT().IsModuleLoaded("mod_b"),
+				sc.EXPECT().FinitModule(filepath.Join(modulesDir, "mod_b.ko"), ""),
+				sc.EXPECT().IsModuleLoaded("mod_a"),
+				sc.EXPECT().FinitModule(filepath.Join(modulesDir, "mod_a.ko"), "opt=1 p=v"),
+				sc.EXPECT().IsModuleLoaded("mod_d"),
+				sc.EXPECT().FinitModule(filepath.Join(modulesDir, "mod_d.ko"), ""),
+			)
+
+			Expect(
+				mr.Run(ctx, "-d", rootDir, "mod_a", "p=v"),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should return ErrModuleNotFound if the module is not in modules.dep", func() {
+			writeModulesDep(rootDir, "mod_a.ko:\n", "")
+
+			Expect(
+				mr.Run(ctx, "-d", rootDir, "mod_b"),
+			).To(
+				MatchError(ErrModuleNotFound),
+			)
+		})
+
+		It("should ignore EEXIST", func() {
+			modulesDir := writeModulesDep(rootDir, "mod_a.ko:\n", "")
+
+			gomock.InOrder(
+				sc.EXPECT().IsModuleLoaded("mod_a"),
+				sc.EXPECT().FinitModule(filepath.Join(modulesDir, "mod_a.ko"), "").Return(unix.EEXIST),
+			)
+
+			Expect(
+				mr.Run(ctx, "-d", rootDir, "mod_a"),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		DescribeTable(
+			"should return a classified error",
+			func(errno unix.Errno, expected error) {
+				modulesDir := writeModulesDep(rootDir, "mod_a.ko:\n", "")
+
+				gomock.InOrder(
+					sc.EXPECT().IsModuleLoaded("mod_a"),
+					sc.EXPECT().FinitModule(filepath.Join(modulesDir, "mod_a.ko"), "").Return(errno),
+				)
+
+				err := mr.Run(ctx, "-d", rootDir, "mod_a")
+				Expect(err).To(MatchError(expected))
+
+				ke := &KmodError{}
+				Expect(errors.As(err, &ke)).To(BeTrue())
+				Expect(ke.Module).To(Equal("mod_a"))
+				Expect(ke.Errno).To(Equal(errno))
+			},
+			Entry(nil, unix.ENOENT, ErrUnknownSymbol),
+			Entry(nil, unix.ENOEXEC, ErrInvalidModuleFormat),
+			Entry(nil, unix.EKEYREJECTED, ErrSignatureRejected),
+		)
+	})
+
+	Describe("remove", func() {
+		It("should remove the module and its unused dependencies", func() {
+			writeModulesDep(rootDir, "mod_a.ko: mod_b.ko mod_c.ko\nmod_b.ko: mod_c.ko\nmod_c.ko:\n", "")
+
+			gomock.InOrder(
+				sc.EXPECT().IsModuleLoaded("mod_a").Return(true, nil),
+				sc.EXPECT().DeleteModule("mod_a", false),
+				sc.EXPECT().IsModuleLoaded("mod_b").Return(true, nil),
+				sc.EXPECT().DeleteModule("mod_b", false).Return(unix.EAGAIN),
+				sc.EXPECT().IsModuleLoaded("mod_c").Return(false, nil),
+			)
+
+			Expect(
+				mr.Run(ctx, "-rvd", rootDir, "mod_a"),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should remove modules in the reverse order of the soft dependencies", func() {
+			writeModulesDep(rootDir, "mod_a.ko:\nmod_b.ko:\nmod_c.ko:\n", "softdep mod_a pre: mod_b\nsoftdep mod_b pre: mod_c\n")
+
+			gomock.InOrder(
+				sc.EXPECT().IsModuleLoaded("mod_a").Return(true, nil),
+				sc.EXPECT().DeleteModule("mod_a", false),
+				sc.EXPECT().IsModuleLoaded("mod_b").Return(true, nil),
+				sc.EXPECT().DeleteModule("mod_b", false),
+				sc.EXPECT().IsModuleLoaded("mod_c").Return(true, nil),
+				sc.EXPECT().DeleteModule("mod_c", false),
+			)
+
+			Expect(
+				mr.Run(ctx, "-rvd", rootDir, "mod_a"),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should remove modules that are not in modules.dep", func() {
+			gomock.InOrder(
+				sc.EXPECT().IsModuleLoaded("intree1").Return(true, nil),
+				sc.EXPECT().DeleteModule("intree1", false),
+				sc.EXPECT().IsModuleLoaded("intree2").Return(false, nil),
+			)
+
+			Expect(
+				mr.Run(ctx, "-rv", "intree1", "intree2"),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should return ErrModuleBusy if the target is in use", func() {
+			writeModulesDep(rootDir, "mod_a.ko:\n", "")
+
+			gomock.InOrder(
+				sc.EXPECT().IsModuleLoaded("mod_a").Return(true, nil),
+				sc.EXPECT().DeleteModule("mod_a", false).Return(unix.EBUSY),
+			)
+
+			Expect(
+				mr.Run(ctx, "-rvd", rootDir, "mod_a"),
+			).To(
+				MatchError(ErrModuleBusy),
+			)
+		})
+	})
+})
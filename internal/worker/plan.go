@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+// LoadPlan is what the worker does to load a kernel module, in order.
+type LoadPlan struct {
+	// InTreeModulesToRemove are the in-tree modules unloaded before anything else.
+	InTreeModulesToRemove []string
+	// FirmwareSrcDir is the directory from which firmware files are copied, or an empty string if there is no firmware.
+	FirmwareSrcDir string
+	// FirmwareDstDir is the directory into which firmware files are copied.
+	FirmwareDstDir string
+	// FirmwareFiles are the firmware files to copy, relative to FirmwareSrcDir.
+	FirmwareFiles []string
+	// Hooks are the hooks run before and after modprobe.
+	Hooks kmmv1beta1.ModprobeHooks
+	// ModprobeArgs are the arguments passed to modprobe to load the module.
+	ModprobeArgs []string
+}
+
+func (p *LoadPlan) inTreeRemovalArgs() []string {
+	return append([]string{"-rv"}, p.InTreeModulesToRemove...)
+}
+
+// Print writes the operations in p to w, one per line.
+func (p *LoadPlan) Print(w io.Writer) error {
+	lines := make([]string, 0)
+
+	if len(p.InTreeModulesToRemove) > 0 {
+		lines = append(lines, "modprobe "+strings.Join(p.inTreeRemovalArgs(), " "))
+	}
+
+	for _, f := range p.FirmwareFiles {
+		lines = append(
+			lines,
+			fmt.Sprintf("copy %s to %s", filepath.Join(p.FirmwareSrcDir, f), filepath.Join(p.FirmwareDstDir, f)),
+		)
+	}
+
+	if h := hookLine(hookPreLoad, p.Hooks.PreLoad); h != "" {
+		lines = append(lines, h)
+	}
+
+	lines = append(lines, "modprobe "+strings.Join(p.ModprobeArgs, " "))
+
+	if h := hookLine(hookPostLoad, p.Hooks.PostLoad); h != "" {
+		lines = append(lines, h)
+	}
+
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(w, l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hookLine(name string, hook *kmmv1beta1.ModprobeHook) string {
+	if hook == nil || len(hook.Command) == 0 {
+		return ""
+	}
+
+	args := append([]string{filepath.Join(hooksRootDir, hook.Command[0])}, hook.Command[1:]...)
+
+	return fmt.Sprintf("hook %s: %s", name, strings.Join(args, " "))
+}
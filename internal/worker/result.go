@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxTerminationMessageLength is the size above which the kubelet truncates termination messages.
+const maxTerminationMessageLength = 4096
+
+// Result is what the worker did, as written to the termination message of the worker container.
+type Result struct {
+	Modules            []kmmv1beta1.LoadedKernelModule `json:"modules,omitempty"`
+	Parameters         map[string]string               `json:"parameters,omitempty"`
+	FirmwareFiles      []string                        `json:"firmwareFiles,omitempty"`
+	BlacklistedModules []string                        `json:"blacklistedModules,omitempty"`
+	Livepatch          *kmmv1beta1.LivepatchStatus     `json:"livepatch,omitempty"`
+	Taint              *kmmv1beta1.KernelTaint         `json:"taint,omitempty"`
+	Duration           metav1.Duration                 `json:"duration"`
+	Error              *ResultError                    `json:"error,omitempty"`
+}
+
+type ResultError struct {
+	Reason  kmmv1beta1.WorkerFailureReason `json:"reason"`
+	Message string                         `json:"message"`
+}
+
+func NewResultError(err error) *ResultError {
+	return &ResultError{
+		Reason:  classifyError(err),
+		Message: err.Error(),
+	}
+}
+
+var errorReasons = []struct {
+	err    error
+	reason kmmv1beta1.WorkerFailureReason
+}{
+	{err: ErrArchMismatch, reason: kmmv1beta1.WorkerFailureReasonArchMismatch},
+	{err: ErrFirmwareConflict, reason: kmmv1beta1.WorkerFailureReasonFirmwareConflict},
+	{err: ErrHookFailed, reason: kmmv1beta1.WorkerFailureReasonHookFailed},
+	{err: ErrInvalidModuleFormat, reason: kmmv1beta1.WorkerFailureReasonInvalidModule},
+	{err: ErrInvalidSignature, reason: kmmv1beta1.WorkerFailureReasonInvalidSignature},
+	{err: ErrKernelMismatch, reason: kmmv1beta1.WorkerFailureReasonKernelMismatch},
+	{err: ErrLivepatchTransition, reason: kmmv1beta1.WorkerFailureReasonLivepatchTransition},
+	{err: ErrMissingDependency, reason: kmmv1beta1.WorkerFailureReasonMissingDependency},
+	{err: ErrModuleBusy, reason: kmmv1beta1.WorkerFailureReasonModuleInUse},
+	{err: ErrModuleNotFound, reason: kmmv1beta1.WorkerFailureReasonModuleNotFound},
+	{err: ErrModuleNotSigned, reason: kmmv1beta1.WorkerFailureReasonInvalidSignature},
+	{err: ErrParamNotWritable, reason: kmmv1beta1.WorkerFailureReasonParameterNotWritable},
+	{err: ErrSignatureMismatch, reason: kmmv1beta1.WorkerFailureReasonInvalidSignature},
+	{err: ErrSignatureRejected, reason: kmmv1beta1.WorkerFailureReasonInvalidSignature},
+	{err: ErrUnknownSymbol, reason: kmmv1beta1.WorkerFailureReasonUnknownSymbol},
+}
+
+func classifyError(err error) kmmv1beta1.WorkerFailureReason {
+	for _, er := range errorReasons {
+		if errors.Is(err, er.err) {
+			return er.reason
+		}
+	}
+
+	return kmmv1beta1.WorkerFailureReasonError
+}
+
+// TerminationMessage returns the JSON representation of r.
+// If it does not fit in a termination message, the parameters and the firmware files are left out, and the error
+// message is truncated.
+func (r *Result) TerminationMessage() ([]byte, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal the result: %v", err)
+	}
+
+	if len(b) <= maxTerminationMessageLength {
+		return b, nil
+	}
+
+	short := *r
+	short.Parameters = nil
+	short.FirmwareFiles = nil
+
+	if short.Error != nil {
+		e := *short.Error
+		short.Error = &e
+	}
+
+	for {
+		if b, err = json.Marshal(&short); err != nil {
+			return nil, fmt.Errorf("could not marshal the result: %v", err)
+		}
+
+		excess := len(b) - maxTerminationMessageLength
+
+		if excess <= 0 {
+			return b, nil
+		}
+
+		if short.Error == nil || short.Error.Message == "" {
+			return nil, fmt.Errorf("the result is %d bytes long, more than the maximum of %d", len(b), maxTerminationMessageLength)
+		}
+
+		msg := short.Error.Message
+		short.Error.Message = strings.ToValidUTF8(msg[:max(len(msg)-excess, 0)], "")
+	}
+}
+
+// ParseResult parses the termination message of a worker container.
+func ParseResult(msg string) (*Result, error) {
+	r := Result{}
+
+	if err := json.Unmarshal([]byte(msg), &r); err != nil {
+		return nil, fmt.Errorf("could not parse the worker result: %v", err)
+	}
+
+	return &r, nil
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("NewResultError", func() {
+	DescribeTable(
+		"should classify errors",
+		func(err error, reason kmmv1beta1.WorkerFailureReason) {
+			Expect(
+				NewResultError(err),
+			).To(
+				Equal(&ResultError{Reason: reason, Message: err.Error()}),
+			)
+		},
+		Entry(nil, errors.New("random error"), kmmv1beta1.WorkerFailureReasonError),
+		Entry(nil, fmt.Errorf("wrapped: %w", ErrKernelMismatch), kmmv1beta1.WorkerFailureReasonKernelMismatch),
+		Entry(nil, &KmodError{Err: ErrUnknownSymbol}, kmmv1beta1.WorkerFailureReasonUnknownSymbol),
+		Entry(nil, fmt.Errorf("%w: details", ErrModuleNotSigned), kmmv1beta1.WorkerFailureReasonInvalidSignature),
+		Entry(nil, fmt.Errorf("%w: details", ErrModuleBusy), kmmv1beta1.WorkerFailureReasonModuleInUse),
+		Entry(nil, fmt.Errorf("%w: details", ErrHookFailed), kmmv1beta1.WorkerFailureReasonHookFailed),
+	)
+})
+
+var _ = Describe("Result_TerminationMessage", func() {
+	It("should round-trip through ParseResult", func() {
+		res := &Result{
+			Modules:       []kmmv1beta1.LoadedKernelModule{{Name: "kmm_a", SrcVersion: "ABC"}},
+			Parameters:    map[string]string{"debug": "1"},
+			FirmwareFiles: []string{"fw.bin"},
+			Duration:      metav1.Duration{Duration: 1500 * time.Millisecond},
+		}
+
+		b, err := res.TerminationMessage()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			ParseResult(string(b)),
+		).To(
+			Equal(res),
+		)
+	})
+
+	It("should shorten results that are too long", func() {
+		res := &Result{
+			Modules:       []kmmv1beta1.LoadedKernelModule{{Name: "kmm_a"}},
+			FirmwareFiles: []string{strings.Repeat("a", maxTerminationMessageLength)},
+			Error: &ResultError{
+				Reason:  kmmv1beta1.WorkerFailureReasonError,
+				Message: strings.Repeat("é", maxTerminationMessageLength),
+			},
+		}
+
+		b, err := res.TerminationMessage()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(b)).To(BeNumerically("<=", maxTerminationMessageLength))
+
+		parsed, err := ParseResult(string(b))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Modules).To(Equal(res.Modules))
+		Expect(parsed.FirmwareFiles).To(BeEmpty())
+		Expect(parsed.Error.Reason).To(Equal(kmmv1beta1.WorkerFailureReasonError))
+		Expect(res.Error.Message).To(HavePrefix(parsed.Error.Message))
+	})
+})
+
+var _ = Describe("ParseResult", func() {
+	It("should return an error if the message is not JSON", func() {
+		_, err := ParseResult("some logs")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// HostStatus is the state on the node of the kernel modules and firmware files referenced by a ModuleConfig.
+type HostStatus struct {
+	Modules  []ModuleState        `json:"modules"`
+	Firmware []FirmwareFileStatus `json:"firmware,omitempty"`
+}
+
+type FirmwareFileStatus struct {
+	// Path is relative to the firmware directory.
+	Path    string `json:"path"`
+	Present bool   `json:"present"`
+}
+
+// KmodStatus returns the state of every kernel module referenced by cfg: the module itself, the modules in its
+// loading order and the in-tree modules to remove.
+// If cfg has firmware and firmwareMountPath is set, it also reports which firmware files of the image are present in
+// firmwareMountPath.
+func (w *worker) KmodStatus(cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) (*HostStatus, error) {
+	names := make([]string, 0)
+	seen := sets.New[string]()
+
+	candidates := []string{cfg.Modprobe.ModuleName}
+	candidates = append(candidates, cfg.Modprobe.ModulesLoadingOrder...)
+	candidates = append(candidates, cfg.InTreeModulesToRemove...)
+	candidates = append(candidates, cfg.InTreeModuleToRemove)
+
+	for _, n := range candidates {
+		if n == "" || seen.Has(n) {
+			continue
+		}
+
+		seen.Insert(n)
+		names = append(names, n)
+	}
+
+	hs := HostStatus{Modules: make([]ModuleState, 0, len(names))}
+
+	for _, n := range names {
+		state, err := w.mc.ModuleState(n)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the state of module %s: %v", n, err)
+		}
+
+		hs.Modules = append(hs.Modules, *state)
+	}
+
+	if cfg.Modprobe.FirmwarePath == "" || firmwareMountPath == "" {
+		return &hs, nil
+	}
+
+	imageFirmwarePath := filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath)
+
+	files, err := listFiles(imageFirmwarePath)
+	if err != nil {
+		w.logger.Info(utils.WarnString("could not list the firmware files of the image"), "error", err)
+		return &hs, nil
+	}
+
+	hs.Firmware = make([]FirmwareFileStatus, 0, len(files))
+
+	for _, f := range files {
+		_, err = os.Stat(filepath.Join(firmwareMountPath, f))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("could not check if firmware file %s is present: %v", f, err)
+		}
+
+		hs.Firmware = append(hs.Firmware, FirmwareFileStatus{Path: f, Present: err == nil})
+	}
+
+	return &hs, nil
+}
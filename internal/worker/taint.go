@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+)
+
+var procTaintedPath = "/proc/sys/kernel/tainted"
+
+// taintFlags are the kernel taint flags, indexed by their bit in /proc/sys/kernel/tainted.
+// See https://docs.kernel.org/admin-guide/tainted-kernels.html.
+var taintFlags = []struct {
+	letter byte
+	flag   kmmv1beta1.TaintFlag
+}{
+	{letter: 'P', flag: "ProprietaryModule"},
+	{letter: 'F', flag: "ForcedModule"},
+	{letter: 'S', flag: "CPUOutOfSpec"},
+	{letter: 'R', flag: "ForcedUnload"},
+	{letter: 'M', flag: "MachineCheck"},
+	{letter: 'B', flag: "BadPage"},
+	{letter: 'U', flag: "User"},
+	{letter: 'D', flag: "Die"},
+	{letter: 'A', flag: "OverriddenACPITable"},
+	{letter: 'W', flag: "Warning"},
+	{letter: 'C', flag: "StagingDriver"},
+	{letter: 'I', flag: "FirmwareWorkaround"},
+	{letter: 'O', flag: "OutOfTreeModule"},
+	{letter: 'E', flag: "UnsignedModule"},
+	{letter: 'L', flag: "SoftLockup"},
+	{letter: 'K', flag: "Livepatch"},
+	{letter: 'X', flag: "Auxiliary"},
+	{letter: 'T', flag: "Randstruct"},
+	{letter: 'N', flag: "Test"},
+	{letter: 'J', flag: "Fwctl"},
+}
+
+// decodeKernelTaint returns the flags that are set in the value of /proc/sys/kernel/tainted.
+// Bits that are unknown to this version of KMM are reported as Bit<n>.
+func decodeKernelTaint(value uint64) []kmmv1beta1.TaintFlag {
+	flags := make([]kmmv1beta1.TaintFlag, 0)
+
+	for bit := 0; bit < 64; bit++ {
+		if value&(1<<bit) == 0 {
+			continue
+		}
+
+		if bit < len(taintFlags) {
+			flags = append(flags, taintFlags[bit].flag)
+		} else {
+			flags = append(flags, kmmv1beta1.TaintFlag(fmt.Sprintf("Bit%d", bit)))
+		}
+	}
+
+	return flags
+}
+
+// decodeModuleTaint returns the flags that correspond to the letters in /sys/module/<name>/taint.
+// Unknown letters are reported as they are.
+func decodeModuleTaint(letters string) []kmmv1beta1.TaintFlag {
+	flags := make([]kmmv1beta1.TaintFlag, 0, len(letters))
+
+	for i := 0; i < len(letters); i++ {
+		flag := kmmv1beta1.TaintFlag(letters[i : i+1])
+
+		for _, tf := range taintFlags {
+			if tf.letter == letters[i] {
+				flag = tf.flag
+				break
+			}
+		}
+
+		flags = append(flags, flag)
+	}
+
+	return flags
+}
+
+// readKernelTaint reads the taint flags of the running kernel and, if moduleName is not empty, those that the module
+// set.
+// It returns nil if neither the kernel nor the module is tainted.
+func readKernelTaint(moduleName string) (*kmmv1beta1.KernelTaint, error) {
+	b, err := os.ReadFile(procTaintedPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", procTaintedPath, err)
+	}
+
+	value, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", procTaintedPath, err)
+	}
+
+	taint := kmmv1beta1.KernelTaint{Kernel: decodeKernelTaint(value)}
+
+	if moduleName != "" {
+		path := filepath.Join(sysModuleDir, normalizeModuleName(moduleName), "taint")
+
+		b, err = os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("could not read %s: %v", path, err)
+		}
+
+		taint.Module = decodeModuleTaint(strings.TrimSpace(string(b)))
+	}
+
+	if len(taint.Kernel) == 0 && len(taint.Module) == 0 {
+		return nil, nil
+	}
+
+	return &taint, nil
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("decodeKernelTaint", func() {
+	DescribeTable(
+		"should decode the bitmask",
+		func(value uint64, expected []kmmv1beta1.TaintFlag) {
+			Expect(decodeKernelTaint(value)).To(Equal(expected))
+		},
+		Entry("not tainted", uint64(0), []kmmv1beta1.TaintFlag{}),
+		Entry("proprietary module", uint64(1), []kmmv1beta1.TaintFlag{"ProprietaryModule"}),
+		Entry("warning and livepatch", uint64(1<<9|1<<15), []kmmv1beta1.TaintFlag{"Warning", "Livepatch"}),
+		Entry("unknown bit", uint64(1<<40), []kmmv1beta1.TaintFlag{"Bit40"}),
+	)
+})
+
+var _ = Describe("decodeModuleTaint", func() {
+	It("should decode known and unknown letters", func() {
+		Expect(
+			decodeModuleTaint("POZ"),
+		).To(
+			Equal([]kmmv1beta1.TaintFlag{"ProprietaryModule", "OutOfTreeModule", "Z"}),
+		)
+	})
+})
+
+var _ = Describe("readKernelTaint", func() {
+	BeforeEach(func() {
+		procTaintedPath = filepath.Join(GinkgoT().TempDir(), "tainted")
+		sysModuleDir = GinkgoT().TempDir()
+	})
+
+	AfterEach(func() {
+		procTaintedPath = "/proc/sys/kernel/tainted"
+		sysModuleDir = "/sys/module"
+	})
+
+	It("should return nil if nothing is tainted", func() {
+		Expect(os.WriteFile(procTaintedPath, []byte("0\n"), 0644)).To(Succeed())
+
+		Expect(readKernelTaint("kmm-a")).To(BeNil())
+	})
+
+	It("should return the kernel flags if the module did not taint the kernel", func() {
+		Expect(os.WriteFile(procTaintedPath, []byte("512\n"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(sysModuleDir, "kmm_a"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sysModuleDir, "kmm_a", "taint"), []byte("\n"), 0644)).To(Succeed())
+
+		Expect(
+			readKernelTaint("kmm-a"),
+		).To(
+			Equal(&kmmv1beta1.KernelTaint{Kernel: []kmmv1beta1.TaintFlag{"Warning"}, Module: []kmmv1beta1.TaintFlag{}}),
+		)
+	})
+
+	It("should return an error if the kernel value is malformed", func() {
+		Expect(os.WriteFile(procTaintedPath, []byte("abc\n"), 0644)).To(Succeed())
+
+		_, err := readKernelTaint("kmm-a")
+		Expect(err).To(HaveOccurred())
+	})
+})
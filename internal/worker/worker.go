@@ -2,42 +2,123 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	kmmv1beta1 "github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
-	cp "github.com/otiai10/copy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 //go:generate mockgen -source=worker.go -package=worker -destination=mock_worker.go
 
 type Worker interface {
-	LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error
+	KmodStatus(cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) (*HostStatus, error)
+	LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, opts KmodOptions) (*Result, error)
+	PlanLoadKmod(cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) (*LoadPlan, error)
 	SetFirmwareClassPath(value string) error
-	UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error
+	SetKmodParameters(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) (*Result, error)
+	UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, opts KmodOptions) (*Result, error)
+}
+
+// KmodOptions describe the host resources that the worker manages when loading and unloading a kernel module.
+type KmodOptions struct {
+	// FirmwareMountPath is where the host firmware directory is mounted.
+	FirmwareMountPath string
+	// FirmwareOwner identifies the Module on behalf of which firmware files are installed.
+	// The module name is used if it is empty.
+	FirmwareOwner string
+	// BlacklistPath is the host modprobe configuration file in which the in-tree modules to remove are blacklisted.
+	// The in-tree modules are not blacklisted if it is empty.
+	BlacklistPath string
 }
 
 type worker struct {
 	logger logr.Logger
+	hr     HookRunner
+	mc     ModuleChecker
 	mr     ModprobeRunner
 	fh     utils.FSHelper
+	fm     FirmwareManager
 }
 
-func NewWorker(mr ModprobeRunner, fh utils.FSHelper, logger logr.Logger) Worker {
+func NewWorker(mr ModprobeRunner, mc ModuleChecker, hr HookRunner, fh utils.FSHelper, fm FirmwareManager, logger logr.Logger) Worker {
 	return &worker{
 		logger: logger,
+		hr:     hr,
+		mc:     mc,
 		mr:     mr,
 		fh:     fh,
+		fm:     fm,
 	}
 }
 
 const sharedFilesDir = "/tmp"
 
-func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
+// LoadKmod loads the kernel module described by cfg.
+// The returned Result is never nil: it describes what was done, even if an error is returned.
+func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, opts KmodOptions) (*Result, error) {
+	res := &Result{}
+
+	err := measure(res, func() error {
+		return w.loadKmod(ctx, cfg, opts, res)
+	})
+
+	return res, err
+}
+
+// measure runs fn and records its duration and its error in res.
+func measure(res *Result, fn func() error) error {
+	start := time.Now()
+
+	err := fn()
+
+	res.Duration = metav1.Duration{Duration: time.Since(start)}
+
+	if err != nil {
+		res.Error = NewResultError(err)
+	}
+
+	return err
+}
+
+// PlanLoadKmod runs the same checks as LoadKmod and returns what LoadKmod would do, without changing anything on the
+// node.
+func (w *worker) PlanLoadKmod(cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) (*LoadPlan, error) {
+	return w.planLoad(cfg, firmwareMountPath, true)
+}
+
+// planLoad checks that the module described by cfg can be loaded and returns what needs to be done to load it.
+// dryRun only changes what is logged, since nothing is done on the node either way.
+func (w *worker) planLoad(cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string, dryRun bool) (*LoadPlan, error) {
+
+	// We cannot know which modules rawArgs would load, so only check the modules when the worker builds the arguments.
+	if cfg.Modprobe.RawArgs == nil {
+		rootDir := filepath.Join(sharedFilesDir, cfg.Modprobe.DirName)
+
+		w.logger.Info("Checking module compatibility with the running kernel", "name", cfg.Modprobe.ModuleName, "dir", rootDir)
+
+		if err := w.mc.CheckModule(rootDir, cfg.Modprobe.ModuleName); err != nil {
+			return nil, fmt.Errorf("module %s cannot be loaded on this node: %w", cfg.Modprobe.ModuleName, err)
+		}
+
+		if cfg.Modprobe.VerifySignature != nil {
+			w.logger.Info("Verifying module signatures", "name", cfg.Modprobe.ModuleName, "certificate", SignatureCertPath)
+
+			if err := w.mc.VerifySignatures(rootDir, cfg.Modprobe.ModuleName, SignatureCertPath); err != nil {
+				return nil, fmt.Errorf("could not verify the signature of module %s: %w", cfg.Modprobe.ModuleName, err)
+			}
+		}
+	}
+
+	plan := &LoadPlan{}
 
 	inTreeModulesToRemove := cfg.InTreeModulesToRemove
 	// [TODO] - remove handling cfg.InTreeModuleToRemove once we cease to support it
@@ -46,7 +127,11 @@ func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fir
 	}
 
 	if inTreeModulesToRemove != nil {
-		w.logger.Info("Unloading in-tree modules", "names", inTreeModulesToRemove)
+		if dryRun {
+			w.logger.Info("Would unload in-tree modules", "names", inTreeModulesToRemove)
+		} else {
+			w.logger.Info("Unloading in-tree modules", "names", inTreeModulesToRemove)
+		}
 		modulesToUnload := make([]string, 0, len(inTreeModulesToRemove))
 		for _, module := range inTreeModulesToRemove {
 			exists, err := w.fh.FileExists("/lib/modules", fmt.Sprintf("^%s.ko", module))
@@ -63,49 +148,164 @@ func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fir
 			modulesToUnload = append(modulesToUnload, module)
 		}
 
-		if len(modulesToUnload) > 0 {
-			runArgs := append([]string{"-rv"}, modulesToUnload...)
-			if err := w.mr.Run(ctx, runArgs...); err != nil {
-				return fmt.Errorf("could not remove in-tree modules %s: %v", strings.Join(modulesToUnload, ""), err)
-			}
-		}
+		plan.InTreeModulesToRemove = modulesToUnload
 	}
 
 	// prepare firmware
 	if cfg.Modprobe.FirmwarePath != "" {
-		imageFirmwarePath := filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath)
-		w.logger.Info("preparing firmware for loading", "image directory", imageFirmwarePath, "host mount directory", firmwareMountPath)
-		options := cp.Options{
-			OnError: func(src, dest string, err error) error {
-				if err != nil {
-					return fmt.Errorf("internal copy error: failed to copy from %s to %s: %v", src, dest, err)
-				}
-				return nil
-			},
-		}
-		if err := cp.Copy(imageFirmwarePath, firmwareMountPath, options); err != nil {
-			return fmt.Errorf("failed to copy firmware from path %s to path %s: %v", imageFirmwarePath, firmwareMountPath, err)
-		}
-	}
+		plan.FirmwareSrcDir = filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath)
+		plan.FirmwareDstDir = firmwareMountPath
 
-	moduleName := cfg.Modprobe.ModuleName
+		files, err := listFiles(plan.FirmwareSrcDir)
+		if err != nil {
+			w.logger.Info(utils.WarnString("could not list the firmware files"), "error", err)
+		}
 
-	var args []string
+		plan.FirmwareFiles = files
+	}
 
 	if cfg.Modprobe.RawArgs != nil {
-		args = cfg.Modprobe.RawArgs.Load
+		plan.ModprobeArgs = cfg.Modprobe.RawArgs.Load
 	} else {
-		args = []string{"-vd", filepath.Join(sharedFilesDir, cfg.Modprobe.DirName)}
+		plan.ModprobeArgs = []string{"-vd", filepath.Join(sharedFilesDir, cfg.Modprobe.DirName)}
 
 		if cfg.Modprobe.Args != nil {
-			args = append(args, cfg.Modprobe.Args.Load...)
+			plan.ModprobeArgs = append(plan.ModprobeArgs, cfg.Modprobe.Args.Load...)
 		}
 
-		args = append(args, moduleName)
-		args = append(args, cfg.Modprobe.Parameters...)
+		plan.ModprobeArgs = append(plan.ModprobeArgs, cfg.Modprobe.ModuleName)
+		plan.ModprobeArgs = append(plan.ModprobeArgs, cfg.Modprobe.Parameters...)
+	}
+
+	if cfg.Modprobe.Hooks != nil {
+		plan.Hooks = *cfg.Modprobe.Hooks
+	}
+
+	return plan, nil
+}
+
+func (w *worker) loadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, opts KmodOptions, res *Result) error {
+	plan, err := w.planLoad(cfg, opts.FirmwareMountPath, false)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.InTreeModulesToRemove) > 0 {
+		if err = w.mr.Run(ctx, plan.inTreeRemovalArgs()...); err != nil {
+			return fmt.Errorf("could not remove in-tree modules %s: %v", strings.Join(plan.InTreeModulesToRemove, ""), err)
+		}
+
+		if opts.BlacklistPath != "" {
+			w.logger.Info("Blacklisting in-tree modules", "names", plan.InTreeModulesToRemove, "path", opts.BlacklistPath)
+
+			if err = writeBlacklist(opts.BlacklistPath, plan.InTreeModulesToRemove); err != nil {
+				return err
+			}
+
+			res.BlacklistedModules = plan.InTreeModulesToRemove
+		}
+	}
+
+	if plan.FirmwareSrcDir != "" {
+		w.logger.Info("preparing firmware for loading", "image directory", plan.FirmwareSrcDir, "host mount directory", plan.FirmwareDstDir)
+		if err = w.fm.Install(firmwareOwner(opts, cfg), plan.FirmwareSrcDir, plan.FirmwareDstDir, plan.FirmwareFiles); err != nil {
+			return fmt.Errorf("failed to copy firmware from path %s to path %s: %w", plan.FirmwareSrcDir, plan.FirmwareDstDir, err)
+		}
+
+		res.FirmwareFiles = plan.FirmwareFiles
+	}
+
+	if err = w.runHook(ctx, hookPreLoad, plan.Hooks.PreLoad); err != nil {
+		return err
+	}
+
+	if err = w.mr.Run(ctx, plan.ModprobeArgs...); err != nil {
+		return err
+	}
+
+	if lp := cfg.Modprobe.Livepatch; lp != nil {
+		if res.Livepatch, err = w.waitForLivepatch(ctx, cfg.Modprobe.ModuleName, lp); err != nil {
+			return err
+		}
+	}
+
+	if err = w.runHook(ctx, hookPostLoad, plan.Hooks.PostLoad); err != nil {
+		return err
+	}
+
+	if cfg.Modprobe.RawArgs == nil {
+		w.recordLoadedState(filepath.Join(sharedFilesDir, cfg.Modprobe.DirName), cfg.Modprobe.ModuleName, res)
+	}
+
+	if res.Taint, err = readKernelTaint(cfg.Modprobe.ModuleName); err != nil {
+		w.logger.Info(utils.WarnString("could not read the kernel taint flags"), "error", err)
+	} else if res.Taint != nil {
+		w.logger.Info("The kernel is tainted", "kernel", res.Taint.Kernel, "module", res.Taint.Module)
+	}
+
+	return nil
+}
+
+// recordLoadedState adds the modules that are now loaded and the module's parameters to res.
+// The module is loaded at this point, so errors are only logged.
+func (w *worker) recordLoadedState(rootDir, moduleName string, res *Result) {
+	var err error
+
+	if res.Modules, err = w.mc.LoadedModules(rootDir, moduleName); err != nil {
+		w.logger.Info(utils.WarnString("could not list the loaded modules"), "error", err)
+	}
+
+	if res.Parameters, err = w.mc.ModuleParameters(moduleName); err != nil {
+		w.logger.Info(utils.WarnString("could not read the module parameters"), "error", err)
+	}
+}
+
+// firmwareOwner returns the owner of the firmware files that the module installs: the Module set in opts, or the
+// kernel module name if none is set.
+func firmwareOwner(opts KmodOptions, cfg *kmmv1beta1.ModuleConfig) string {
+	if opts.FirmwareOwner != "" {
+		return opts.FirmwareOwner
+	}
+
+	return cfg.Modprobe.ModuleName
+}
+
+// listFiles returns the regular files under dir, relative to dir.
+func listFiles(dir string) ([]string, error) {
+	files := make([]string, 0)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, rel)
+
+		return nil
+	})
+
+	return files, err
+}
+
+func (w *worker) runHook(ctx context.Context, name string, hook *kmmv1beta1.ModprobeHook) error {
+	if hook == nil {
+		return nil
 	}
 
-	return w.mr.Run(ctx, args...)
+	if err := w.hr.RunHook(ctx, name, hook); err != nil {
+		return fmt.Errorf("%w: error while running the %s hook: %v", ErrHookFailed, name, err)
+	}
+
+	return nil
 }
 
 var firmwareClassPathLocation = FirmwareClassPathLocation
@@ -132,7 +332,48 @@ func (w *worker) SetFirmwareClassPath(value string) error {
 	return nil
 }
 
-func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
+// SetKmodParameters changes the parameters of the loaded kernel module described by cfg to the values in
+// cfg.Modprobe.Parameters, without reloading it.
+// It returns an error wrapping ErrParamNotWritable if one of the parameters cannot be changed at runtime.
+// The returned Result is never nil.
+func (w *worker) SetKmodParameters(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) (*Result, error) {
+	res := &Result{}
+
+	err := measure(res, func() error {
+		if cfg.Modprobe.RawArgs != nil {
+			return fmt.Errorf("%w: parameters cannot be changed when rawArgs are used", ErrParamNotWritable)
+		}
+
+		moduleName := cfg.Modprobe.ModuleName
+
+		w.logger.Info("Setting module parameters", "name", moduleName, "parameters", cfg.Modprobe.Parameters)
+
+		if err := writeModuleParameters(moduleName, cfg.Modprobe.Parameters); err != nil {
+			return fmt.Errorf("could not set the parameters of module %s: %w", moduleName, err)
+		}
+
+		w.recordLoadedState(filepath.Join(sharedFilesDir, cfg.Modprobe.DirName), moduleName, res)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// UnloadKmod unloads the kernel module described by cfg.
+// Firmware files are only removed if the Module was their last owner.
+// The returned Result is never nil.
+func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, opts KmodOptions) (*Result, error) {
+	res := &Result{}
+
+	err := measure(res, func() error {
+		return w.unloadKmod(ctx, cfg, opts)
+	})
+
+	return res, err
+}
+
+func (w *worker) unloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, opts KmodOptions) error {
 
 	moduleName := cfg.Modprobe.ModuleName
 
@@ -143,6 +384,10 @@ func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, f
 	} else {
 		args = []string{"-rvd", filepath.Join(sharedFilesDir, cfg.Modprobe.DirName)}
 
+		if p := cfg.Modprobe.UnloadPolicy; p != nil && p.Type == kmmv1beta1.UnloadPolicyForce {
+			args = append(args, "--force")
+		}
+
 		if cfg.Modprobe.Args != nil {
 			args = append(args, cfg.Modprobe.Args.Unload...)
 		}
@@ -150,20 +395,165 @@ func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, f
 		args = append(args, moduleName)
 	}
 
+	hooks := cfg.Modprobe.Hooks
+	if hooks == nil {
+		hooks = &kmmv1beta1.ModprobeHooks{}
+	}
+
+	if err := w.runHook(ctx, hookPreUnload, hooks.PreUnload); err != nil {
+		return err
+	}
+
+	if lp := cfg.Modprobe.Livepatch; lp != nil {
+		if err := w.disableLivepatch(ctx, moduleName, lp); err != nil {
+			return err
+		}
+	}
+
+	// The preUnload hook may release the module, so only check its usage afterward.
+	if cfg.Modprobe.RawArgs == nil {
+		if err := w.checkModuleUsage(ctx, moduleName, cfg.Modprobe.UnloadPolicy); err != nil {
+			return err
+		}
+	}
+
 	w.logger.Info("Unloading module", "name", moduleName)
 
 	if err := w.mr.Run(ctx, args...); err != nil {
 		return fmt.Errorf("could not unload module %s: %v", moduleName, err)
 	}
 
+	if err := w.runHook(ctx, hookPostUnload, hooks.PostUnload); err != nil {
+		return err
+	}
+
 	//remove firmware files only (no directories)
 	if cfg.Modprobe.FirmwarePath != "" {
 		imageFirmwarePath := filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath)
-		err := w.fh.RemoveSrcFilesFromDst(imageFirmwarePath, firmwareMountPath)
+		err := w.fm.Remove(firmwareOwner(opts, cfg), imageFirmwarePath, opts.FirmwareMountPath)
 		if err != nil {
 			w.logger.Info(utils.WarnString("failed to remove all firmware blobs"), "error", err)
 		}
 	}
 
+	if opts.BlacklistPath != "" {
+		w.logger.Info("Removing the in-tree modules blacklist", "path", opts.BlacklistPath)
+
+		if err := removeBlacklist(opts.BlacklistPath); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// waitForLivepatch waits for the transition of a livepatch that was just loaded to complete.
+func (w *worker) waitForLivepatch(ctx context.Context, name string, lp *kmmv1beta1.Livepatch) (*kmmv1beta1.LivepatchStatus, error) {
+	timeout := livepatchTransitionTimeout(lp)
+
+	w.logger.Info("Waiting for the livepatch transition to complete", "name", name, "timeout", timeout)
+
+	status, err := waitForLivepatchTransition(ctx, name, timeout)
+	if err != nil {
+		return status, err
+	}
+
+	w.logger.Info("Livepatch transition completed", "name", name, "enabled", status.Enabled)
+
+	return status, nil
+}
+
+// disableLivepatch disables a livepatch and waits for the transition to complete, so that its module can be removed.
+// It does nothing if the livepatch is not registered.
+func (w *worker) disableLivepatch(ctx context.Context, name string, lp *kmmv1beta1.Livepatch) error {
+	logger := w.logger.WithValues("name", name)
+
+	status, err := readLivepatchStatus(name)
+	if err != nil {
+		if errors.Is(err, errLivepatchNotRegistered) {
+			logger.Info("Livepatch is not registered; not disabling it")
+			return nil
+		}
+
+		return err
+	}
+
+	if status.Enabled {
+		logger.Info("Disabling livepatch")
+
+		if err = disableLivepatch(name); err != nil {
+			return err
+		}
+	}
+
+	timeout := livepatchTransitionTimeout(lp)
+
+	logger.Info("Waiting for the livepatch transition to complete", "timeout", timeout)
+
+	if _, err = waitForLivepatchTransition(ctx, name, timeout); err != nil {
+		return err
+	}
+
+	logger.Info("Livepatch transition completed")
+
+	return nil
+}
+
+const defaultUnloadWaitTimeout = 300 * time.Second
+
+var moduleUsagePollInterval = 2 * time.Second
+
+// checkModuleUsage returns an error wrapping ErrModuleBusy if moduleName is in use and policy does not allow unloading
+// it.
+// With the Wait policy, it polls the module's usage until the module is released or the timeout expires.
+func (w *worker) checkModuleUsage(ctx context.Context, moduleName string, policy *kmmv1beta1.UnloadPolicy) error {
+	if policy == nil {
+		policy = &kmmv1beta1.UnloadPolicy{Type: kmmv1beta1.UnloadPolicyFail}
+	}
+
+	usage, err := w.mc.ModuleUsage(moduleName)
+	if err != nil {
+		return fmt.Errorf("could not check if module %s is in use: %v", moduleName, err)
+	}
+
+	if usage == nil || !usage.InUse() {
+		return nil
+	}
+
+	logger := w.logger.WithValues("name", moduleName, "policy", policy.Type)
+
+	switch policy.Type {
+	case kmmv1beta1.UnloadPolicyForce:
+		if len(usage.Holders) == 0 {
+			logger.Info(utils.WarnString("Module is in use; forcing the unload"), "reference count", usage.RefCount)
+			return nil
+		}
+	case kmmv1beta1.UnloadPolicyWait:
+		timeout := defaultUnloadWaitTimeout
+
+		if policy.TimeoutSeconds > 0 {
+			timeout = time.Duration(policy.TimeoutSeconds) * time.Second
+		}
+
+		logger.Info("Module is in use; waiting for it to be released", "usage", usage.String(), "timeout", timeout)
+
+		err = wait.PollUntilContextTimeout(ctx, moduleUsagePollInterval, timeout, false, func(_ context.Context) (bool, error) {
+			if usage, err = w.mc.ModuleUsage(moduleName); err != nil {
+				return false, fmt.Errorf("could not check if module %s is in use: %v", moduleName, err)
+			}
+
+			return usage == nil || !usage.InUse(), nil
+		})
+
+		if err == nil {
+			logger.Info("Module was released")
+			return nil
+		}
+
+		if !wait.Interrupted(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: cannot unload module %s: %s", ErrModuleBusy, moduleName, usage)
+}
@@ -6,17 +6,23 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/kubernetes-sigs/kernel-module-management/api/v1beta1"
 	"github.com/kubernetes-sigs/kernel-module-management/internal/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("worker_LoadKmod", func() {
 	var (
 		fh       *utils.MockFSHelper
+		fm       *MockFirmwareManager
+		hr       *MockHookRunner
+		mc       *MockModuleChecker
 		mr       *MockModprobeRunner
 		w        Worker
 		imageDir string
@@ -26,14 +32,21 @@ var _ = Describe("worker_LoadKmod", func() {
 	BeforeEach(func() {
 		ctrl := gomock.NewController(GinkgoT())
 		fh = utils.NewMockFSHelper(ctrl)
+		fm = NewMockFirmwareManager(ctrl)
+		hr = NewMockHookRunner(ctrl)
+		mc = NewMockModuleChecker(ctrl)
 		mr = NewMockModprobeRunner(ctrl)
-		w = NewWorker(mr, fh, GinkgoLogr)
+		w = NewWorker(mr, mc, hr, fh, fm, GinkgoLogr)
 
 		var err error
 		imageDir, err = os.MkdirTemp("", "imageDir")
 		Expect(err).Should(BeNil())
 		hostDir, err = os.MkdirTemp("", "hostMappedDir")
 		Expect(err).Should(BeNil())
+
+		procTaintedPath = filepath.Join(GinkgoT().TempDir(), "tainted")
+		Expect(os.WriteFile(procTaintedPath, []byte("0\n"), 0644)).To(Succeed())
+		sysModuleDir = GinkgoT().TempDir()
 	})
 
 	AfterEach(func() {
@@ -41,6 +54,9 @@ var _ = Describe("worker_LoadKmod", func() {
 		Expect(err).Should(BeNil())
 		err = os.RemoveAll(hostDir)
 		Expect(err).Should(BeNil())
+
+		procTaintedPath = "/proc/sys/kernel/tainted"
+		sysModuleDir = "/sys/module"
 	})
 
 	ctx := context.TODO()
@@ -60,13 +76,50 @@ var _ = Describe("worker_LoadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName).Return(errors.New("random error"))
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName).Return(errors.New("random error")),
+		)
 
-		Expect(
-			w.LoadKmod(ctx, &cfg, ""),
-		).To(
-			HaveOccurred(),
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the module is not compatible with the running kernel", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage:        imageName,
+			InTreeModulesToRemove: []string{"intree1"},
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName).Return(ErrKernelMismatch)
+
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(MatchError(ErrKernelMismatch))
+	})
+
+	It("should verify the module signatures if configured", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				VerifySignature: &v1beta1.ModuleSignatureVerification{
+					CertSecret: v1.LocalObjectReference{Name: "cert"},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().VerifySignatures(filepath.Join(sharedFilesDir, dirName), moduleName, SignatureCertPath).Return(ErrModuleNotSigned),
 		)
+
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(MatchError(ErrModuleNotSigned))
 	})
 
 	It("should remove present-on-host in-tree module if configured", func() {
@@ -82,19 +135,78 @@ var _ = Describe("worker_LoadKmod", func() {
 		}
 
 		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
 			fh.EXPECT().FileExists("/lib/modules", "^intree1.ko").Return(true, nil),
 			fh.EXPECT().FileExists("/lib/modules", "^intree2.ko").Return(false, nil),
 			fh.EXPECT().FileExists("/lib/modules", "^intree3.ko").Return(true, nil),
 			fh.EXPECT().FileExists("/lib/modules", "^intree4.ko").Return(false, fmt.Errorf("some error")),
 			mr.EXPECT().Run(ctx, "-rv", "intree1", "intree3"),
 			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
 		)
 
-		Expect(
-			w.LoadKmod(ctx, &cfg, ""),
-		).NotTo(
-			HaveOccurred(),
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should blacklist the removed in-tree modules if configured", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage:         imageName,
+			InTreeModulesToRemove:  []string{"intree1", "intree2"},
+			BlacklistInTreeModules: true,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		blacklistPath := filepath.Join(GinkgoT().TempDir(), "kmm-ns-name.conf")
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			fh.EXPECT().FileExists("/lib/modules", "^intree1.ko").Return(true, nil),
+			fh.EXPECT().FileExists("/lib/modules", "^intree2.ko").Return(true, nil),
+			mr.EXPECT().Run(ctx, "-rv", "intree1", "intree2"),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
 		)
+
+		res, err := w.LoadKmod(ctx, &cfg, KmodOptions{BlacklistPath: blacklistPath})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.BlacklistedModules).To(Equal([]string{"intree1", "intree2"}))
+
+		b, err := os.ReadFile(blacklistPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(HaveSuffix("\nblacklist intree1\nblacklist intree2\n"))
+	})
+
+	It("should wait for the livepatch transition and report its state", func() {
+		sysLivepatchDir = GinkgoT().TempDir()
+		DeferCleanup(func() { sysLivepatchDir = "/sys/kernel/livepatch" })
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				Livepatch:  &v1beta1.Livepatch{},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName).Do(func(_ context.Context, _ ...string) {
+				setLivepatchState(moduleName, "1", "0")
+			}),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
+		)
+
+		res, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Livepatch).To(Equal(&v1beta1.LivepatchStatus{Enabled: true}))
 	})
 
 	It("should use deprecated InTreeModuleToRemove if configured", func() {
@@ -109,16 +221,16 @@ var _ = Describe("worker_LoadKmod", func() {
 		}
 
 		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
 			fh.EXPECT().FileExists("/lib/modules", "^intreeToRemove.ko").Return(true, nil),
 			mr.EXPECT().Run(ctx, "-rv", "intreeToRemove"),
 			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
 		)
 
-		Expect(
-			w.LoadKmod(ctx, &cfg, ""),
-		).NotTo(
-			HaveOccurred(),
-		)
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should copy all the firmware files/directories if configured", func() {
@@ -138,19 +250,128 @@ var _ = Describe("worker_LoadKmod", func() {
 		err = os.WriteFile(filepath.Join(sharedFilesDir, "firmwareDir", "binDir", "firwmwareFile2"), []byte("some data 2"), 0660)
 		Expect(err).Should(BeNil())
 
-		mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName)
+		files := []string{"binDir/firwmwareFile2", "firwmwareFile1"}
 
-		Expect(
-			w.LoadKmod(ctx, &cfg, hostDir),
-		).NotTo(
-			HaveOccurred(),
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			fm.EXPECT().Install("ns/name", filepath.Join(sharedFilesDir, "firmwareDir"), hostDir, files),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
 		)
-		_, err = os.Stat(hostDir + "/binDir")
-		Expect(err).Should(BeNil())
-		_, err = os.Stat(hostDir + "/binDir/firwmwareFile2")
+
+		res, err := w.LoadKmod(ctx, &cfg, KmodOptions{FirmwareMountPath: hostDir, FirmwareOwner: "ns/name"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.FirmwareFiles).To(Equal(files))
+	})
+
+	It("should not load the module if a firmware file conflicts with another Module", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   moduleName,
+				DirName:      dirName,
+				FirmwarePath: "/firmwareDir",
+			},
+		}
+
+		fwDir := filepath.Join(sharedFilesDir, "firmwareDir")
+		Expect(os.RemoveAll(fwDir)).To(Succeed())
+		DeferCleanup(os.RemoveAll, fwDir)
+
+		err := os.MkdirAll(fwDir, 0750)
 		Expect(err).Should(BeNil())
-		_, err = os.Stat(hostDir + "/firwmwareFile1")
+		err = os.WriteFile(filepath.Join(fwDir, "firwmwareFile1"), []byte("some data 1"), 0660)
 		Expect(err).Should(BeNil())
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			fm.
+				EXPECT().
+				Install(moduleName, filepath.Join(sharedFilesDir, "firmwareDir"), hostDir, []string{"firwmwareFile1"}).
+				Return(ErrFirmwareConflict),
+		)
+
+		res, err := w.LoadKmod(ctx, &cfg, KmodOptions{FirmwareMountPath: hostDir})
+		Expect(err).To(MatchError(ErrFirmwareConflict))
+		Expect(res.Error.Reason).To(Equal(v1beta1.WorkerFailureReasonFirmwareConflict))
+	})
+
+	It("should record the loaded modules and their parameters in the result", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		modules := []v1beta1.LoadedKernelModule{
+			{Name: "dep", SrcVersion: "0123456789ABCDEF"},
+			{Name: moduleName},
+		}
+
+		params := map[string]string{"debug": "1"}
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName).Return(modules, nil),
+			mc.EXPECT().ModuleParameters(moduleName).Return(params, nil),
+		)
+
+		res, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Modules).To(Equal(modules))
+		Expect(res.Parameters).To(Equal(params))
+		Expect(res.Error).To(BeNil())
+	})
+
+	It("should record the taint flags in the result", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		Expect(os.WriteFile(procTaintedPath, []byte("12288\n"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(sysModuleDir, moduleName), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sysModuleDir, moduleName, "taint"), []byte("OE\n"), 0644)).To(Succeed())
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
+		)
+
+		res, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Taint).To(Equal(&v1beta1.KernelTaint{
+			Kernel: []v1beta1.TaintFlag{"OutOfTreeModule", "UnsignedModule"},
+			Module: []v1beta1.TaintFlag{"OutOfTreeModule", "UnsignedModule"},
+		}))
+	})
+
+	It("should classify the error in the result", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName).Return(ErrKernelMismatch)
+
+		res, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(res.Error).To(Equal(&ResultError{
+			Reason:  v1beta1.WorkerFailureReasonKernelMismatch,
+			Message: err.Error(),
+		}))
 	})
 
 	It("should use rawArgs if they are defined", func() {
@@ -165,11 +386,59 @@ var _ = Describe("worker_LoadKmod", func() {
 
 		mr.EXPECT().Run(ctx, ToInterfaceSlice(rawArgs)...)
 
-		Expect(
-			w.LoadKmod(ctx, &cfg, ""),
-		).NotTo(
-			HaveOccurred(),
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should run the load hooks around modprobe", func() {
+		preLoad := &v1beta1.ModprobeHook{Command: []string{"/pre-load.sh"}}
+		postLoad := &v1beta1.ModprobeHook{Command: []string{"/post-load.sh"}}
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				Hooks: &v1beta1.ModprobeHooks{
+					PreLoad:   preLoad,
+					PostLoad:  postLoad,
+					PreUnload: &v1beta1.ModprobeHook{Command: []string{"/pre-unload.sh"}},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			hr.EXPECT().RunHook(ctx, hookPreLoad, preLoad),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			hr.EXPECT().RunHook(ctx, hookPostLoad, postLoad),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
 		)
+
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not run modprobe if the preLoad hook failed", func() {
+		preLoad := &v1beta1.ModprobeHook{Command: []string{"/pre-load.sh"}}
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				Hooks:      &v1beta1.ModprobeHooks{PreLoad: preLoad},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			hr.EXPECT().RunHook(ctx, hookPreLoad, preLoad).Return(errors.New("random error")),
+		)
+
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(MatchError(ContainSubstring("error while running the preLoad hook: random error")))
 	})
 
 	It("should use all modprobe settings", func() {
@@ -183,18 +452,205 @@ var _ = Describe("worker_LoadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), "a", "b", "c", moduleName, "key0=value0", "key1=value1")
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), "a", "b", "c", moduleName, "key0=value0", "key1=value1"),
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, dirName), moduleName),
+			mc.EXPECT().ModuleParameters(moduleName),
+		)
+
+		_, err := w.LoadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("worker_PlanLoadKmod", func() {
+	var (
+		fh *utils.MockFSHelper
+		mc *MockModuleChecker
+		w  Worker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		fh = utils.NewMockFSHelper(ctrl)
+		mc = NewMockModuleChecker(ctrl)
+		w = NewWorker(nil, mc, nil, fh, nil, GinkgoLogr)
+	})
+
+	const (
+		dirName    = "/dir"
+		moduleName = "test"
+	)
+
+	It("should return an error if the module is not compatible with the running kernel", func() {
+		cfg := v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName).Return(ErrKernelMismatch)
+
+		_, err := w.PlanLoadKmod(&cfg, "")
+		Expect(err).To(MatchError(ErrKernelMismatch))
+	})
+
+	It("should return the operations without running them", func() {
+		hook := &v1beta1.ModprobeHook{Command: []string{"/usr/bin/pre-load.sh", "--verbose"}}
+
+		cfg := v1beta1.ModuleConfig{
+			InTreeModulesToRemove: []string{"intree1", "intree2"},
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				Args:       &v1beta1.ModprobeArgs{Load: []string{"--first-time"}},
+				Parameters: []string{"a=1"},
+				Hooks:      &v1beta1.ModprobeHooks{PreLoad: hook},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().CheckModule(filepath.Join(sharedFilesDir, dirName), moduleName),
+			fh.EXPECT().FileExists("/lib/modules", "^intree1.ko").Return(true, nil),
+			fh.EXPECT().FileExists("/lib/modules", "^intree2.ko").Return(false, nil),
+		)
+
+		plan, err := w.PlanLoadKmod(&cfg, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan).To(Equal(&LoadPlan{
+			InTreeModulesToRemove: []string{"intree1"},
+			Hooks:                 v1beta1.ModprobeHooks{PreLoad: hook},
+			ModprobeArgs:          []string{"-vd", "/tmp/dir", "--first-time", moduleName, "a=1"},
+		}))
+
+		var sb strings.Builder
+
+		Expect(
+			plan.Print(&sb),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(sb.String()).To(Equal(`modprobe -rv intree1
+hook preLoad: /tmp/usr/bin/pre-load.sh --verbose
+modprobe -vd /tmp/dir --first-time test a=1
+`))
+	})
+
+	It("should list the firmware files to copy", func() {
+		const firmwareDir = "/firmware"
+
+		cfg := v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				RawArgs:      &v1beta1.ModprobeArgs{Load: []string{"a", "b"}},
+				FirmwarePath: firmwareDir,
+			},
+		}
+
+		plan, err := w.PlanLoadKmod(&cfg, "/var/lib/firmware")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.FirmwareSrcDir).To(Equal(filepath.Join(sharedFilesDir, firmwareDir)))
+		Expect(plan.FirmwareDstDir).To(Equal("/var/lib/firmware"))
+		Expect(plan.ModprobeArgs).To(Equal([]string{"a", "b"}))
+	})
+})
+
+var _ = Describe("worker_KmodStatus", func() {
+	var (
+		mc *MockModuleChecker
+		w  Worker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mc = NewMockModuleChecker(ctrl)
+		w = NewWorker(nil, mc, nil, nil, nil, GinkgoLogr)
+	})
+
+	It("should return the state of every module once", func() {
+		cfg := v1beta1.ModuleConfig{
+			InTreeModulesToRemove: []string{"intree"},
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:          "a",
+				ModulesLoadingOrder: []string{"a", "b"},
+			},
+		}
+
+		stateA := &ModuleState{Name: "a", Loaded: true, RefCount: 1, Holders: []string{"b"}}
+
+		gomock.InOrder(
+			mc.EXPECT().ModuleState("a").Return(stateA, nil),
+			mc.EXPECT().ModuleState("b").Return(&ModuleState{Name: "b", Loaded: true}, nil),
+			mc.EXPECT().ModuleState("intree").Return(&ModuleState{Name: "intree"}, nil),
+		)
+
+		Expect(
+			w.KmodStatus(&cfg, ""),
+		).To(
+			Equal(&HostStatus{
+				Modules: []ModuleState{*stateA, {Name: "b", Loaded: true}, {Name: "intree"}},
+			}),
+		)
+	})
+
+	It("should return an error if a module state cannot be read", func() {
+		cfg := v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{ModuleName: "a"},
+		}
+
+		mc.EXPECT().ModuleState("a").Return(nil, errors.New("random error"))
+
+		_, err := w.KmodStatus(&cfg, "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should report which firmware files are present", func() {
+		imageDir, err := os.MkdirTemp(sharedFilesDir, "firmware")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, imageDir)
+
+		hostDir := GinkgoT().TempDir()
+
+		Expect(
+			os.WriteFile(filepath.Join(imageDir, "present.bin"), []byte("a"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(imageDir, "missing.bin"), []byte("b"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
 
 		Expect(
-			w.LoadKmod(ctx, &cfg, ""),
+			os.WriteFile(filepath.Join(hostDir, "present.bin"), []byte("a"), 0644),
 		).NotTo(
 			HaveOccurred(),
 		)
+
+		cfg := v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   "a",
+				FirmwarePath: filepath.Base(imageDir),
+			},
+		}
+
+		mc.EXPECT().ModuleState("a").Return(&ModuleState{Name: "a"}, nil)
+
+		hs, err := w.KmodStatus(&cfg, hostDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hs.Firmware).To(ConsistOf(
+			FirmwareFileStatus{Path: "present.bin", Present: true},
+			FirmwareFileStatus{Path: "missing.bin", Present: false},
+		))
 	})
 })
 
 var _ = Describe("worker_SetFirmwareClassPath", func() {
-	w := NewWorker(nil, nil, GinkgoLogr)
+	w := NewWorker(nil, nil, nil, nil, nil, GinkgoLogr)
 
 	AfterEach(func() {
 		firmwareClassPathLocation = FirmwareClassPathLocation
@@ -240,10 +696,122 @@ var _ = Describe("worker_SetFirmwareClassPath", func() {
 	)
 })
 
+var _ = Describe("worker_SetKmodParameters", func() {
+	var (
+		mc        *MockModuleChecker
+		w         Worker
+		paramsDir string
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mc = NewMockModuleChecker(ctrl)
+		w = NewWorker(nil, mc, nil, nil, nil, GinkgoLogr)
+		sysModuleDir = GinkgoT().TempDir()
+		paramsDir = filepath.Join(sysModuleDir, "kmm_a", "parameters")
+
+		Expect(
+			os.MkdirAll(paramsDir, 0755),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(paramsDir, "debug"), []byte("0\n"), 0644),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.WriteFile(filepath.Join(paramsDir, "queues"), []byte("4\n"), 0444),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	AfterEach(func() {
+		sysModuleDir = "/sys/module"
+	})
+
+	ctx := context.TODO()
+
+	cfgWithParams := func(params ...string) *v1beta1.ModuleConfig {
+		return &v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: "kmm-a",
+				DirName:    "/dir",
+				Parameters: params,
+			},
+		}
+	}
+
+	It("should write the parameters and record them", func() {
+		params := map[string]string{"debug": "1", "queues": "4"}
+
+		gomock.InOrder(
+			mc.EXPECT().LoadedModules(filepath.Join(sharedFilesDir, "/dir"), "kmm-a"),
+			mc.EXPECT().ModuleParameters("kmm-a").Return(params, nil),
+		)
+
+		res, err := w.SetKmodParameters(ctx, cfgWithParams("debug=1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Parameters).To(Equal(params))
+		Expect(res.Error).To(BeNil())
+
+		Expect(
+			os.ReadFile(filepath.Join(paramsDir, "debug")),
+		).To(
+			Equal([]byte("1")),
+		)
+	})
+
+	It("should not write anything if a parameter is read-only", func() {
+		res, err := w.SetKmodParameters(ctx, cfgWithParams("debug=1", "queues=8"))
+		Expect(err).To(MatchError(ErrParamNotWritable))
+		Expect(res.Error.Reason).To(Equal(v1beta1.WorkerFailureReasonParameterNotWritable))
+
+		Expect(
+			os.ReadFile(filepath.Join(paramsDir, "debug")),
+		).To(
+			Equal([]byte("0\n")),
+		)
+	})
+
+	It("should return an error if a parameter does not exist", func() {
+		_, err := w.SetKmodParameters(ctx, cfgWithParams("missing=1"))
+		Expect(err).To(MatchError(ErrParamNotWritable))
+	})
+
+	It("should return an error if a parameter is malformed", func() {
+		_, err := w.SetKmodParameters(ctx, cfgWithParams("debug"))
+		Expect(err).To(HaveOccurred())
+		Expect(err).NotTo(MatchError(ErrParamNotWritable))
+	})
+
+	It("should return an error if the module is not loaded", func() {
+		cfg := cfgWithParams("debug=1")
+		cfg.Modprobe.ModuleName = "kmm_b"
+
+		_, err := w.SetKmodParameters(ctx, cfg)
+		Expect(err).To(MatchError(ErrModuleNotLoaded))
+	})
+
+	It("should return an error if rawArgs are used", func() {
+		cfg := cfgWithParams("debug=1")
+		cfg.Modprobe.RawArgs = &v1beta1.ModprobeArgs{Load: []string{"kmm_a", "debug=1"}}
+
+		_, err := w.SetKmodParameters(ctx, cfg)
+		Expect(err).To(MatchError(ErrParamNotWritable))
+	})
+})
+
 var _ = Describe("worker_UnloadKmod", func() {
 	var (
 		mr       *MockModprobeRunner
 		fh       *utils.MockFSHelper
+		fm       *MockFirmwareManager
+		hr       *MockHookRunner
+		mc       *MockModuleChecker
 		w        Worker
 		imageDir string
 		hostDir  string
@@ -253,7 +821,10 @@ var _ = Describe("worker_UnloadKmod", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		mr = NewMockModprobeRunner(ctrl)
 		fh = utils.NewMockFSHelper(ctrl)
-		w = NewWorker(mr, fh, GinkgoLogr)
+		fm = NewMockFirmwareManager(ctrl)
+		hr = NewMockHookRunner(ctrl)
+		mc = NewMockModuleChecker(ctrl)
+		w = NewWorker(mr, mc, hr, fh, fm, GinkgoLogr)
 		var err error
 		imageDir, err = os.MkdirTemp("", "imageDir")
 		Expect(err).Should(BeNil())
@@ -285,13 +856,13 @@ var _ = Describe("worker_UnloadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName).Return(errors.New("random error"))
-
-		Expect(
-			w.UnloadKmod(ctx, &cfg, ""),
-		).To(
-			HaveOccurred(),
+		gomock.InOrder(
+			mc.EXPECT().ModuleUsage(moduleName),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName).Return(errors.New("random error")),
 		)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(HaveOccurred())
 	})
 
 	It("should use rawArgs if they are defined", func() {
@@ -306,11 +877,8 @@ var _ = Describe("worker_UnloadKmod", func() {
 
 		mr.EXPECT().Run(ctx, ToInterfaceSlice(rawArgs)...)
 
-		Expect(
-			w.UnloadKmod(ctx, &cfg, ""),
-		).NotTo(
-			HaveOccurred(),
-		)
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should use all modprobe settings", func() {
@@ -323,13 +891,134 @@ var _ = Describe("worker_UnloadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), "a", "b", "c", moduleName)
+		gomock.InOrder(
+			mc.EXPECT().ModuleUsage(moduleName),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), "a", "b", "c", moduleName),
+		)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
 
-		Expect(
-			w.UnloadKmod(ctx, &cfg, ""),
-		).NotTo(
-			HaveOccurred(),
+	It("should run the unload hooks around modprobe", func() {
+		preUnload := &v1beta1.ModprobeHook{Command: []string{"/pre-unload.sh"}}
+		postUnload := &v1beta1.ModprobeHook{Command: []string{"/post-unload.sh"}}
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				Hooks: &v1beta1.ModprobeHooks{
+					PreLoad:    &v1beta1.ModprobeHook{Command: []string{"/pre-load.sh"}},
+					PreUnload:  preUnload,
+					PostUnload: postUnload,
+				},
+			},
+		}
+
+		gomock.InOrder(
+			hr.EXPECT().RunHook(ctx, hookPreUnload, preUnload),
+			mc.EXPECT().ModuleUsage(moduleName),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			hr.EXPECT().RunHook(ctx, hookPostUnload, postUnload),
+		)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not unload a module that is in use with the Fail policy", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		mc.EXPECT().ModuleUsage(moduleName).Return(&ModuleUsage{RefCount: 2, Holders: []string{"holder"}}, nil)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(MatchError(ErrModuleBusy))
+		Expect(err.Error()).To(ContainSubstring("reference count 2, holders [holder]"))
+	})
+
+	It("should wait for the module to be released with the Wait policy", func() {
+		moduleUsagePollInterval = time.Millisecond
+		DeferCleanup(func() { moduleUsagePollInterval = 2 * time.Second })
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   moduleName,
+				DirName:      dirName,
+				UnloadPolicy: &v1beta1.UnloadPolicy{Type: v1beta1.UnloadPolicyWait, TimeoutSeconds: 10},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().ModuleUsage(moduleName).Return(&ModuleUsage{RefCount: 1}, nil).Times(2),
+			mc.EXPECT().ModuleUsage(moduleName).Return(&ModuleUsage{}, nil),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName),
 		)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the module is still in use after the timeout", func() {
+		moduleUsagePollInterval = 100 * time.Millisecond
+		DeferCleanup(func() { moduleUsagePollInterval = 2 * time.Second })
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   moduleName,
+				DirName:      dirName,
+				UnloadPolicy: &v1beta1.UnloadPolicy{Type: v1beta1.UnloadPolicyWait, TimeoutSeconds: 1},
+			},
+		}
+
+		mc.EXPECT().ModuleUsage(moduleName).Return(&ModuleUsage{RefCount: 1}, nil).MinTimes(2)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(MatchError(ErrModuleBusy))
+	})
+
+	It("should force the unload of a module in use with the Force policy", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   moduleName,
+				DirName:      dirName,
+				UnloadPolicy: &v1beta1.UnloadPolicy{Type: v1beta1.UnloadPolicyForce},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().ModuleUsage(moduleName).Return(&ModuleUsage{RefCount: 1}, nil),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), "--force", moduleName),
+		)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not force the unload of a module that other modules depend on", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   moduleName,
+				DirName:      dirName,
+				UnloadPolicy: &v1beta1.UnloadPolicy{Type: v1beta1.UnloadPolicyForce},
+			},
+		}
+
+		mc.EXPECT().ModuleUsage(moduleName).Return(&ModuleUsage{RefCount: 1, Holders: []string{"holder"}}, nil)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(MatchError(ErrModuleBusy))
 	})
 
 	It("should remove all firmware file only", func() {
@@ -342,14 +1031,88 @@ var _ = Describe("worker_UnloadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName)
-		fh.EXPECT().RemoveSrcFilesFromDst(filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath), hostDir).Return(nil)
+		gomock.InOrder(
+			mc.EXPECT().ModuleUsage(moduleName),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			fm.EXPECT().Remove("ns/name", filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath), hostDir),
+		)
 
-		Expect(
-			w.UnloadKmod(ctx, &cfg, hostDir),
-		).NotTo(
-			HaveOccurred(),
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{FirmwareMountPath: hostDir, FirmwareOwner: "ns/name"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should disable a livepatch before unloading it", func() {
+		sysLivepatchDir = GinkgoT().TempDir()
+		DeferCleanup(func() { sysLivepatchDir = "/sys/kernel/livepatch" })
+
+		setLivepatchState(moduleName, "1", "0")
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				Livepatch:  &v1beta1.Livepatch{},
+			},
+		}
+
+		gomock.InOrder(
+			mc.EXPECT().ModuleUsage(moduleName).Do(func(_ string) {
+				Expect(os.ReadFile(filepath.Join(sysLivepatchDir, moduleName, "enabled"))).To(Equal([]byte("0")))
+			}),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName),
+		)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not unload a livepatch whose transition does not complete", func() {
+		sysLivepatchDir = GinkgoT().TempDir()
+		livepatchPollInterval = time.Millisecond
+		DeferCleanup(func() {
+			sysLivepatchDir = "/sys/kernel/livepatch"
+			livepatchPollInterval = time.Second
+		})
+
+		setLivepatchState(moduleName, "0", "1")
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				Livepatch:  &v1beta1.Livepatch{TransitionTimeoutSeconds: 1},
+			},
+		}
+
+		res, err := w.UnloadKmod(ctx, &cfg, KmodOptions{})
+		Expect(err).To(MatchError(ErrLivepatchTransition))
+		Expect(res.Error.Reason).To(Equal(v1beta1.WorkerFailureReasonLivepatchTransition))
+	})
+
+	It("should remove the in-tree modules blacklist", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage:         imageName,
+			InTreeModulesToRemove:  []string{"intree1"},
+			BlacklistInTreeModules: true,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		blacklistPath := filepath.Join(GinkgoT().TempDir(), "kmm-ns-name.conf")
+		Expect(os.WriteFile(blacklistPath, []byte("blacklist intree1\n"), 0644)).To(Succeed())
+
+		gomock.InOrder(
+			mc.EXPECT().ModuleUsage(moduleName),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName),
 		)
+
+		_, err := w.UnloadKmod(ctx, &cfg, KmodOptions{BlacklistPath: blacklistPath})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blacklistPath).NotTo(BeAnExistingFile())
 	})
 })
 